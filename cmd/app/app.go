@@ -1,73 +1,178 @@
 package app
 
 import (
+	"context"
 	"go-fiber-api/internal/core/config"
+	"go-fiber-api/internal/core/health"
+	"go-fiber-api/internal/core/lifecycle"
+	"go-fiber-api/internal/core/lock"
 	"go-fiber-api/internal/core/storage/db"
+	"go-fiber-api/internal/wrapper/jwtx"
 	"go-fiber-api/internal/wrapper/logx"
+	"go-fiber-api/internal/wrapper/metrics"
+	"go-fiber-api/internal/wrapper/redis"
+	"go-fiber-api/internal/wrapper/tracing"
 
 	apikey_middleware "go-fiber-api/internal/core/middleware/apikey"
+	auth_middleware "go-fiber-api/internal/core/middleware/auth"
+	"go-fiber-api/internal/core/middleware/authz"
 	"go-fiber-api/internal/core/middleware/cache"
+	cors_middleware "go-fiber-api/internal/core/middleware/cors"
+	"go-fiber-api/internal/core/middleware/logger"
+	metrics_middleware "go-fiber-api/internal/core/middleware/metrics"
+	"go-fiber-api/internal/core/middleware/ratelimit"
+	tracing_middleware "go-fiber-api/internal/core/middleware/tracing"
 
 	"go-fiber-api/internal/feature/apikey"
+	"go-fiber-api/internal/feature/auth"
+	"go-fiber-api/internal/feature/loglevel"
+	"go-fiber-api/internal/feature/oidc"
+	"go-fiber-api/internal/feature/rbac"
 	"go-fiber-api/internal/feature/user"
 
 	"go-fiber-api/toolkit/errorhandler"
-	"sync"
+	"sync/atomic"
 
+	"github.com/go-resty/resty/v2"
 	"github.com/gofiber/fiber/v2"
-	"github.com/gofiber/fiber/v2/middleware/cors"
 	"github.com/gofiber/fiber/v2/middleware/helmet"
 	"github.com/gofiber/fiber/v2/middleware/idempotency"
 	"github.com/gofiber/fiber/v2/middleware/requestid"
-	"github.com/sirupsen/logrus"
 )
 
 type Application struct {
-	Config           *config.Configuration
-	LogX             *logx.LogX
-	Server           *fiber.App
-	DBClient         db.Client
-	UserHandler      user.Handler
-	CacheMiddleware  cache.CacheMiddleware
-	APIKeyHandler    apikey.Handler
-	APIKeyMiddleware apikey_middleware.Middleware
+	Config              *config.Configuration
+	LogX                *logx.LogX
+	Metrics             *metrics.Metrics
+	Tracing             *tracing.Tracing
+	HTTPClient          *resty.Client
+	Keyring             *jwtx.Keyring
+	Server              *fiber.App
+	DBClient            db.Client
+	RedisClient         redis.Client
+	Locker              lock.Locker
+	UserHandler         user.Handler
+	CacheMiddleware     cache.CacheMiddleware
+	CORSMiddleware      cors_middleware.Middleware
+	RateLimitMiddleware ratelimit.Middleware
+	APIKeyHandler       apikey.Handler
+	APIKeyMiddleware    apikey_middleware.Middleware
+	AuthHandler         auth.Handler
+	TokenCleanup        *auth.Cleanup
+	AuthMiddleware      auth_middleware.Middleware
+	OIDCHandler         oidc.Handler
+	RBACHandler         rbac.Handler
+	AuthzMiddleware     authz.Middleware
+	LoggerMiddleware    logger.Middleware
+	LogLevelHandler     loglevel.Handler
+	MetricsMiddleware   metrics_middleware.Middleware
+	TracingMiddleware   tracing_middleware.Middleware
+	HealthHandler       health.Handler
+	HealthRegistry      *health.Registry
+	Lifecycle           *lifecycle.Lifecycle
+
+	// Set by Listen when TLS is enabled, read by Shutdown from another
+	// goroutine.
+	redirectServer atomic.Pointer[fiber.App]
 }
 
-var (
-	app     = &Application{}
-	appOnce sync.Once
-)
+// New builds an Application from the config of args. Whatever its providers
+// opened is closed again when one of them fails; once built, Shutdown stops it.
+func New(client *resty.Client, args config.Args) (*Application, error) {
+	app, _, err := build(client, args)
+	return app, err
+}
 
 func Provide(
 	cfg *config.Configuration,
 	log *logx.LogX,
+	m *metrics.Metrics,
+	t *tracing.Tracing,
+	client *resty.Client,
+	keyring *jwtx.Keyring,
 	dbClient db.Client,
+	redisClient redis.Client,
+	locker lock.Locker,
 	userHandler user.Handler,
 	cacheMiddleware cache.CacheMiddleware,
+	corsMiddleware cors_middleware.Middleware,
+	rateLimitMiddleware ratelimit.Middleware,
 	apiKeyHandler apikey.Handler,
 	apiKeyMiddleware apikey_middleware.Middleware,
+	authHandler auth.Handler,
+	tokenCleanup *auth.Cleanup,
+	authMiddleware auth_middleware.Middleware,
+	oidcHandler oidc.Handler,
+	rbacHandler rbac.Handler,
+	authzMiddleware authz.Middleware,
+	loggerMiddleware logger.Middleware,
+	logLevelHandler loglevel.Handler,
+	metricsMiddleware metrics_middleware.Middleware,
+	tracingMiddleware tracing_middleware.Middleware,
+	healthHandler health.Handler,
+	healthRegistry *health.Registry,
+	lc *lifecycle.Lifecycle,
 ) *Application {
-	appOnce.Do(func() {
-		app = &Application{
-			Config:           cfg,
-			LogX:             log,
-			Server:           getServer(log),
-			DBClient:         dbClient,
-			UserHandler:      userHandler,
-			CacheMiddleware:  cacheMiddleware,
-			APIKeyHandler:    apiKeyHandler,
-			APIKeyMiddleware: apiKeyMiddleware,
-		}
-		registerHandler(app)
-	})
+	// Outbound calls join the trace of the request they are made for.
+	t.InstrumentResty(client)
+
+	app := &Application{
+		Config:              cfg,
+		LogX:                log,
+		Metrics:             m,
+		Tracing:             t,
+		HTTPClient:          client,
+		Keyring:             keyring,
+		Server:              getServer(cfg, m, keyring, healthHandler, tracingMiddleware, metricsMiddleware, loggerMiddleware),
+		DBClient:            dbClient,
+		RedisClient:         redisClient,
+		Locker:              locker,
+		UserHandler:         userHandler,
+		CacheMiddleware:     cacheMiddleware,
+		CORSMiddleware:      corsMiddleware,
+		RateLimitMiddleware: rateLimitMiddleware,
+		APIKeyHandler:       apiKeyHandler,
+		APIKeyMiddleware:    apiKeyMiddleware,
+		AuthHandler:         authHandler,
+		TokenCleanup:        tokenCleanup,
+		AuthMiddleware:      authMiddleware,
+		OIDCHandler:         oidcHandler,
+		RBACHandler:         rbacHandler,
+		AuthzMiddleware:     authzMiddleware,
+		LoggerMiddleware:    loggerMiddleware,
+		LogLevelHandler:     logLevelHandler,
+		MetricsMiddleware:   metricsMiddleware,
+		TracingMiddleware:   tracingMiddleware,
+		HealthHandler:       healthHandler,
+		HealthRegistry:      healthRegistry,
+		Lifecycle:           lc,
+	}
+	registerHandler(app)
 
 	return app
 }
 
-func getServer(log *logx.LogX) *fiber.App {
+// Start runs the lifecycle start hooks, before the application listens.
+func (a *Application) Start(ctx context.Context) error {
+	return a.Lifecycle.Start(ctx)
+}
+
+func getServer(
+	cfg *config.Configuration,
+	m *metrics.Metrics,
+	keyring *jwtx.Keyring,
+	healthHandler health.Handler,
+	tracingMiddleware tracing_middleware.Middleware,
+	metricsMiddleware metrics_middleware.Middleware,
+	loggerMiddleware logger.Middleware,
+) *fiber.App {
 	server := fiber.New(
 		fiber.Config{
-			ErrorHandler: errorhandler.Handler(),
+			// Outside of dev mode, the causes of errors are only logged.
+			ErrorHandler: errorhandler.Handler(errorhandler.Config{
+				DevMode:            cfg.DevMode,
+				ProblemTypeBaseURL: cfg.ProblemTypeBaseURL,
+			}),
 		},
 	)
 
@@ -80,40 +185,82 @@ func getServer(log *logx.LogX) *fiber.App {
 
 	server.Use(requestid.New())
 
-	server.Use(func(c *fiber.Ctx) error {
-		log.WithFields(logrus.Fields{
-			"method": c.Method(),
-			"path":   c.Path(),
-			"ip":     c.IP(),
-		}).Info("Incoming request")
-		return c.Next()
-	})
+	// Both before the request logger: it logs the trace ID of the request
+	// span and renders errors, so they are recorded with their final status.
+	server.Use(tracingMiddleware.RequestTracing())
+	server.Use(metricsMiddleware.RequestMetrics())
+
+	server.Use(loggerMiddleware.RequestLogger())
 
 	// == Routes ==
 
-	server.Get("/healthz", func(c *fiber.Ctx) error {
-		return c.SendString("Hello, World!")
-	})
+	// Registered ahead of the cache middleware, probes are never cached.
+	server.Get("/livez", healthHandler.Livez)
+	server.Get("/readyz", healthHandler.Readyz)
+	// Deprecated: kept for existing probes, use /livez.
+	server.Get("/healthz", healthHandler.Livez)
+
+	server.Get("/metrics", m.Handler())
+
+	// Public keys checking the tokens of the keyring.
+	server.Get("/.well-known/jwks.json", keyring.Handler())
 
 	return server
 }
 
 func registerHandler(app *Application) {
-	app.Server.Use(app.CacheMiddleware.RedisCacheMiddleware(60))
+	// Both ahead of the cache middleware, cached responses count towards
+	// the rate limit and get CORS headers too.
+	app.Server.Use(app.RateLimitMiddleware.RateLimit())
+	app.Server.Use(app.CORSMiddleware.CORS())
 
-	app.Server.Use(cors.New(cors.Config{
-		AllowOrigins: app.Config.CorsAllowedOrigins, // Allow requests from frontend
-		AllowHeaders: app.Config.CorsAllowedHeaders,
-	}))
-	root := app.Server.Group("")
+	// Ahead of the cache middleware too, a session is never replayed.
+	app.Server.Get("/auth/oidc/:provider", app.OIDCHandler.Login)
+	app.Server.Get("/auth/oidc/:provider/callback", app.OIDCHandler.Callback)
 
-	// Middleware: Set user access token to context
-	root.Use(func(c *fiber.Ctx) error {
-		// tk := c.Get(fiber.HeaderAuthorization)
-		// c.Context().SetUserValue(constants.ContextUserAccessTokenKey, tk)
-		return c.Next()
+	// Machine clients, authenticated by API key or client certificate, never
+	// share cached responses either.
+	service := app.Server.Group("service")
+	service.Use(app.APIKeyMiddleware.Validate())
+	service.Get("", func(ctx *fiber.Ctx) error {
+		return ctx.SendString("hello, world")
 	})
 
+	// With an API key, to appoint the first administrators: closed once a
+	// user has the admin permission.
+	bootstrap := app.Server.Group("bootstrap")
+	bootstrap.Use(app.APIKeyMiddleware.Validate())
+	bootstrap.Use(app.AuthzMiddleware.Bootstrap(rbac.PermissionAdmin))
+	registerRBAC(bootstrap, app.RBACHandler)
+
+	admin := app.Server.Group("admin")
+	admin.Use(app.AuthMiddleware.Authenticate())
+	admin.Use(app.AuthzMiddleware.Require(rbac.PermissionAdmin))
+	admin.Get("log-level", app.LogLevelHandler.Get)
+	admin.Put("log-level", app.LogLevelHandler.Update)
+	admin.Delete("log-level", app.LogLevelHandler.Reset)
+	registerRBAC(admin, app.RBACHandler)
+
+	app.Server.Use(app.CacheMiddleware.RedisCacheMiddleware())
+
+	root := app.Server.Group("")
+
+	authGroup := root.Group("auth")
+	authGroup.Post("register", app.AuthHandler.Register)
+	authGroup.Post("login", app.AuthHandler.Login)
+	authGroup.Post("refresh", app.AuthHandler.Refresh)
+	authGroup.Post("logout", app.AuthHandler.Logout)
+
+	users := root.Group("users")
+	users.Use(app.AuthMiddleware.Authenticate())
+	users.Get("me", app.UserHandler.Me)
+
+	// Role management of the admin UI
+	roles := root.Group("rbac")
+	roles.Use(app.AuthMiddleware.Authenticate())
+	roles.Use(app.AuthzMiddleware.Require(rbac.PermissionRolesManage))
+	registerRBAC(roles, app.RBACHandler)
+
 	// games := root.Group("users")
 	// games.Get("", app.UserHandler)
 	// games.Get(":id", app.UserHandler.GetByID)
@@ -121,12 +268,6 @@ func registerHandler(app *Application) {
 	// games.Put(":id", app.UserHandler.Update)
 	// games.Delete(":id", app.UserHandler.Delete)
 
-	service := root.Group("service")
-	service.Use(app.APIKeyMiddleware.Validate())
-	service.Get("", func(ctx *fiber.Ctx) error {
-		return ctx.SendString("hello, world")
-	})
-
 	// adminApi.Get("/me", func(c *fiber.Ctx) error {
 	// 	return c.JSON(fiber.Map{
 	// 		"data": fiber.Map{
@@ -138,7 +279,12 @@ func registerHandler(app *Application) {
 	// })
 }
 
-// Only for test
-func Reset() {
-	appOnce = sync.Once{}
+// registerRBAC registers the role management API on router.
+func registerRBAC(router fiber.Router, h rbac.Handler) {
+	router.Get("roles", h.FindRoles)
+	router.Post("roles", h.CreateRole)
+	router.Put("roles/:id", h.UpdateRole)
+	router.Delete("roles/:id", h.DeleteRole)
+	router.Get("users/:id/roles", h.FindUserRoles)
+	router.Put("users/:id/roles", h.SetUserRoles)
 }
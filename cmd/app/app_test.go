@@ -0,0 +1,465 @@
+package app_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"go-fiber-api/cmd/app"
+	"go-fiber-api/internal/core/config"
+	"go-fiber-api/internal/core/lock"
+	"go-fiber-api/internal/core/model"
+	"go-fiber-api/internal/core/response"
+	"go-fiber-api/internal/feature/oidc/oidctest"
+	"go-fiber-api/internal/feature/rbac"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestMain(m *testing.M) {
+	// Shared by every application of the package: each of them still gets a
+	// private in-memory database and cache.
+	env := map[string]string{
+		"ENV_FILE_PATH":        "",
+		"LOG_LEVEL":            "error",
+		"DB_DRIVER":            "sqlite",
+		"DB_NAME":              ":memory:",
+		"CACHE_BACKEND":        "memory",
+		"REDIS_HOST":           "localhost",
+		"REDIS_PORT":           "6379",
+		"REDIS_DB":             "10",
+		"SHUTDOWN_DRAIN_DELAY": "0",
+		"SHUTDOWN_TIMEOUT":     "1",
+		"SECRET_KEY":           "secret",
+	}
+	for key, value := range env {
+		os.Setenv(key, value)
+	}
+
+	os.Exit(m.Run())
+}
+
+func TestNew_Parallel(t *testing.T) {
+	var (
+		mu   sync.Mutex
+		apps []*app.Application
+	)
+
+	t.Run("apps", func(t *testing.T) {
+		for i := range 3 {
+			t.Run(fmt.Sprintf("app_%d", i), func(t *testing.T) {
+				t.Parallel()
+				ctx := context.Background()
+
+				a, err := app.New(resty.New(), nil)
+				if !assert.NoError(t, err) {
+					return
+				}
+				assert.NoError(t, a.Start(ctx))
+
+				resp, err := a.Server.Test(httptest.NewRequest(fiber.MethodGet, "/readyz", nil))
+				assert.NoError(t, err)
+				assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+				// Rows written by one application are not seen by the others.
+				assert.NoError(t, a.DBClient.Create(&model.User{Username: fmt.Sprintf("user-%d", i)}).Error)
+				var count int64
+				assert.NoError(t, a.DBClient.Model(&model.User{}).Count(&count).Error)
+				assert.Equal(t, int64(1), count)
+
+				mu.Lock()
+				apps = append(apps, a)
+				mu.Unlock()
+
+				assert.NoError(t, a.Shutdown(ctx))
+
+				sqlDB, err := a.DBClient.DB()
+				assert.NoError(t, err)
+				assert.Error(t, sqlDB.Ping(), "db is closed by shutdown")
+				assert.Error(t, a.RedisClient.Ping(ctx), "redis is closed by shutdown")
+			})
+		}
+	})
+
+	assert.Len(t, apps, 3)
+	for i, a := range apps {
+		for _, b := range apps[i+1:] {
+			assert.NotSame(t, a, b)
+			assert.NotSame(t, a.Config, b.Config)
+			assert.NotSame(t, a.Server, b.Server)
+			assert.NotSame(t, a.Metrics, b.Metrics)
+			assert.NotSame(t, a.Lifecycle, b.Lifecycle)
+			assert.NotSame(t, a.HealthRegistry, b.HealthRegistry)
+		}
+	}
+}
+
+func TestNew_Failed(t *testing.T) {
+	// The local tier of the cache keeps a goroutine subscribed to Redis.
+	t.Setenv("CACHE_LOCAL_SIZE", "10")
+	t.Setenv("CACHE_LOCAL_TTL", "10")
+	t.Setenv("OIDC_PROVIDERS", "corp=sso.example.com")
+	t.Setenv("OIDC_CLIENT_IDS", "corp=api")
+	t.Setenv("OIDC_REDIRECT_URL", "https://api.example.com/auth/oidc")
+	goroutines := runtime.NumGoroutine()
+
+	// test logic
+	// Fails once the database and Redis are open, which are closed again.
+	_, err := app.New(resty.New(), nil)
+	assert.ErrorContains(t, err, `OIDC_PROVIDERS issuer of provider "corp" must be a URL`)
+	// Polled here, assert.Eventually runs goroutines of its own.
+	for deadline := time.Now().Add(time.Second); runtime.NumGoroutine() > goroutines && time.Now().Before(deadline); {
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.LessOrEqual(t, runtime.NumGoroutine(), goroutines, "the clients of the database and Redis are closed")
+}
+
+func TestNew_ReloadConfig(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "app.env")
+	assert.NoError(t, os.WriteFile(path, []byte("CORS_ALLOWED_ORIGINS=https://a.example.com\n"), 0o600))
+
+	a, err := app.New(resty.New(), config.Args{"--config", path})
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.NoError(t, a.Start(ctx))
+	defer a.Shutdown(ctx)
+
+	allowed := func(origin string) bool {
+		req := httptest.NewRequest(fiber.MethodGet, "/service", nil)
+		req.Header.Set(fiber.HeaderOrigin, origin)
+
+		resp, err := a.Server.Test(req)
+		return err == nil && resp.Header.Get(fiber.HeaderAccessControlAllowOrigin) == origin
+	}
+	assert.True(t, allowed("https://a.example.com"))
+	assert.False(t, allowed("https://b.example.com"))
+
+	// test logic
+	assert.NoError(t, os.WriteFile(path, []byte("CORS_ALLOWED_ORIGINS=https://b.example.com\n"), 0o600))
+	assert.Eventually(t, func() bool {
+		return allowed("https://b.example.com")
+	}, 5*time.Second, 20*time.Millisecond)
+	assert.False(t, allowed("https://a.example.com"))
+}
+
+func TestNew_MachineRoutesNotCached(t *testing.T) {
+	ctx := context.Background()
+	a, err := app.New(resty.New(), nil)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.NoError(t, a.Start(ctx))
+	defer a.Shutdown(ctx)
+
+	// test logic
+	for _, path := range []string{"/service", "/admin/log-level", "/admin/roles", "/bootstrap/roles"} {
+		resp, err := a.Server.Test(httptest.NewRequest(fiber.MethodGet, path, nil))
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusUnauthorized, resp.StatusCode, path)
+		assert.Empty(t, resp.Header.Get("X-Cache"), "%s is served ahead of the cache", path)
+	}
+}
+
+func TestNew_Locker(t *testing.T) {
+	ctx := context.Background()
+	a, err := app.New(resty.New(), nil)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.NoError(t, a.Start(ctx))
+	defer a.Shutdown(ctx)
+
+	// test logic
+	key := "test-app-lock-" + uuid.NewString()
+	lease, err := a.Locker.Acquire(ctx, key, time.Second)
+	if !assert.NoError(t, err) {
+		return
+	}
+	_, err = a.Locker.Acquire(ctx, key, time.Second)
+	assert.ErrorIs(t, err, lock.ErrNotAcquired)
+	assert.NoError(t, lease.Release(ctx))
+}
+
+func TestNew_Auth(t *testing.T) {
+	ctx := context.Background()
+	a, err := app.New(resty.New(), nil)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.NoError(t, a.Start(ctx))
+	defer a.Shutdown(ctx)
+
+	call := func(method, path, token string, body any) (int, response.ResponseDTO) {
+		var reader io.Reader
+		if body != nil {
+			b, err := json.Marshal(body)
+			assert.NoError(t, err)
+			reader = bytes.NewReader(b)
+		}
+
+		req := httptest.NewRequest(method, path, reader)
+		req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+		if token != "" {
+			req.Header.Set(fiber.HeaderAuthorization, "Bearer "+token)
+		}
+
+		resp, err := a.Server.Test(req)
+		if !assert.NoError(t, err) {
+			return 0, response.ResponseDTO{}
+		}
+
+		var res response.ResponseDTO
+		_ = json.NewDecoder(resp.Body).Decode(&res)
+		return resp.StatusCode, res
+	}
+	tokens := func(res response.ResponseDTO) model.TokenDTO {
+		var token model.TokenDTO
+		b, _ := json.Marshal(res.Data)
+		assert.NoError(t, json.Unmarshal(b, &token))
+		return token
+	}
+
+	// test logic
+	status, _ := call(fiber.MethodPost, "/auth/register", "", model.RegisterDTO{Username: "alice", Password: "password"})
+	assert.Equal(t, fiber.StatusCreated, status)
+
+	status, res := call(fiber.MethodPost, "/auth/login", "", model.LoginDTO{Username: "alice", Password: "password"})
+	assert.Equal(t, fiber.StatusOK, status)
+	session := tokens(res)
+
+	status, res = call(fiber.MethodGet, "/users/me", session.AccessToken, nil)
+	assert.Equal(t, fiber.StatusOK, status)
+	assert.Equal(t, "alice", res.Data.(map[string]any)["username"])
+
+	status, res = call(fiber.MethodPost, "/auth/refresh", "", model.RefreshDTO{RefreshToken: session.RefreshToken})
+	assert.Equal(t, fiber.StatusOK, status)
+	rotated := tokens(res)
+
+	status, _ = call(fiber.MethodPost, "/auth/logout", "", model.RefreshDTO{RefreshToken: rotated.RefreshToken})
+	assert.Equal(t, fiber.StatusOK, status)
+
+	status, _ = call(fiber.MethodGet, "/users/me", rotated.AccessToken, nil)
+	assert.Equal(t, fiber.StatusUnauthorized, status, "logout revokes the access tokens of the session")
+
+	// Locked after login: the access tokens of the user are refused.
+	status, res = call(fiber.MethodPost, "/auth/login", "", model.LoginDTO{Username: "alice", Password: "password"})
+	assert.Equal(t, fiber.StatusOK, status)
+	assert.NoError(t, a.DBClient.Model(&model.User{}).Where("username = ?", "alice").Update("status", model.UserStatusLocked).Error)
+
+	status, _ = call(fiber.MethodGet, "/users/me", tokens(res).AccessToken, nil)
+	assert.Equal(t, fiber.StatusForbidden, status)
+}
+
+func TestNew_Problem(t *testing.T) {
+	ctx := context.Background()
+	a, err := app.New(resty.New(), nil)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.NoError(t, a.Start(ctx))
+	defer a.Shutdown(ctx)
+
+	register := func(accept string, dto model.RegisterDTO) (*http.Response, map[string]any) {
+		b, err := json.Marshal(dto)
+		assert.NoError(t, err)
+
+		req := httptest.NewRequest(fiber.MethodPost, "/auth/register", bytes.NewReader(b))
+		req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+		req.Header.Set(fiber.HeaderAccept, accept)
+
+		resp, err := a.Server.Test(req)
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+
+		var body map[string]any
+		assert.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+		return resp, body
+	}
+
+	// test logic
+	resp, body := register("application/problem+json", model.RegisterDTO{Username: "erin", Password: "short"})
+	assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, "application/problem+json", resp.Header.Get(fiber.HeaderContentType))
+	assert.Equal(t, "VALIDATION_FAILED", body["code"])
+	assert.Equal(t, resp.Header.Get(fiber.HeaderXRequestID), body["instance"])
+	assert.NotEmpty(t, body["instance"])
+	assert.Equal(t, []any{map[string]any{"field": "RegisterDTO.Password", "tag": "min", "param": "8"}}, body["errors"])
+
+	resp, _ = register(fiber.MIMEApplicationJSON, model.RegisterDTO{Username: "erin", Password: "password"})
+	assert.Equal(t, fiber.StatusCreated, resp.StatusCode)
+
+	resp, body = register(fiber.MIMEApplicationJSON, model.RegisterDTO{Username: "erin", Password: "password"})
+	assert.Equal(t, fiber.StatusConflict, resp.StatusCode)
+	assert.Equal(t, fiber.MIMEApplicationJSON, resp.Header.Get(fiber.HeaderContentType))
+	assert.Equal(t, map[string]any{"code": "USERNAME_TAKEN", "message": "username is already taken", "ok": false}, body)
+
+	// Errors of middlewares are rendered alike.
+	req := httptest.NewRequest(fiber.MethodGet, "/users/me", nil)
+	req.Header.Set(fiber.HeaderAccept, "application/problem+json")
+	resp, err = a.Server.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusUnauthorized, resp.StatusCode)
+	assert.Equal(t, "application/problem+json", resp.Header.Get(fiber.HeaderContentType))
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(t, "TOKEN_REQUIRED", body["code"])
+}
+
+func TestNew_RBAC(t *testing.T) {
+	ctx := context.Background()
+	a, err := app.New(resty.New(), nil)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.NoError(t, a.Start(ctx))
+	defer a.Shutdown(ctx)
+
+	call := func(method, path, token string, body any) (int, response.ResponseDTO) {
+		var reader io.Reader
+		if body != nil {
+			b, err := json.Marshal(body)
+			assert.NoError(t, err)
+			reader = bytes.NewReader(b)
+		}
+
+		req := httptest.NewRequest(method, path, reader)
+		req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+		if token != "" {
+			req.Header.Set(fiber.HeaderAuthorization, "Bearer "+token)
+		}
+
+		resp, err := a.Server.Test(req)
+		if !assert.NoError(t, err) {
+			return 0, response.ResponseDTO{}
+		}
+
+		var res response.ResponseDTO
+		_ = json.NewDecoder(resp.Body).Decode(&res)
+		return resp.StatusCode, res
+	}
+
+	login := func(username string) (uint, string) {
+		status, res := call(fiber.MethodPost, "/auth/register", "", model.RegisterDTO{Username: username, Password: "password"})
+		assert.Equal(t, fiber.StatusCreated, status)
+		id := uint(res.Data.(map[string]any)["ID"].(float64))
+
+		status, res = call(fiber.MethodPost, "/auth/login", "", model.LoginDTO{Username: username, Password: "password"})
+		assert.Equal(t, fiber.StatusOK, status)
+		return id, res.Data.(map[string]any)["accessToken"].(string)
+	}
+
+	// The first administrator is appointed out of band.
+	carolID, carol := login("carol")
+	admin := model.Role{Name: "admin", Permissions: []model.Permission{{Name: rbac.PermissionAdmin}, {Name: rbac.PermissionRolesManage}}}
+	assert.NoError(t, a.DBClient.Create(&admin).Error)
+	assert.NoError(t, a.DBClient.Model(&model.User{Model: gorm.Model{ID: carolID}}).Association("Roles").Append(&admin))
+	daveID, dave := login("dave")
+
+	// test logic
+	status, _ := call(fiber.MethodGet, "/admin/roles", dave, nil)
+	assert.Equal(t, fiber.StatusForbidden, status, "not an administrator")
+	status, _ = call(fiber.MethodGet, "/admin/roles", carol, nil)
+	assert.Equal(t, fiber.StatusOK, status)
+	status, _ = call(fiber.MethodGet, "/admin/log-level", dave, nil)
+	assert.Equal(t, fiber.StatusForbidden, status, "not an administrator")
+	status, _ = call(fiber.MethodGet, "/admin/log-level", carol, nil)
+	assert.Equal(t, fiber.StatusOK, status)
+
+	status, _ = call(fiber.MethodGet, "/rbac/roles", "", nil)
+	assert.Equal(t, fiber.StatusUnauthorized, status)
+
+	status, _ = call(fiber.MethodGet, "/rbac/roles", dave, nil)
+	assert.Equal(t, fiber.StatusForbidden, status, "no role, no access")
+
+	status, res := call(fiber.MethodPost, "/rbac/roles", carol, model.RoleDTO{Name: "viewer", Permissions: []string{"posts:read"}})
+	assert.Equal(t, fiber.StatusCreated, status)
+	assert.Equal(t, "viewer", res.Data.(map[string]any)["name"])
+
+	path := fmt.Sprintf("/rbac/users/%d/roles", daveID)
+	status, _ = call(fiber.MethodPut, path, carol, model.UserRolesDTO{Roles: []string{"viewer", "owner"}})
+	assert.Equal(t, fiber.StatusBadRequest, status)
+
+	status, _ = call(fiber.MethodPut, path, carol, model.UserRolesDTO{Roles: []string{"viewer", "admin"}})
+	assert.Equal(t, fiber.StatusOK, status)
+
+	status, res = call(fiber.MethodGet, "/rbac/roles", dave, nil)
+	assert.Equal(t, fiber.StatusOK, status, "the cached policy is dropped on assignment")
+	assert.Len(t, res.Data, 2)
+
+	status, _ = call(fiber.MethodPut, path, carol, model.UserRolesDTO{Roles: []string{"viewer"}})
+	assert.Equal(t, fiber.StatusOK, status)
+
+	status, _ = call(fiber.MethodGet, "/rbac/roles", dave, nil)
+	assert.Equal(t, fiber.StatusForbidden, status)
+}
+
+func TestNew_OIDC(t *testing.T) {
+	provider := oidctest.NewProvider(t, "api", "secret")
+	t.Setenv("OIDC_PROVIDERS", "corp="+provider.Issuer())
+	t.Setenv("OIDC_CLIENT_IDS", "corp=api")
+	t.Setenv("OIDC_CLIENT_SECRETS", "corp=secret")
+	t.Setenv("OIDC_REDIRECT_URL", "https://api.example.com/auth/oidc")
+	provider.SetUser(map[string]any{"sub": "1001", "preferred_username": "erin", "email": "erin@example.com"})
+
+	ctx := context.Background()
+	a, err := app.New(resty.New(), nil)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.NoError(t, a.Start(ctx))
+	defer a.Shutdown(ctx)
+
+	get := func(path, token string, cookies ...*http.Cookie) (*http.Response, response.ResponseDTO) {
+		req := httptest.NewRequest(fiber.MethodGet, path, nil)
+		if token != "" {
+			req.Header.Set(fiber.HeaderAuthorization, "Bearer "+token)
+		}
+		for _, cookie := range cookies {
+			req.AddCookie(cookie)
+		}
+
+		resp, err := a.Server.Test(req)
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+
+		var res response.ResponseDTO
+		_ = json.NewDecoder(resp.Body).Decode(&res)
+		return resp, res
+	}
+
+	// test logic
+	resp, _ := get("/auth/oidc/corp", "")
+	assert.Equal(t, fiber.StatusFound, resp.StatusCode)
+	cookies := resp.Cookies()
+
+	callback, err := provider.Authorize(resp.Header.Get(fiber.HeaderLocation))
+	assert.NoError(t, err)
+	resp, _ = get(callback.RequestURI(), "")
+	assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode, "a login completes in the browser starting it")
+	resp, res := get(callback.RequestURI(), "", cookies...)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	token := res.Data.(map[string]any)["accessToken"].(string)
+
+	resp, res = get("/users/me", token)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	assert.Equal(t, "erin", res.Data.(map[string]any)["username"])
+
+	resp, _ = get(callback.RequestURI(), "", cookies...)
+	assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode, "a login completes once")
+}
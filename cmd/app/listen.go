@@ -0,0 +1,53 @@
+package app
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"go-fiber-api/internal/core/lifecycle"
+	"go-fiber-api/internal/wrapper/tlsx"
+	"net"
+)
+
+// Listen serves the application on the configured port, over HTTPS when TLS
+// is enabled, along with the plain HTTP redirect listener if configured. It
+// blocks until the server is shut down.
+func (a *Application) Listen() error {
+	addr := ":" + a.Config.Port
+	if !a.Config.TLSEnabled {
+		return a.Server.Listen(addr)
+	}
+
+	reloader, err := tlsx.NewCertReloader(a.Config.TLSCertFile, a.Config.TLSKeyFile)
+	if err != nil {
+		return err
+	}
+	a.Lifecycle.Append(lifecycle.Hook{
+		Name: "certificate reloader",
+		OnStop: func(context.Context) error {
+			return reloader.Close()
+		},
+	})
+
+	tlsConfig, err := tlsx.ServerConfig(a.Config, reloader)
+	if err != nil {
+		return err
+	}
+
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("listen %s failed: %v", addr, err)
+	}
+
+	if a.Config.TLSRedirectPort != "" {
+		redirect := tlsx.NewRedirectServer(a.Config.Port)
+		a.redirectServer.Store(redirect)
+		go func() {
+			if err := redirect.Listen(":" + a.Config.TLSRedirectPort); err != nil {
+				a.LogX.Errorf("https redirect listener failed: %v", err)
+			}
+		}()
+	}
+
+	return a.Server.Listener(tls.NewListener(ln, tlsConfig))
+}
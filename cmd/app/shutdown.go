@@ -0,0 +1,47 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Shutdown stops the application in order: readiness fails first so the
+// instance is taken out of rotation, in-flight requests are drained, then the
+// lifecycle stop hooks run.
+func (a *Application) Shutdown(ctx context.Context) error {
+	log := a.LogX.WithField("component", "shutdown")
+
+	a.HealthRegistry.ShutDown()
+
+	drainDelay := time.Duration(a.Config.ShutdownDrainDelay) * time.Second
+	log.Infof("readiness failing, closing listener in %s", drainDelay)
+	select {
+	case <-time.After(drainDelay):
+	case <-ctx.Done():
+	}
+
+	var errs []error
+
+	timeout := time.Duration(a.Config.ShutdownTimeout) * time.Second
+	log.Infof("draining in-flight requests for up to %s", timeout)
+	if err := a.Server.ShutdownWithTimeout(timeout); err != nil {
+		errs = append(errs, fmt.Errorf("shutdown server: %w", err))
+	}
+	if redirect := a.redirectServer.Load(); redirect != nil {
+		if err := redirect.ShutdownWithTimeout(timeout); err != nil {
+			errs = append(errs, fmt.Errorf("shutdown redirect server: %w", err))
+		}
+	}
+
+	// The components stop in the reverse order they were built in: Redis
+	// and the DB are closed, pending spans flushed, and the log outputs
+	// closed last so the lines above still reach all of them.
+	log.Info("stopping components")
+	if err := a.Lifecycle.Stop(ctx); err != nil {
+		errs = append(errs, err)
+	}
+
+	return errors.Join(errs...)
+}
@@ -6,31 +6,69 @@ package app
 
 import (
 	"go-fiber-api/internal/core/config"
+	"go-fiber-api/internal/core/guard"
+	"go-fiber-api/internal/core/health"
+	"go-fiber-api/internal/core/lifecycle"
+	"go-fiber-api/internal/core/lock"
 	apikey_middleware "go-fiber-api/internal/core/middleware/apikey"
+	auth_middleware "go-fiber-api/internal/core/middleware/auth"
+	"go-fiber-api/internal/core/middleware/authz"
 	"go-fiber-api/internal/core/middleware/cache"
+	cors_middleware "go-fiber-api/internal/core/middleware/cors"
+	"go-fiber-api/internal/core/middleware/logger"
+	metrics_middleware "go-fiber-api/internal/core/middleware/metrics"
+	"go-fiber-api/internal/core/middleware/ratelimit"
+	tracing_middleware "go-fiber-api/internal/core/middleware/tracing"
+	cache_storage "go-fiber-api/internal/core/storage/cache"
 	"go-fiber-api/internal/core/storage/db"
+	"go-fiber-api/internal/wrapper/jwtx"
 	"go-fiber-api/internal/wrapper/logx"
+	"go-fiber-api/internal/wrapper/metrics"
 	"go-fiber-api/internal/wrapper/redis"
+	"go-fiber-api/internal/wrapper/tracing"
 
 	"go-fiber-api/internal/feature/apikey"
+	"go-fiber-api/internal/feature/auth"
+	"go-fiber-api/internal/feature/loglevel"
+	"go-fiber-api/internal/feature/oidc"
+	"go-fiber-api/internal/feature/rbac"
 	"go-fiber-api/internal/feature/user"
 
 	"github.com/go-resty/resty/v2"
 	"github.com/google/wire"
 )
 
-func New(client *resty.Client) (*Application, error) {
+func build(client *resty.Client, args config.Args) (*Application, func(), error) {
 	wire.Build(
 		Provide,
+		lifecycle.ProviderSet,
 		config.ProviderSet,
 		logx.ProviderSet,
+		metrics.ProviderSet,
+		tracing.ProviderSet,
+		jwtx.ProviderSet,
 		db.ProviderSet,
 		user.ProviderSet,
 		redis.ProviderSet,
+		lock.ProviderSet,
+		cache_storage.ProviderSet,
 		cache.ProviderSet,
+		guard.ProviderSet,
+		cors_middleware.ProviderSet,
+		ratelimit.ProviderSet,
 		apikey.ProviderSet,
 		apikey_middleware.ProviderSet,
+		auth.ProviderSet,
+		auth_middleware.ProviderSet,
+		oidc.ProviderSet,
+		rbac.ProviderSet,
+		authz.ProviderSet,
+		logger.ProviderSet,
+		loglevel.ProviderSet,
+		metrics_middleware.ProviderSet,
+		tracing_middleware.ProviderSet,
+		health.ProviderSet,
 	)
 
-	return &Application{}, nil
+	return &Application{}, nil, nil
 }
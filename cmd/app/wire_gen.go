@@ -9,33 +9,115 @@ package app
 import (
 	"github.com/go-resty/resty/v2"
 	"go-fiber-api/internal/core/config"
+	"go-fiber-api/internal/core/guard"
+	"go-fiber-api/internal/core/health"
+	"go-fiber-api/internal/core/lifecycle"
+	"go-fiber-api/internal/core/lock"
 	apikey2 "go-fiber-api/internal/core/middleware/apikey"
-	"go-fiber-api/internal/core/middleware/cache"
+	auth2 "go-fiber-api/internal/core/middleware/auth"
+	"go-fiber-api/internal/core/middleware/authz"
+	cache2 "go-fiber-api/internal/core/middleware/cache"
+	"go-fiber-api/internal/core/middleware/cors"
+	"go-fiber-api/internal/core/middleware/logger"
+	metrics2 "go-fiber-api/internal/core/middleware/metrics"
+	"go-fiber-api/internal/core/middleware/ratelimit"
+	tracing2 "go-fiber-api/internal/core/middleware/tracing"
+	"go-fiber-api/internal/core/storage/cache"
 	"go-fiber-api/internal/core/storage/db"
 	"go-fiber-api/internal/feature/apikey"
+	"go-fiber-api/internal/feature/auth"
+	"go-fiber-api/internal/feature/loglevel"
+	"go-fiber-api/internal/feature/oidc"
+	"go-fiber-api/internal/feature/rbac"
 	"go-fiber-api/internal/feature/user"
+	"go-fiber-api/internal/wrapper/jwtx"
 	"go-fiber-api/internal/wrapper/logx"
+	"go-fiber-api/internal/wrapper/metrics"
 	"go-fiber-api/internal/wrapper/redis"
+	"go-fiber-api/internal/wrapper/tracing"
 )
 
 // Injectors from wire.go:
 
-func New(client *resty.Client) (*Application, error) {
-	logX := logx.Provide()
-	configuration := config.Provide(logX)
-	dbClient := db.ProvideDB(configuration)
+func build(client *resty.Client, args config.Args) (*Application, func(), error) {
+	lifecycleLifecycle, cleanup := lifecycle.Provide()
+	logX := logx.Provide(lifecycleLifecycle)
+	configuration, err := config.Provide(logX, args)
+	if err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+	metricsMetrics := metrics.New()
+	tracingTracing, err := tracing.Provide(configuration, lifecycleLifecycle)
+	if err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+	keyring, err := jwtx.Provide(configuration)
+	if err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+	dbClient, err := db.ProvideDB(configuration, metricsMetrics, tracingTracing, lifecycleLifecycle)
+	if err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+	redisClient, err := redis.ProvideClient(configuration, metricsMetrics, tracingTracing, lifecycleLifecycle)
+	if err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+	locker := lock.Provide(redisClient)
 	repo := user.ProvideRepository(dbClient)
 	service := user.ProvideService(repo)
 	handler := user.ProvideHandler(service)
-	redisClient, err := redis.ProvideClient(configuration)
+	cacheCache, err := cache.Provide(configuration, redisClient)
+	if err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+	watcher, err := config.ProvideWatcher(configuration, logX, args, lifecycleLifecycle)
 	if err != nil {
-		return nil, err
+		cleanup()
+		return nil, nil, err
 	}
-	cacheMiddleware := cache.New(redisClient)
+	cacheMiddleware := cache2.New(cacheCache, metricsMetrics, configuration, watcher)
+	middleware := cors.Provide(configuration, watcher)
+	ratelimitMiddleware := ratelimit.Provide(configuration, watcher)
 	repoRepo := apikey.ProvideRepository(dbClient)
-	apikeyService := apikey.ProvideService(configuration, repoRepo)
+	apikeyService := apikey.ProvideService(keyring, repoRepo)
 	apikeyHandler := apikey.ProvideHandler(apikeyService)
-	middleware := apikey2.Provide(configuration, apikeyService)
-	application := Provide(configuration, logX, dbClient, handler, cacheMiddleware, apikeyHandler, middleware)
-	return application, nil
+	guardGuard := guard.Provide(configuration, redisClient)
+	apikeyMiddleware := apikey2.Provide(configuration, apikeyService, keyring, metricsMetrics, guardGuard)
+	repository := auth.ProvideRepository(dbClient)
+	authService, err := auth.ProvideService(configuration, keyring, dbClient, repo, repository, guardGuard)
+	if err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+	authHandler := auth.ProvideHandler(authService)
+	authCleanup := auth.ProvideCleanup(repository, locker, logX, lifecycleLifecycle)
+	authMiddleware := auth2.Provide(authService, metricsMetrics)
+	oidcRepository := oidc.ProvideRepository(dbClient)
+	oidcService, err := oidc.ProvideService(configuration, client, cacheCache, oidcRepository, repo, authService)
+	if err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+	oidcHandler := oidc.ProvideHandler(configuration, oidcService)
+	rbacRepository := rbac.ProvideRepository(dbClient)
+	rbacService := rbac.ProvideService(configuration, rbacRepository, cacheCache)
+	rbacHandler := rbac.ProvideHandler(rbacService)
+	authzMiddleware := authz.Provide(rbacService, metricsMetrics)
+	loggerMiddleware := logger.Provide(logX)
+	loglevelHandler := loglevel.ProvideHandler(logX, configuration, watcher)
+	metricsMiddleware := metrics2.Provide(metricsMetrics)
+	tracingMiddleware := tracing2.Provide(tracingTracing)
+	registry := health.ProvideRegistry(configuration, dbClient, redisClient)
+	healthHandler := health.ProvideHandler(configuration, registry)
+	application := Provide(configuration, logX, metricsMetrics, tracingTracing, client, keyring, dbClient, redisClient, locker, handler, cacheMiddleware, middleware, ratelimitMiddleware, apikeyHandler, apikeyMiddleware, authHandler, authCleanup, authMiddleware, oidcHandler, rbacHandler, authzMiddleware, loggerMiddleware, loglevelHandler, metricsMiddleware, tracingMiddleware, healthHandler, registry, lifecycleLifecycle)
+	return application, func() {
+		cleanup()
+	}, nil
 }
@@ -0,0 +1,69 @@
+// Command cli runs maintenance tasks of the application:
+//
+//	go run cmd/cli/main.go encrypt-config -key config.key config.yaml > config.yaml.enc
+//	go run cmd/cli/main.go decrypt-config -key config.key config.yaml.enc
+//
+// The key is generated with `openssl rand -base64 32 > config.key`.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"go-fiber-api/internal/core/config"
+	"os"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+	}
+
+	switch os.Args[1] {
+	case "encrypt-config":
+		crypt(os.Args[2:], config.Encrypt)
+	case "decrypt-config":
+		crypt(os.Args[2:], config.Decrypt)
+	default:
+		usage()
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: cli encrypt-config|decrypt-config -key FILE CONFIG_FILE")
+	os.Exit(2)
+}
+
+// crypt writes the config file of args, encrypted or decrypted by fn, to
+// stdout.
+func crypt(args []string, fn func(data, key []byte) ([]byte, error)) {
+	flags := flag.NewFlagSet(os.Args[1], flag.ExitOnError)
+	keyFile := flags.String("key", os.Getenv(config.ConfigKeyFileEnv), "key file")
+	_ = flags.Parse(args)
+	if flags.NArg() != 1 || *keyFile == "" {
+		usage()
+	}
+
+	key, err := config.ReadKeyFile(*keyFile)
+	if err != nil {
+		fail(err)
+	}
+
+	data, err := os.ReadFile(flags.Arg(0))
+	if err != nil {
+		fail(err)
+	}
+
+	out, err := fn(data, key)
+	if err != nil {
+		fail(err)
+	}
+
+	if _, err := os.Stdout.Write(out); err != nil {
+		fail(err)
+	}
+}
+
+func fail(err error) {
+	fmt.Fprintln(os.Stderr, err)
+	os.Exit(1)
+}
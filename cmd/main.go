@@ -1,8 +1,13 @@
 package main
 
 import (
+	"context"
 	"go-fiber-api/cmd/app"
+	"go-fiber-api/internal/core/config"
 	"log"
+	"os"
+	"os/signal"
+	"syscall"
 
 	"github.com/go-resty/resty/v2"
 	// _ "time/tzdata"
@@ -11,13 +16,36 @@ import (
 func main() {
 	// TODO: init global & remove all log in DI.
 	client := resty.New()
-	application, err := app.New(client)
+	application, err := app.New(client, config.Args(os.Args[1:]))
 	if err != nil {
 		log.Fatalf("initial application failed: %v", err)
 	}
 
-	if err := application.Server.Listen(":" + application.Config.Port); err != nil {
-		log.Fatal(err)
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	if err := application.Start(ctx); err != nil {
+		log.Fatalf("start application failed: %v", err)
+	}
+
+	listenErr := make(chan error, 1)
+	go func() {
+		log.Printf("server listening on port %s", application.Config.Port)
+		listenErr <- application.Listen()
+	}()
+
+	select {
+	case err := <-listenErr:
+		if err != nil {
+			log.Fatal(err)
+		}
+	case <-ctx.Done():
+		// Restore the default behaviour: a second signal kills the process.
+		stop()
+	}
+
+	if err := application.Shutdown(context.Background()); err != nil {
+		log.Printf("shutdown completed with errors: %v", err)
+		os.Exit(1)
 	}
-	log.Printf("server listening on port %s", application.Config.Port)
 }
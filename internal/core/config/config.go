@@ -1,113 +1,299 @@
 package config
 
 import (
+	"fmt"
 	"go-fiber-api/internal/wrapper/logx"
-	"os"
-	"reflect"
 	"strings"
-	"sync"
 
 	"github.com/sirupsen/logrus"
-	"github.com/spf13/viper"
 )
 
-var (
-	_configOnce sync.Once
-	// All values below are default if they are not specified from env variable
-	_config = Configuration{
+// defaults returns the values used when they are not specified by the config
+// file, the environment or a flag.
+func defaults() Configuration {
+	return Configuration{
 		DevMode:       false,
 		Port:          "80",
 		IsAutoMigrate: true,
 		TZ:            "Asia/Bangkok",
-	}
 
-	_log *logrus.Entry
-)
+		CacheTTL: 60,
+
+		RateLimitWindow: 60,
+
+		RBACCacheTTL: 300,
+
+		DB: DBConfig{
+			Driver: "postgres",
+		},
+
+		CacheBackend:    "redis",
+		CacheMemorySize: 10000,
+		CacheLocalSize:  1000,
+		CacheLocalTTL:   30,
+
+		TracingExporter:     "none",
+		TracingServiceName:  "go-fiber-api",
+		TracingSampleRatio:  1,
+		TracingOTLPEndpoint: "localhost:4318",
+		TracingFilePath:     "logs/traces.json",
+
+		HealthCheckTimeout: 2,
+		HealthCacheTTL:     5,
+
+		ShutdownTimeout:    30,
+		ShutdownDrainDelay: 5,
+
+		TLSClientAuth: "none",
+
+		Auth: AuthConfig{
+			AccessTokenTTL:  900,
+			RefreshTokenTTL: 30 * 24 * 60 * 60,
+			PasswordHash:    "argon2id",
+		},
+
+		BruteForce: BruteForceConfig{
+			Window:             900,
+			FreeAttempts:       3,
+			BaseDelay:          1,
+			MaxDelay:           60,
+			LockThreshold:      10,
+			LockDuration:       900,
+			APIKeyBanThreshold: 10,
+			APIKeyBanDuration:  900,
+		},
+
+		JWT: JWTConfig{
+			LegacyEnabled: true,
+		},
+
+		OIDC: OIDCConfig{
+			Scopes:   "openid profile email",
+			StateTTL: 600,
+		},
+	}
+}
 
+// Configuration is read from the keys of the mapstructure tags, nested keys
+// joined with "_" in the environment (DB_HOST) and with "." in the config
+// file and flags (db.host). Keys tagged reload:"true" are applied at runtime
+// when the config file changes, see Watcher.
 type Configuration struct {
-	DevMode bool   `mapstructure:"DEV_MODE"`
-	Port    string `mapstructure:"PORT"`
-	TZ      string `mapstructure:"TZ"`
-
-	DBUser        string `mapstructure:"DB_USER"`
-	DBPass        string `mapstructure:"DB_PASS"`
-	DBName        string `mapstructure:"DB_NAME"`
-	DBPort        string `mapstructure:"DB_PORT"`
-	DBHost        string `mapstructure:"DB_HOST"`
-	DBSSLMode     string `mapstructure:"DB_SSL_MODE"`
+	DevMode       bool   `mapstructure:"DEV_MODE"`
+	Port          string `mapstructure:"PORT" validate:"required,numeric"`
+	TZ            string `mapstructure:"TZ"`
 	IsAutoMigrate bool   `mapstructure:"IS_AUTO_MIGRATE"`
 
-	// Redis
-	RedisHost     string `mapstructure:"REDIS_HOST"`
-	RedisPort     string `mapstructure:"REDIS_PORT"`
-	RedisPassword string `mapstructure:"REDIS_PASSWORD"`
-	RedisDB       int    `mapstructure:"REDIS_DB"`
+	// Base of the type URIs of problem+json errors, followed by the error
+	// code, e.g. https://docs.example.com/errors/user-locked. Empty types
+	// them about:blank
+	ProblemTypeBaseURL string `mapstructure:"PROBLEM_TYPE_BASE_URL" validate:"omitempty,url"`
+
+	// Default log level, empty keeps the one logx read from LOG_LEVEL
+	LogLevel string `mapstructure:"LOG_LEVEL" reload:"true" validate:"omitempty,oneof=panic fatal error warn warning info debug trace"`
+
+	// Response cache of GET requests
+	CacheTTL int `mapstructure:"CACHE_TTL" reload:"true" validate:"gt=0"` // seconds
+
+	// Requests per client IP and window, 0 disables rate limiting
+	RateLimitMax    int `mapstructure:"RATE_LIMIT_MAX" reload:"true" validate:"gte=0"`
+	RateLimitWindow int `mapstructure:"RATE_LIMIT_WINDOW" reload:"true" validate:"gt=0"` // seconds
+
+	// Permissions of a user, cached until their roles change
+	RBACCacheTTL int `mapstructure:"RBAC_CACHE_TTL" validate:"gt=0"` // seconds
+
+	DB    DBConfig    `mapstructure:"DB"`
+	Redis RedisConfig `mapstructure:"REDIS"`
+
+	// Cache backend: "redis" or "memory" for runs without Redis
+	CacheBackend    string `mapstructure:"CACHE_BACKEND" validate:"oneof=redis memory"`
+	CacheMemorySize int    `mapstructure:"CACHE_MEMORY_SIZE" validate:"gt=0"`
+
+	// Local cache, an in-process LRU tier in front of Redis
+	CacheLocalEnabled bool `mapstructure:"CACHE_LOCAL_ENABLED"`
+	CacheLocalSize    int  `mapstructure:"CACHE_LOCAL_SIZE" validate:"gt=0"`
+	CacheLocalTTL     int  `mapstructure:"CACHE_LOCAL_TTL" validate:"gte=0"` // seconds
 
-	// API Keys
-	SecretKey string `mapstructure:"SECRET_KEY"`
+	// Tracing exporter: "none", "otlp" (HTTP), "stdout" or "file"
+	TracingExporter     string  `mapstructure:"TRACING_EXPORTER" validate:"oneof=none otlp stdout file"`
+	TracingServiceName  string  `mapstructure:"TRACING_SERVICE_NAME"`
+	TracingSampleRatio  float64 `mapstructure:"TRACING_SAMPLE_RATIO" validate:"gte=0,lte=1"` // of new traces
+	TracingOTLPEndpoint string  `mapstructure:"TRACING_OTLP_ENDPOINT" validate:"required_if=TracingExporter otlp"`
+	TracingOTLPInsecure bool    `mapstructure:"TRACING_OTLP_INSECURE"`
+	TracingFilePath     string  `mapstructure:"TRACING_FILE_PATH" validate:"required_if=TracingExporter file"`
 
-	// CORS
-	CorsAllowedOrigins string `mapstructure:"CORS_ALLOWED_ORIGINS"`
-	CorsAllowedHeaders string `mapstructure:"CORS_ALLOWED_HEADERS"`
+	// Health checks
+	HealthCheckTimeout int `mapstructure:"HEALTH_CHECK_TIMEOUT" validate:"gt=0"` // seconds, per checker
+	HealthCacheTTL     int `mapstructure:"HEALTH_CACHE_TTL" validate:"gte=0"`    // seconds a readiness report is reused
+
+	// TLS, the certificate and key are reloaded when they change on disk
+	TLSEnabled      bool   `mapstructure:"TLS_ENABLED"`
+	TLSCertFile     string `mapstructure:"TLS_CERT_FILE" validate:"required_if=TLSEnabled true"`
+	TLSKeyFile      string `mapstructure:"TLS_KEY_FILE" validate:"required_if=TLSEnabled true"`
+	TLSRedirectPort string `mapstructure:"TLS_REDIRECT_PORT" validate:"omitempty,numeric"` // plain HTTP port redirecting to HTTPS, empty to disable
+
+	// Mutual TLS: "none", "optional" or "require" client certificates
+	TLSClientAuth       string `mapstructure:"TLS_CLIENT_AUTH" validate:"oneof=none optional require"`
+	TLSClientCAFile     string `mapstructure:"TLS_CLIENT_CA_FILE" validate:"required_unless=TLSClientAuth none"`
+	TLSClientPrincipals string `mapstructure:"TLS_CLIENT_PRINCIPALS"` // comma separated identity=principal, empty maps the common name
+
+	// Graceful shutdown (seconds)
+	ShutdownTimeout    int `mapstructure:"SHUTDOWN_TIMEOUT" validate:"gte=0"`     // to drain in-flight requests
+	ShutdownDrainDelay int `mapstructure:"SHUTDOWN_DRAIN_DELAY" validate:"gte=0"` // readiness fails before the listener closes
+
+	// API Keys, signed with SECRET_KEY unless the JWT keyring has keys
+	SecretKey string    `mapstructure:"SECRET_KEY" secret:"true" validate:"required_without=JWT.Keys"`
+	JWT       JWTConfig `mapstructure:"JWT"`
+
+	Auth       AuthConfig       `mapstructure:"AUTH"`
+	BruteForce BruteForceConfig `mapstructure:"BRUTE_FORCE"`
+	OIDC       OIDCConfig       `mapstructure:"OIDC"`
+
+	CORS CORSConfig `mapstructure:"CORS"`
 }
 
-func Provide(logx *logx.LogX) *Configuration {
-	_configOnce.Do(func() {
-		_log = logx.WithFields(logrus.Fields{
-			"component": "config",
-			"module":    "config",
-		})
-
-		envFilePath, ok := os.LookupEnv("ENV_FILE_PATH")
-		if len(envFilePath) > 0 && ok {
-			viper.SetConfigFile(envFilePath)
-			if err := viper.ReadInConfig(); err != nil {
-				_log.Warnf("read config from file %s failed: %v\tcontinue reading from `env`\n", envFilePath, err)
-			}
-		} else {
-			viper.AutomaticEnv()
-		}
+type DBConfig struct {
+	Driver  string `mapstructure:"DRIVER" validate:"oneof=postgres sqlite"`
+	User    string `mapstructure:"USER" validate:"required_if=Driver postgres"`
+	Pass    string `mapstructure:"PASS" secret:"true"`
+	Name    string `mapstructure:"NAME" validate:"required"` // the file or ":memory:" with sqlite
+	Port    string `mapstructure:"PORT" validate:"required_if=Driver postgres,omitempty,numeric"`
+	Host    string `mapstructure:"HOST" validate:"required_if=Driver postgres"`
+	SSLMode string `mapstructure:"SSL_MODE"`
+}
 
-		bindEnv(_config)
+type RedisConfig struct {
+	Host     string `mapstructure:"HOST" validate:"required_without_all=SentinelMaster ClusterAddrs"`
+	Port     string `mapstructure:"PORT" validate:"required_without_all=SentinelMaster ClusterAddrs,omitempty,numeric"`
+	Password string `mapstructure:"PASSWORD" secret:"true"`
+	DB       int    `mapstructure:"DB" validate:"gte=0"`
+	Username string `mapstructure:"USERNAME"` // ACL user, Redis 6+
 
-		err := viper.Unmarshal(&_config)
-		if err != nil {
-			logrus.Fatalf("config bind failed: %v", err)
-		}
+	// Sentinel, used instead of Host/Port when the master name is set
+	SentinelMaster   string `mapstructure:"SENTINEL_MASTER"`
+	SentinelAddrs    string `mapstructure:"SENTINEL_ADDRS" validate:"required_with=SentinelMaster"` // comma separated host:port
+	SentinelUsername string `mapstructure:"SENTINEL_USERNAME"`
+	SentinelPassword string `mapstructure:"SENTINEL_PASSWORD" secret:"true"`
 
-		_log.Infof("Configuration: %v", logx.GetLevel())
-		_log.Debugf("Configuration: %+v", _config)
-	})
+	// Cluster seed nodes, comma separated host:port
+	ClusterAddrs string `mapstructure:"CLUSTER_ADDRS"`
 
-	return &_config
+	TLSEnabled            bool   `mapstructure:"TLS_ENABLED"`
+	TLSCAFile             string `mapstructure:"TLS_CA_FILE"`
+	TLSServerName         string `mapstructure:"TLS_SERVER_NAME"`
+	TLSInsecureSkipVerify bool   `mapstructure:"TLS_INSECURE_SKIP_VERIFY"`
 }
 
-func ResetProvide() {
-	_configOnce = sync.Once{}
+// JWTConfig is the keyring signing tokens, each key named by its kid header.
+// Tokens without kid are still checked with SECRET_KEY until LegacyEnabled is
+// turned off, once they are all gone.
+type JWTConfig struct {
+	Keys          string `mapstructure:"KEYS" validate:"required_unless=LegacyEnabled true"` // comma separated kid:algorithm:file, e.g. 2025-01:RS256:file:///run/secrets/jwt.pem
+	SigningKID    string `mapstructure:"SIGNING_KID" validate:"required_with=Keys"`
+	LegacyEnabled bool   `mapstructure:"LEGACY_ENABLED"` // SECRET_KEY checks tokens without kid
 }
 
-func bindEnv(dest interface{}, parts ...string) {
-	ifv := reflect.ValueOf(dest)
-	ift := reflect.TypeOf(dest)
+// AuthConfig is the password login of users. Their access tokens are signed
+// by the JWT keyring, their refresh tokens are stored hashed in the database.
+type AuthConfig struct {
+	AccessTokenTTL  int    `mapstructure:"ACCESS_TOKEN_TTL" validate:"gt=0"`               // seconds
+	RefreshTokenTTL int    `mapstructure:"REFRESH_TOKEN_TTL" validate:"gt=0"`              // seconds
+	PasswordHash    string `mapstructure:"PASSWORD_HASH" validate:"oneof=argon2id bcrypt"` // of new passwords, others are rehashed at login
+}
 
-	for i := 0; i < ift.NumField(); i++ {
-		v := ifv.Field(i)
-		t := ift.Field(i)
+// BruteForceConfig slows down the guessing of passwords and API keys. Failed
+// attempts are counted in Redis per username and per client IP over Window;
+// past FreeAttempts, each failure refuses the next attempts for a delay
+// doubling from BaseDelay up to MaxDelay.
+type BruteForceConfig struct {
+	Window             int `mapstructure:"WINDOW" validate:"gt=0"`                 // seconds
+	FreeAttempts       int `mapstructure:"FREE_ATTEMPTS" validate:"gte=0"`         // failures without delay
+	BaseDelay          int `mapstructure:"BASE_DELAY" validate:"gt=0"`             // seconds
+	MaxDelay           int `mapstructure:"MAX_DELAY" validate:"gt=0"`              // seconds
+	LockThreshold      int `mapstructure:"LOCK_THRESHOLD" validate:"gte=0"`        // failures of a username locking its user, 0 disables locking
+	LockDuration       int `mapstructure:"LOCK_DURATION" validate:"gt=0"`          // seconds until the user is unlocked
+	APIKeyBanThreshold int `mapstructure:"API_KEY_BAN_THRESHOLD" validate:"gte=0"` // invalid API keys of a client IP banning it, 0 disables bans
+	APIKeyBanDuration  int `mapstructure:"API_KEY_BAN_DURATION" validate:"gt=0"`   // seconds
+}
 
-		tv, ok := t.Tag.Lookup("mapstructure")
-		if !ok {
-			continue
-		}
+// OIDCConfig is the login of users with OpenID Connect providers, each one
+// named in the paths of its routes, e.g. /auth/oidc/corp. Users are linked to
+// their subject at the provider, created at their first login.
+type OIDCConfig struct {
+	Providers     string `mapstructure:"PROVIDERS"`                                                     // comma separated name=issuer, e.g. corp=https://sso.example.com
+	ClientIDs     string `mapstructure:"CLIENT_IDS" validate:"required_with=Providers"`                 // comma separated name=client ID
+	ClientSecrets string `mapstructure:"CLIENT_SECRETS" secret:"true"`                                  // comma separated name=client secret, none for public clients
+	RedirectURL   string `mapstructure:"REDIRECT_URL" validate:"required_with=Providers,omitempty,url"` // public URL of /auth/oidc, callbacks are under it
+	Scopes        string `mapstructure:"SCOPES"`                                                        // space separated, openid is always requested
+	StateTTL      int    `mapstructure:"STATE_TTL" validate:"gt=0"`                                     // seconds to complete a login
+}
 
-		switch v.Kind() {
-		case reflect.Struct:
-			bindEnv(v.Interface(), append(parts, tv)...)
-		default:
-			envKey := strings.Join(append(parts, tv), ".")
-			err := viper.BindEnv(envKey)
-			if err != nil {
-				_log.Printf("bind env key %s failed: %v\n", envKey, err)
-			}
+type CORSConfig struct {
+	AllowedOrigins string `mapstructure:"ALLOWED_ORIGINS" reload:"true" validate:"omitempty,origins"`
+	AllowedHeaders string `mapstructure:"ALLOWED_HEADERS" reload:"true"`
+}
+
+// Provide loads a new Configuration from, in increasing precedence, its
+// defaults, the config file, the environment and the flags of args. Startup
+// fails on any invalid value rather than at its first use.
+func Provide(log *logx.LogX, args Args) (*Configuration, error) {
+	entry := log.WithFields(logrus.Fields{
+		"component": "config",
+		"module":    "config",
+	})
+
+	cfg, _, err := load(log, args, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	entry.Infof("Configuration: %v", log.GetLevel())
+	entry.Debugf("Configuration: %+v", logx.Redact(cfg))
+
+	return cfg, nil
+}
+
+// load reads and validates a Configuration, also returning the config file it
+// was read from if any. The secrets of running, the configuration in use if
+// any, stay masked along with the ones read.
+func load(log *logx.LogX, args Args, running *Configuration) (*Configuration, string, error) {
+	v, file, err := newViper(args)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var cfg Configuration
+	if err := v.Unmarshal(&cfg); err != nil {
+		return nil, "", fmt.Errorf("config bind failed: %v", err)
+	}
+
+	if err := resolveSecrets(&cfg); err != nil {
+		return nil, "", err
+	}
+
+	// Secrets are masked in the dump below and in any later log line.
+	secrets := secretValues(&cfg)
+	if running != nil {
+		secrets = append(secrets, secretValues(running)...)
+	}
+	log.RegisterSecrets(secrets...)
+
+	if err := cfg.Validate(); err != nil {
+		return nil, "", err
+	}
+
+	return &cfg, file, nil
+}
+
+// secretValues returns the secrets of cfg, each client secret of
+// OIDC_CLIENT_SECRETS included.
+func secretValues(cfg *Configuration) []string {
+	values := logx.SecretValues(cfg)
+	for _, pair := range strings.Split(cfg.OIDC.ClientSecrets, ",") {
+		if _, secret, ok := strings.Cut(pair, "="); ok {
+			values = append(values, strings.TrimSpace(secret))
 		}
 	}
+
+	return values
 }
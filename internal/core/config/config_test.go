@@ -2,42 +2,257 @@ package config_test
 
 import (
 	"go-fiber-api/internal/core/config"
+	"go-fiber-api/internal/core/lifecycle"
 	"go-fiber-api/internal/wrapper/logx"
 	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 )
 
+// required are the values without a default.
+var required = map[string]string{
+	"SECRET_KEY": "secret",
+	"DB_HOST":    "localhost",
+	"DB_USER":    "postgres",
+	"DB_NAME":    "app",
+	"DB_PORT":    "5432",
+	"REDIS_HOST": "localhost",
+	"REDIS_PORT": "6379",
+}
+
 func TestLoadConfig(t *testing.T) {
-	logger := logx.Provide()
+	logger := logx.Provide(lifecycle.New())
 
 	tests := []struct {
-		name       string
-		expected   config.Configuration
-		isClearEnv bool
+		name     string
+		env      map[string]string
+		file     string // name and content of the config file
+		content  string
+		args     config.Args
+		expected config.Configuration
+		check    func(t *testing.T, cfg *config.Configuration)
 	}{
 		{
-			name:       "if_no_env_file_path_should_get_default_config",
-			isClearEnv: true,
+			name: "if_no_env_file_path_should_get_default_config",
+			env:  required,
 			expected: config.Configuration{
 				DevMode:       false,
 				Port:          "80",
 				IsAutoMigrate: true,
 				TZ:            "Asia/Bangkok",
+
+				CacheTTL: 60,
+
+				RateLimitWindow: 60,
+
+				RBACCacheTTL: 300,
+
+				DB: config.DBConfig{
+					Driver: "postgres",
+					Host:   "localhost",
+					User:   "postgres",
+					Name:   "app",
+					Port:   "5432",
+				},
+				Redis: config.RedisConfig{
+					Host: "localhost",
+					Port: "6379",
+				},
+
+				CacheBackend:    "redis",
+				CacheMemorySize: 10000,
+				CacheLocalSize:  1000,
+				CacheLocalTTL:   30,
+
+				TracingExporter:     "none",
+				TracingServiceName:  "go-fiber-api",
+				TracingSampleRatio:  1,
+				TracingOTLPEndpoint: "localhost:4318",
+				TracingFilePath:     "logs/traces.json",
+
+				HealthCheckTimeout: 2,
+				HealthCacheTTL:     5,
+
+				ShutdownTimeout:    30,
+				ShutdownDrainDelay: 5,
+
+				TLSClientAuth: "none",
+
+				SecretKey: "secret",
+
+				Auth: config.AuthConfig{
+					AccessTokenTTL:  900,
+					RefreshTokenTTL: 2592000,
+					PasswordHash:    "argon2id",
+				},
+				BruteForce: config.BruteForceConfig{
+					Window:             900,
+					FreeAttempts:       3,
+					BaseDelay:          1,
+					MaxDelay:           60,
+					LockThreshold:      10,
+					LockDuration:       900,
+					APIKeyBanThreshold: 10,
+					APIKeyBanDuration:  900,
+				},
+				JWT: config.JWTConfig{
+					LegacyEnabled: true,
+				},
+				OIDC: config.OIDCConfig{
+					Scopes:   "openid profile email",
+					StateTTL: 600,
+				},
+			},
+		},
+		{
+			name:    "when_env_file_and_env_are_set_should_prefer_env",
+			env:     map[string]string{"DB_NAME": "from-env"},
+			file:    "app.env",
+			content: "PORT=9000\nDB_NAME=from-file\nCORS_ALLOWED_ORIGINS=https://example.com\n",
+			check: func(t *testing.T, cfg *config.Configuration) {
+				assert.Equal(t, "9000", cfg.Port)
+				assert.Equal(t, "from-env", cfg.DB.Name)
+				assert.Equal(t, "https://example.com", cfg.CORS.AllowedOrigins)
+				assert.Equal(t, "none", cfg.TracingExporter)
+			},
+		},
+		{
+			name:    "when_yaml_file_and_flags_are_set_should_prefer_flags",
+			env:     map[string]string{"DB_NAME": ""},
+			file:    "config.yaml",
+			content: "port: \"7000\"\ndb:\n  host: yaml-host\n  name: yaml-db\ncache_local_ttl: 60\n",
+			args:    config.Args{"--port=7100", "--db.host", "flag-host", "--dev-mode", "--tracing-sample-ratio=0.5"},
+			check: func(t *testing.T, cfg *config.Configuration) {
+				assert.Equal(t, "7100", cfg.Port)
+				assert.Equal(t, "flag-host", cfg.DB.Host)
+				assert.Equal(t, "yaml-db", cfg.DB.Name)
+				assert.Equal(t, 60, cfg.CacheLocalTTL)
+				assert.True(t, cfg.DevMode)
+				assert.Equal(t, 0.5, cfg.TracingSampleRatio)
 			},
 		},
 	}
 
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			if test.isClearEnv {
-				os.Setenv("ENV_FILE_PATH", "")
+			t.Setenv(config.ConfigFileEnv, "")
+			for key, value := range required {
+				t.Setenv(key, value)
+			}
+			for key, value := range test.env {
+				t.Setenv(key, value)
+			}
+			if test.file != "" {
+				path := filepath.Join(t.TempDir(), test.file)
+				assert.NoError(t, os.WriteFile(path, []byte(test.content), 0o600))
+				t.Setenv(config.ConfigFileEnv, path)
 			}
 
 			// test logic
-			cfg := config.Provide(logger)
+			cfg, err := config.Provide(logger, test.args)
+			assert.NoError(t, err)
+			if test.check != nil {
+				test.check(t, cfg)
+				return
+			}
 			assert.Equal(t, test.expected, *cfg)
 		})
 	}
 }
+
+func TestLoadConfig_Invalid(t *testing.T) {
+	logger := logx.Provide(lifecycle.New())
+
+	tests := []struct {
+		name             string
+		env              map[string]string
+		args             config.Args
+		expectedProblems []string
+		expectedError    string
+	}{
+		{
+			name: "when_required_values_are_missing_should_list_all_of_them",
+			expectedProblems: []string{
+				"DB_USER is required when DB_DRIVER is postgres",
+				"DB_NAME is required",
+				"DB_PORT is required when DB_DRIVER is postgres",
+				"DB_HOST is required when DB_DRIVER is postgres",
+				"REDIS_HOST is required without REDIS_SENTINEL_MASTER or REDIS_CLUSTER_ADDRS",
+				"REDIS_PORT is required without REDIS_SENTINEL_MASTER or REDIS_CLUSTER_ADDRS",
+				"SECRET_KEY is required without JWT_KEYS",
+			},
+		},
+		{
+			name: "when_values_are_invalid_should_list_all_of_them",
+			env: map[string]string{
+				"SECRET_KEY":            "secret",
+				"PORT":                  "http",
+				"PROBLEM_TYPE_BASE_URL": "errors",
+				"DB_DRIVER":             "sqlite",
+				"DB_NAME":               ":memory:",
+				"REDIS_SENTINEL_MASTER": "mymaster",
+				"TRACING_EXPORTER":      "jaeger",
+				"TRACING_SAMPLE_RATIO":  "2",
+				"TLS_ENABLED":           "true",
+				"TLS_CLIENT_AUTH":       "require",
+				"CORS_ALLOWED_ORIGINS":  "https://example.com, example.org",
+				"LOG_LEVEL":             "loud",
+				"OIDC_PROVIDERS":        "corp=https://sso.example.com",
+				"OIDC_REDIRECT_URL":     "/auth/oidc",
+				"JWT_LEGACY_ENABLED":    "false",
+			},
+			expectedProblems: []string{
+				"PORT must be a number",
+				"PROBLEM_TYPE_BASE_URL must be a URL such as https://example.com",
+				"REDIS_SENTINEL_ADDRS is required with REDIS_SENTINEL_MASTER",
+				"TRACING_EXPORTER must be one of none, otlp, stdout, file",
+				"TRACING_SAMPLE_RATIO must be at most 1",
+				"TLS_CERT_FILE is required when TLS_ENABLED is true",
+				"TLS_KEY_FILE is required when TLS_ENABLED is true",
+				"TLS_CLIENT_CA_FILE is required unless TLS_CLIENT_AUTH is none",
+				"CORS_ALLOWED_ORIGINS must be comma separated origins such as https://example.com",
+				"LOG_LEVEL must be one of panic, fatal, error, warn, warning, info, debug, trace",
+				"OIDC_CLIENT_IDS is required with OIDC_PROVIDERS",
+				"JWT_KEYS is required unless JWT_LEGACY_ENABLED is true",
+				"OIDC_REDIRECT_URL must be a URL such as https://example.com",
+			},
+		},
+		{
+			name:          "when_flag_is_unknown_should_get_error",
+			args:          config.Args{"--no-such-flag"},
+			expectedError: "unknown flag: --no-such-flag",
+		},
+		{
+			name:          "when_config_file_is_missing_should_get_error",
+			args:          config.Args{"--config", "/not/exist/config.yaml"},
+			expectedError: "read config file /not/exist/config.yaml failed",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Setenv(config.ConfigFileEnv, "")
+			for key := range required {
+				t.Setenv(key, "")
+			}
+			for key, value := range test.env {
+				t.Setenv(key, value)
+			}
+
+			cfg, err := config.Provide(logger, test.args)
+			assert.Nil(t, cfg)
+
+			if test.expectedError != "" {
+				assert.ErrorContains(t, err, test.expectedError)
+				return
+			}
+
+			var validationErr *config.ValidationError
+			if assert.ErrorAs(t, err, &validationErr) {
+				assert.ElementsMatch(t, test.expectedProblems, validationErr.Problems)
+			}
+		})
+	}
+}
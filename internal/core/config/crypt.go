@@ -0,0 +1,87 @@
+package config
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// EncryptedExt ends the name of an encrypted config file, after the extension
+// of its format, e.g. config.yaml.enc or app.env.enc.
+const EncryptedExt = ".enc"
+
+// ReadKeyFile reads the key of encrypted config files: 32 random bytes, base64
+// encoded as by `openssl rand -base64 32`.
+func ReadKeyFile(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read key file %s failed: %v", path, err)
+	}
+
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(data)))
+	if err != nil {
+		return nil, fmt.Errorf("key file %s is not base64: %v", path, err)
+	}
+	if len(key) != 32 {
+		return nil, fmt.Errorf("key file %s holds %d bytes, want 32", path, len(key))
+	}
+
+	return key, nil
+}
+
+// Encrypt seals a config file with AES-256-GCM, returning the base64 encoded
+// nonce and ciphertext.
+func Encrypt(plaintext, key []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	sealed := gcm.Seal(nonce, nonce, plaintext, nil)
+
+	return []byte(base64.StdEncoding.EncodeToString(sealed) + "\n"), nil
+}
+
+// Decrypt opens a config file sealed by Encrypt.
+func Decrypt(data, key []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(data)))
+	if err != nil {
+		return nil, fmt.Errorf("not base64: %v", err)
+	}
+	if len(sealed) < gcm.NonceSize() {
+		return nil, errors.New("too short")
+	}
+
+	nonce, ciphertext := sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():]
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		// Tampered with or encrypted with another key.
+		return nil, errors.New("message authentication failed")
+	}
+
+	return plaintext, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
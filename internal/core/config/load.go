@@ -0,0 +1,206 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+const (
+	// ConfigFileEnv is the env variable naming the config file, overridden
+	// by the --config flag.
+	ConfigFileEnv = "ENV_FILE_PATH"
+	// ConfigKeyFileEnv is the env variable naming the key file of an
+	// encrypted config file, overridden by the --config-key-file flag.
+	ConfigKeyFileEnv = "CONFIG_KEY_FILE"
+)
+
+// Args are the command line arguments of the application. Every key has a
+// flag, e.g. --db.host or --secret-key, taking precedence over every other
+// source.
+type Args []string
+
+type field struct {
+	key    string // e.g. DB.HOST
+	index  []int  // of the struct field, for reflect.Value.FieldByIndex
+	kind   reflect.Kind
+	def    any
+	reload bool
+	secret bool
+}
+
+// envName returns the env variable of key, e.g. DB_HOST for DB.HOST.
+func envName(key string) string {
+	return strings.ReplaceAll(key, ".", "_")
+}
+
+// flagName returns the flag of key, e.g. redis.sentinel-master for
+// REDIS.SENTINEL_MASTER.
+func flagName(key string) string {
+	return strings.ToLower(strings.ReplaceAll(key, "_", "-"))
+}
+
+// fields lists the leaf keys of dest, a Configuration or one of its nested
+// structs, with their values as defaults.
+func fields(dest reflect.Value, parts ...string) []field {
+	var fs []field
+	for i := 0; i < dest.NumField(); i++ {
+		sf := dest.Type().Field(i)
+		tv, ok := sf.Tag.Lookup("mapstructure")
+		if !ok {
+			continue
+		}
+
+		fv := dest.Field(i)
+		if fv.Kind() == reflect.Struct {
+			for _, f := range fields(fv, append(parts, tv)...) {
+				f.index = append([]int{i}, f.index...)
+				fs = append(fs, f)
+			}
+			continue
+		}
+
+		fs = append(fs, field{
+			key:    strings.Join(append(parts, tv), "."),
+			index:  []int{i},
+			kind:   fv.Kind(),
+			def:    fv.Interface(),
+			reload: sf.Tag.Get("reload") == "true",
+			secret: sf.Tag.Get("secret") == "true",
+		})
+	}
+
+	return fs
+}
+
+// newViper layers, in increasing precedence, the defaults, the config file,
+// the environment and the flags of args. It also returns the config file, if
+// any.
+func newViper(args Args) (*viper.Viper, string, error) {
+	v := viper.New()
+	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+	v.AutomaticEnv()
+
+	flags := pflag.NewFlagSet("go-fiber-api", pflag.ContinueOnError)
+	configFile := flags.String("config", os.Getenv(ConfigFileEnv), "config file, YAML or an env file of KEY=value lines, encrypted when ending in "+EncryptedExt)
+	keyFile := flags.String("config-key-file", os.Getenv(ConfigKeyFileEnv), "key file of an encrypted config file")
+
+	keys := fields(reflect.ValueOf(defaults()))
+	for _, f := range keys {
+		v.SetDefault(f.key, f.def)
+		if err := v.BindEnv(f.key); err != nil {
+			return nil, "", fmt.Errorf("bind env %s failed: %v", envName(f.key), err)
+		}
+
+		name, usage := flagName(f.key), "overrides "+envName(f.key)
+		switch f.kind {
+		case reflect.Bool:
+			flags.Bool(name, false, usage)
+		case reflect.Int:
+			flags.Int(name, 0, usage)
+		case reflect.Float64:
+			flags.Float64(name, 0, usage)
+		default:
+			flags.String(name, "", usage)
+		}
+		// Only flags given on the command line override other sources.
+		if err := v.BindPFlag(f.key, flags.Lookup(name)); err != nil {
+			return nil, "", fmt.Errorf("bind flag %s failed: %v", name, err)
+		}
+	}
+
+	if err := flags.Parse(args); err != nil {
+		return nil, "", err
+	}
+
+	if *configFile != "" {
+		if err := readFile(v, *configFile, *keyFile, keys); err != nil {
+			return nil, "", err
+		}
+	}
+
+	return v, *configFile, nil
+}
+
+// fileType returns the format of the config file at path, "yaml", "json",
+// "toml" or "env", whether it is encrypted or not.
+func fileType(path string) string {
+	switch filepath.Ext(strings.TrimSuffix(strings.ToLower(path), EncryptedExt)) {
+	case ".yaml", ".yml":
+		return "yaml"
+	case ".json":
+		return "json"
+	case ".toml":
+		return "toml"
+	default:
+		return "env"
+	}
+}
+
+// readFile merges a YAML, JSON or TOML file, whose keys nest like the
+// Configuration, or an env file, whose keys are the env variables. A file
+// ending in EncryptedExt is decrypted with the key of keyFile first.
+func readFile(v *viper.Viper, path, keyFile string, keys []field) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("read config file %s failed: %v", path, err)
+	}
+
+	if strings.HasSuffix(strings.ToLower(path), EncryptedExt) {
+		if keyFile == "" {
+			return fmt.Errorf("config file %s is encrypted, %s or --config-key-file is required", path, ConfigKeyFileEnv)
+		}
+
+		key, err := ReadKeyFile(keyFile)
+		if err != nil {
+			return err
+		}
+
+		data, err = Decrypt(data, key)
+		if err != nil {
+			return fmt.Errorf("decrypt config file %s failed: %v", path, err)
+		}
+	}
+
+	typ := fileType(path)
+	if typ != "env" {
+		v.SetConfigType(typ)
+		if err := v.ReadConfig(bytes.NewReader(data)); err != nil {
+			return fmt.Errorf("read config file %s failed: %v", path, err)
+		}
+
+		return nil
+	}
+
+	env := viper.New()
+	env.SetConfigType("env")
+	if err := env.ReadConfig(bytes.NewReader(data)); err != nil {
+		return fmt.Errorf("read config file %s failed: %v", path, err)
+	}
+
+	values := map[string]any{}
+	for _, f := range keys {
+		name := envName(f.key)
+		if !env.IsSet(name) {
+			continue
+		}
+
+		m := values
+		parts := strings.Split(f.key, ".")
+		for _, part := range parts[:len(parts)-1] {
+			if _, ok := m[part].(map[string]any); !ok {
+				m[part] = map[string]any{}
+			}
+			m = m[part].(map[string]any)
+		}
+		m[parts[len(parts)-1]] = env.Get(name)
+	}
+
+	return v.MergeConfigMap(values)
+}
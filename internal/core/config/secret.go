@@ -0,0 +1,62 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"reflect"
+	"strings"
+)
+
+// secretProviders resolve the secret references of their URL scheme.
+var secretProviders = map[string]func(ref *url.URL) (string, error){
+	"file": fileSecret,
+}
+
+// fileSecret reads file:///run/secrets/db_pass, the way Docker and Kubernetes
+// mount secrets, dropping the trailing newline.
+func fileSecret(ref *url.URL) (string, error) {
+	if ref.Host != "" && ref.Host != "localhost" {
+		return "", fmt.Errorf("file reference must be absolute, e.g. file:///run/secrets/name")
+	}
+
+	data, err := os.ReadFile(ref.Path)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
+
+// resolveSecrets replaces the values of the keys tagged secret:"true" that
+// reference a secret, e.g. file:///run/secrets/db_pass, with the secret. Any
+// other value is kept as is.
+func resolveSecrets(cfg *Configuration) error {
+	dest := reflect.ValueOf(cfg).Elem()
+
+	for _, f := range fields(dest) {
+		if !f.secret || f.kind != reflect.String {
+			continue
+		}
+
+		v := dest.FieldByIndex(f.index)
+		scheme, _, ok := strings.Cut(v.String(), "://")
+		provider, known := secretProviders[scheme]
+		if !ok || !known {
+			continue
+		}
+
+		ref, err := url.Parse(v.String())
+		if err != nil {
+			return fmt.Errorf("resolve secret of %s failed: %v", envName(f.key), err)
+		}
+
+		secret, err := provider(ref)
+		if err != nil {
+			return fmt.Errorf("resolve secret of %s failed: %v", envName(f.key), err)
+		}
+		v.SetString(secret)
+	}
+
+	return nil
+}
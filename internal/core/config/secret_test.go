@@ -0,0 +1,214 @@
+package config_test
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"go-fiber-api/internal/core/config"
+	"go-fiber-api/internal/core/lifecycle"
+	"go-fiber-api/internal/wrapper/logx"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadConfig_Secrets(t *testing.T) {
+	logger := logx.Provide(lifecycle.New())
+
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "db_pass"), []byte("s3cr3t\n"), 0o600))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "secret_key"), []byte("signing-key"), 0o600))
+
+	tests := []struct {
+		name          string
+		env           map[string]string
+		expectedError string
+		check         func(t *testing.T, cfg *config.Configuration)
+	}{
+		{
+			name: "when_secret_references_a_file_should_read_it",
+			env: map[string]string{
+				"DB_PASS":    "file://" + filepath.Join(dir, "db_pass"),
+				"SECRET_KEY": "file://" + filepath.Join(dir, "secret_key"),
+			},
+			check: func(t *testing.T, cfg *config.Configuration) {
+				assert.Equal(t, "s3cr3t", cfg.DB.Pass)
+				assert.Equal(t, "signing-key", cfg.SecretKey)
+			},
+		},
+		{
+			name: "when_value_is_not_a_reference_should_keep_it",
+			env: map[string]string{
+				"REDIS_PASSWORD":        "file:password",
+				"TLS_CLIENT_PRINCIPALS": "file://" + filepath.Join(dir, "db_pass"),
+			},
+			check: func(t *testing.T, cfg *config.Configuration) {
+				assert.Equal(t, "file:password", cfg.Redis.Password)
+				assert.Equal(t, "file://"+filepath.Join(dir, "db_pass"), cfg.TLSClientPrincipals)
+			},
+		},
+		{
+			name:          "when_secret_file_is_missing_should_get_error",
+			env:           map[string]string{"REDIS_PASSWORD": "file:///not/exist/redis_password"},
+			expectedError: "resolve secret of REDIS_PASSWORD failed: open /not/exist/redis_password",
+		},
+		{
+			name:          "when_secret_file_is_relative_should_get_error",
+			env:           map[string]string{"DB_PASS": "file://secrets/db_pass"},
+			expectedError: "resolve secret of DB_PASS failed: file reference must be absolute",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(config.ConfigFileEnv, "")
+			for key, value := range required {
+				t.Setenv(key, value)
+			}
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+
+			// test logic
+			cfg, err := config.Provide(logger, nil)
+			if tt.expectedError != "" {
+				assert.ErrorContains(t, err, tt.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			tt.check(t, cfg)
+		})
+	}
+
+	t.Run("resolved_secrets_should_be_masked_in_logs", func(t *testing.T) {
+		t.Setenv(config.ConfigFileEnv, "")
+		for key, value := range required {
+			t.Setenv(key, value)
+		}
+		t.Setenv("DB_PASS", "file://"+filepath.Join(dir, "db_pass"))
+		t.Setenv("OIDC_CLIENT_SECRETS", "corp=corp-client-secret, partner=partner-client-secret")
+		t.Setenv("REDIS_PASSWORD", "pass")
+		_, err := config.Provide(logger, nil)
+		assert.NoError(t, err)
+
+		var buf bytes.Buffer
+		logger.SetOutput(&buf)
+		defer logger.SetOutput(os.Stdout)
+
+		// test logic
+		logger.Warn("connecting with s3cr3t, corp-client-secret and partner-client-secret")
+		assert.NotContains(t, buf.String(), "s3cr3t")
+		assert.NotContains(t, buf.String(), "client-secret")
+		logger.Warn("pass through")
+		assert.Contains(t, buf.String(), "pass through", "too short to be masked")
+	})
+
+	t.Run("secrets_of_a_previous_load_should_not_be_masked", func(t *testing.T) {
+		t.Setenv(config.ConfigFileEnv, "")
+		for key, value := range required {
+			t.Setenv(key, value)
+		}
+		_, err := config.Provide(logger, nil)
+		assert.NoError(t, err)
+
+		var buf bytes.Buffer
+		logger.SetOutput(&buf)
+		defer logger.SetOutput(os.Stdout)
+
+		// test logic
+		logger.Warn("s3cr3t is no longer a secret")
+		assert.Contains(t, buf.String(), "s3cr3t")
+	})
+}
+
+func TestLoadConfig_Encrypted(t *testing.T) {
+	logger := logx.Provide(lifecycle.New())
+
+	dir := t.TempDir()
+	key := make([]byte, 32)
+	_, err := rand.Read(key)
+	assert.NoError(t, err)
+	keyFile := filepath.Join(dir, "config.key")
+	assert.NoError(t, os.WriteFile(keyFile, []byte(base64.StdEncoding.EncodeToString(key)+"\n"), 0o600))
+
+	otherKey := filepath.Join(dir, "other.key")
+	assert.NoError(t, os.WriteFile(otherKey, []byte(base64.StdEncoding.EncodeToString(make([]byte, 32))), 0o600))
+	shortKey := filepath.Join(dir, "short.key")
+	assert.NoError(t, os.WriteFile(shortKey, []byte(base64.StdEncoding.EncodeToString(make([]byte, 16))), 0o600))
+
+	encrypt := func(name, content string) string {
+		data, err := config.Encrypt([]byte(content), key)
+		assert.NoError(t, err)
+
+		path := filepath.Join(dir, name)
+		assert.NoError(t, os.WriteFile(path, data, 0o600))
+		return path
+	}
+	yamlFile := encrypt("config.yaml.enc", "port: \"7000\"\nsecret_key: from-yaml\n")
+	envFile := encrypt("app.env.enc", "PORT=7100\nDB_PASS=from-env-file\n")
+
+	tests := []struct {
+		name          string
+		args          config.Args
+		env           map[string]string
+		expectedError string
+		check         func(t *testing.T, cfg *config.Configuration)
+	}{
+		{
+			name: "when_yaml_file_is_encrypted_should_decrypt_it",
+			args: config.Args{"--config", yamlFile, "--config-key-file", keyFile},
+			env:  map[string]string{"SECRET_KEY": ""},
+			check: func(t *testing.T, cfg *config.Configuration) {
+				assert.Equal(t, "7000", cfg.Port)
+				assert.Equal(t, "from-yaml", cfg.SecretKey)
+			},
+		},
+		{
+			name: "when_env_file_is_encrypted_should_decrypt_it",
+			env:  map[string]string{config.ConfigFileEnv: envFile, config.ConfigKeyFileEnv: keyFile},
+			check: func(t *testing.T, cfg *config.Configuration) {
+				assert.Equal(t, "7100", cfg.Port)
+				assert.Equal(t, "from-env-file", cfg.DB.Pass)
+			},
+		},
+		{
+			name:          "when_key_file_is_missing_should_get_error",
+			args:          config.Args{"--config", yamlFile},
+			expectedError: "is encrypted, CONFIG_KEY_FILE or --config-key-file is required",
+		},
+		{
+			name:          "when_key_is_wrong_should_get_error",
+			args:          config.Args{"--config", yamlFile, "--config-key-file", otherKey},
+			expectedError: "decrypt config file " + yamlFile + " failed: message authentication failed",
+		},
+		{
+			name:          "when_key_is_too_short_should_get_error",
+			args:          config.Args{"--config", yamlFile, "--config-key-file", shortKey},
+			expectedError: "holds 16 bytes, want 32",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(config.ConfigFileEnv, "")
+			t.Setenv(config.ConfigKeyFileEnv, "")
+			for key, value := range required {
+				t.Setenv(key, value)
+			}
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+
+			// test logic
+			cfg, err := config.Provide(logger, tt.args)
+			if tt.expectedError != "" {
+				assert.ErrorContains(t, err, tt.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			tt.check(t, cfg)
+		})
+	}
+}
@@ -0,0 +1,139 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// ValidationError lists every invalid value of a Configuration, by env
+// variable.
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return "invalid configuration: " + strings.Join(e.Problems, "; ")
+}
+
+// Validate checks the validate tags of c, reporting all the invalid values at
+// once.
+func (c *Configuration) Validate() error {
+	v := validator.New()
+	v.RegisterTagNameFunc(func(f reflect.StructField) string {
+		return f.Tag.Get("mapstructure")
+	})
+	_ = v.RegisterValidation("origins", validOrigins)
+
+	err := v.Struct(c)
+
+	var fieldErrs validator.ValidationErrors
+	if !errors.As(err, &fieldErrs) {
+		return err
+	}
+
+	problems := make([]string, 0, len(fieldErrs))
+	for _, fe := range fieldErrs {
+		problems = append(problems, describe(fe))
+	}
+
+	return &ValidationError{Problems: problems}
+}
+
+func describe(fe validator.FieldError) string {
+	name := envName(trimRoot(fe.Namespace()))
+	param := fe.Param()
+
+	switch fe.Tag() {
+	case "required":
+		return name + " is required"
+	case "required_if", "required_unless":
+		field, value, _ := strings.Cut(param, " ")
+		cond := "when"
+		if fe.Tag() == "required_unless" {
+			cond = "unless"
+		}
+		return fmt.Sprintf("%s is required %s %s is %s", name, cond, sibling(fe, field), value)
+	case "required_with":
+		return fmt.Sprintf("%s is required with %s", name, sibling(fe, param))
+	case "required_without":
+		return fmt.Sprintf("%s is required without %s", name, sibling(fe, param))
+	case "required_without_all":
+		var others []string
+		for _, field := range strings.Fields(param) {
+			others = append(others, sibling(fe, field))
+		}
+		return fmt.Sprintf("%s is required without %s", name, strings.Join(others, " or "))
+	case "oneof":
+		return fmt.Sprintf("%s must be one of %s", name, strings.Join(strings.Fields(param), ", "))
+	case "numeric":
+		return name + " must be a number"
+	case "gt":
+		return fmt.Sprintf("%s must be greater than %s", name, param)
+	case "gte":
+		return fmt.Sprintf("%s must be at least %s", name, param)
+	case "lte":
+		return fmt.Sprintf("%s must be at most %s", name, param)
+	case "url":
+		return name + " must be a URL such as https://example.com"
+	case "origins":
+		return name + " must be comma separated origins such as https://example.com"
+	default:
+		return fmt.Sprintf("%s failed the %s check", name, fe.Tag())
+	}
+}
+
+// validOrigins checks a comma separated list of CORS origins the way the
+// cors middleware does, which panics on an invalid one.
+func validOrigins(fl validator.FieldLevel) bool {
+	for _, origin := range strings.Split(fl.Field().String(), ",") {
+		origin = strings.TrimSpace(origin)
+		if origin == "*" {
+			continue
+		}
+
+		u, err := url.Parse(origin)
+		if err != nil || u.Scheme == "" || u.Host == "" || (u.Path != "" && u.Path != "/") || u.RawQuery != "" || u.Fragment != "" {
+			return false
+		}
+	}
+
+	return true
+}
+
+// trimRoot drops the "Configuration." prefix of a namespace.
+func trimRoot(namespace string) string {
+	_, key, _ := strings.Cut(namespace, ".")
+	return key
+}
+
+// sibling returns the env variable of the Go field name next to the field of
+// fe, as found in validate tag parameters. Fields of nested structs are named
+// by their path, such as JWT.Keys.
+func sibling(fe validator.FieldError, name string) string {
+	goPath := strings.Split(trimRoot(fe.StructNamespace()), ".")
+	keyPath := strings.Split(trimRoot(fe.Namespace()), ".")
+
+	t := reflect.TypeOf(Configuration{})
+	for _, part := range goPath[:len(goPath)-1] {
+		f, _ := t.FieldByName(part)
+		t = f.Type
+	}
+
+	keys := slices.Clone(keyPath[:len(keyPath)-1])
+	for _, part := range strings.Split(name, ".") {
+		f, ok := t.FieldByName(part)
+		if !ok {
+			return name
+		}
+		keys = append(keys, f.Tag.Get("mapstructure"))
+		t = f.Type
+	}
+
+	return envName(strings.Join(keys, "."))
+}
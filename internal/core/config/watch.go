@@ -0,0 +1,255 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"go-fiber-api/internal/core/lifecycle"
+	"go-fiber-api/internal/wrapper/logx"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/sirupsen/logrus"
+)
+
+// Change describes a reload, Keys being the env variables of the reloadable
+// keys whose value changed, e.g. CORS_ALLOWED_ORIGINS.
+type Change struct {
+	Old  *Configuration
+	New  *Configuration
+	Keys []string
+}
+
+// Has reports whether the value of key, an env variable, changed.
+func (c Change) Has(key string) bool {
+	return slices.Contains(c.Keys, key)
+}
+
+type subscription struct {
+	keys []string
+	fn   func(Change)
+}
+
+// Watcher reloads the configuration when its config file changes. Only the
+// keys tagged reload:"true" take effect at runtime: a change to any other key
+// is logged and waits for a restart.
+type Watcher struct {
+	args  Args
+	file  string
+	log   *logx.LogX
+	entry *logrus.Entry
+
+	current atomic.Pointer[Configuration]
+
+	// Watches the directory of file while started.
+	watcher *fsnotify.Watcher
+	done    chan struct{}
+
+	// Serializes reloads, each one comparing against the previous result.
+	reloadMu sync.Mutex
+
+	mu   sync.Mutex
+	subs map[int]subscription
+	next int
+}
+
+// NewWatcher returns a Watcher starting from cfg, the configuration loaded
+// from args.
+func NewWatcher(cfg *Configuration, log *logx.LogX, args Args) (*Watcher, error) {
+	_, file, err := newViper(args)
+	if err != nil {
+		return nil, err
+	}
+
+	w := &Watcher{
+		args: args,
+		file: file,
+		log:  log,
+		entry: log.WithFields(logrus.Fields{
+			"component": "config",
+			"module":    "config",
+		}),
+		subs: make(map[int]subscription),
+	}
+	w.current.Store(cfg)
+
+	return w, nil
+}
+
+// ProvideWatcher returns a Watcher of the config file, watched from the
+// moment lc starts. There is nothing to watch without a config file: the
+// environment and the flags cannot change at runtime.
+func ProvideWatcher(cfg *Configuration, log *logx.LogX, args Args, lc *lifecycle.Lifecycle) (*Watcher, error) {
+	w, err := NewWatcher(cfg, log, args)
+	if err != nil {
+		return nil, err
+	}
+
+	if w.file == "" {
+		return w, nil
+	}
+
+	lc.Append(lifecycle.Hook{
+		Name: "config watcher",
+		OnStart: func(context.Context) error {
+			return w.watch()
+		},
+		OnStop: func(context.Context) error {
+			err := w.watcher.Close()
+			<-w.done
+			return err
+		},
+	})
+
+	return w, nil
+}
+
+// watch reloads on every write of the config file, until the watcher is
+// closed. Its directory is watched rather than the file, which editors and
+// Kubernetes config maps replace instead of writing it.
+func (w *Watcher) watch() error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("watch config file failed: %v", err)
+	}
+	if err := watcher.Add(filepath.Dir(w.file)); err != nil {
+		_ = watcher.Close()
+		return fmt.Errorf("watch config file failed: %v", err)
+	}
+	w.watcher, w.done = watcher, make(chan struct{})
+
+	go func() {
+		defer close(w.done)
+
+		file := filepath.Clean(w.file)
+		// The file a config map links to, which is replaced by another one.
+		real, _ := filepath.EvalSymlinks(file)
+		for {
+			select {
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+
+				current, _ := filepath.EvalSymlinks(file)
+				written := filepath.Clean(event.Name) == file && event.Has(fsnotify.Write|fsnotify.Create)
+				if !written && (current == "" || current == real) {
+					continue
+				}
+				real = current
+
+				if err := w.Reload(); err != nil {
+					w.entry.Errorf("configuration reload rejected, keeping the current one: %v", err)
+				}
+			case err, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+				w.entry.Errorf("watch config file failed: %v", err)
+			}
+		}
+	}()
+
+	w.entry.Infof("watching %s for configuration changes", w.file)
+
+	return nil
+}
+
+// Current returns the configuration with the latest reloadable values.
+func (w *Watcher) Current() *Configuration {
+	return w.current.Load()
+}
+
+// Subscribe calls fn after every reload changing one of keys, env variables
+// such as CORS_ALLOWED_ORIGINS, or any reloadable key when none is given. fn
+// is called from the reloading goroutine, one reload at a time. The returned
+// function cancels the subscription. A nil Watcher never calls fn.
+func (w *Watcher) Subscribe(fn func(Change), keys ...string) (cancel func()) {
+	if w == nil {
+		return func() {}
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	id := w.next
+	w.next++
+	w.subs[id] = subscription{keys: keys, fn: fn}
+
+	return func() {
+		w.mu.Lock()
+		defer w.mu.Unlock()
+
+		delete(w.subs, id)
+	}
+}
+
+// Reload reads the configuration again and applies its reloadable values,
+// notifying the subscribers. Nothing is applied if it is invalid.
+func (w *Watcher) Reload() error {
+	w.reloadMu.Lock()
+	defer w.reloadMu.Unlock()
+
+	// The secrets in use stay masked until a restart applies the new ones.
+	next, _, err := load(w.log, w.args, w.Current())
+	if err != nil {
+		return err
+	}
+
+	old := w.Current()
+	applied := *old
+
+	var changed, ignored, audit []string
+	from, to, dest := reflect.ValueOf(old).Elem(), reflect.ValueOf(next).Elem(), reflect.ValueOf(&applied).Elem()
+	for _, f := range fields(from) {
+		before, after := from.FieldByIndex(f.index), to.FieldByIndex(f.index)
+		if reflect.DeepEqual(before.Interface(), after.Interface()) {
+			continue
+		}
+
+		name := envName(f.key)
+		if !f.reload {
+			ignored = append(ignored, name)
+			continue
+		}
+
+		dest.FieldByIndex(f.index).Set(after)
+		changed = append(changed, name)
+		audit = append(audit, fmt.Sprintf("%s %q -> %q", name, fmt.Sprint(before), fmt.Sprint(after)))
+	}
+
+	if len(ignored) > 0 {
+		// Values are left out, some of these keys are secrets.
+		w.entry.WithField("keys", ignored).Warnf("configuration changes need a restart: %s", strings.Join(ignored, ", "))
+	}
+
+	if len(changed) == 0 {
+		return nil
+	}
+
+	w.current.Store(&applied)
+	w.entry.WithField("keys", changed).Infof("configuration reloaded: %s", strings.Join(audit, ", "))
+
+	w.notify(Change{Old: old, New: &applied, Keys: changed})
+
+	return nil
+}
+
+func (w *Watcher) notify(change Change) {
+	w.mu.Lock()
+	subs := make([]subscription, 0, len(w.subs))
+	for _, sub := range w.subs {
+		subs = append(subs, sub)
+	}
+	w.mu.Unlock()
+
+	for _, sub := range subs {
+		if len(sub.keys) == 0 || slices.ContainsFunc(sub.keys, change.Has) {
+			sub.fn(change)
+		}
+	}
+}
@@ -0,0 +1,237 @@
+package config_test
+
+import (
+	"bytes"
+	"context"
+	"go-fiber-api/internal/core/config"
+	"go-fiber-api/internal/core/lifecycle"
+	"go-fiber-api/internal/wrapper/logx"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWatcher_Reload(t *testing.T) {
+	tests := []struct {
+		name         string
+		env          map[string]string // changed before the reload
+		keys         []string          // subscribed to
+		expectedKeys []string          // of the change notified, none if nil
+		expectedErr  string
+		expectedLog  []string
+		check        func(t *testing.T, cfg *config.Configuration)
+	}{
+		{
+			name:         "when_reloadable_keys_change_should_apply_them_and_notify",
+			env:          map[string]string{"CORS_ALLOWED_ORIGINS": "https://example.com", "CACHE_TTL": "30"},
+			expectedKeys: []string{"CACHE_TTL", "CORS_ALLOWED_ORIGINS"},
+			expectedLog:  []string{`configuration reloaded: CACHE_TTL "60" -> "30", CORS_ALLOWED_ORIGINS "" -> "https://example.com"`},
+			check: func(t *testing.T, cfg *config.Configuration) {
+				assert.Equal(t, 30, cfg.CacheTTL)
+				assert.Equal(t, "https://example.com", cfg.CORS.AllowedOrigins)
+			},
+		},
+		{
+			name:         "when_other_keys_change_should_keep_them_until_restart",
+			env:          map[string]string{"PORT": "9000", "DB_HOST": "db.internal", "RATE_LIMIT_MAX": "10"},
+			expectedKeys: []string{"RATE_LIMIT_MAX"},
+			expectedLog: []string{
+				"configuration changes need a restart: PORT, DB_HOST",
+				`configuration reloaded: RATE_LIMIT_MAX "0" -> "10"`,
+			},
+			check: func(t *testing.T, cfg *config.Configuration) {
+				assert.Equal(t, "80", cfg.Port)
+				assert.Equal(t, "localhost", cfg.DB.Host)
+				assert.Equal(t, 10, cfg.RateLimitMax)
+			},
+		},
+		{
+			name:        "when_subscribed_keys_do_not_change_should_not_notify",
+			env:         map[string]string{"LOG_LEVEL": "warn"},
+			keys:        []string{"CACHE_TTL"},
+			expectedLog: []string{`configuration reloaded: LOG_LEVEL "" -> "warn"`},
+			check: func(t *testing.T, cfg *config.Configuration) {
+				assert.Equal(t, "warn", cfg.LogLevel)
+			},
+		},
+		{
+			name: "when_nothing_changes_should_not_notify",
+		},
+		{
+			name:        "when_config_is_invalid_should_keep_the_current_one",
+			env:         map[string]string{"CACHE_TTL": "0", "CORS_ALLOWED_ORIGINS": "https://example.com"},
+			expectedErr: "CACHE_TTL must be greater than 0",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(config.ConfigFileEnv, "")
+			for key, value := range required {
+				t.Setenv(key, value)
+			}
+
+			logger := logx.Provide(lifecycle.New())
+			hook := test.NewLocal(logger.Logger)
+
+			cfg, err := config.Provide(logger, nil)
+			assert.NoError(t, err)
+			w, err := config.NewWatcher(cfg, logger, nil)
+			assert.NoError(t, err)
+
+			var changes []config.Change
+			w.Subscribe(func(change config.Change) {
+				changes = append(changes, change)
+			}, tt.keys...)
+
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+			hook.Reset()
+
+			// test logic
+			err = w.Reload()
+			if tt.expectedErr != "" {
+				assert.ErrorContains(t, err, tt.expectedErr)
+				assert.Same(t, cfg, w.Current())
+				assert.Empty(t, changes)
+				return
+			}
+			assert.NoError(t, err)
+
+			if tt.expectedKeys == nil {
+				assert.Empty(t, changes)
+			} else if assert.Len(t, changes, 1) {
+				assert.Equal(t, tt.expectedKeys, changes[0].Keys)
+				assert.Same(t, cfg, changes[0].Old)
+				assert.Same(t, w.Current(), changes[0].New)
+			}
+
+			var messages []string
+			for _, entry := range hook.AllEntries() {
+				messages = append(messages, entry.Message)
+			}
+			assert.Equal(t, tt.expectedLog, messages)
+
+			if tt.check != nil {
+				tt.check(t, w.Current())
+			}
+		})
+	}
+}
+
+func TestWatcher_Subscribe(t *testing.T) {
+	t.Setenv(config.ConfigFileEnv, "")
+	for key, value := range required {
+		t.Setenv(key, value)
+	}
+
+	logger := logx.Provide(lifecycle.New())
+	cfg, err := config.Provide(logger, nil)
+	assert.NoError(t, err)
+	w, err := config.NewWatcher(cfg, logger, nil)
+	assert.NoError(t, err)
+
+	var calls int
+	cancel := w.Subscribe(func(config.Change) {
+		calls++
+	})
+
+	t.Setenv("CACHE_TTL", "30")
+	assert.NoError(t, w.Reload())
+	assert.Equal(t, 1, calls)
+
+	cancel()
+	t.Setenv("CACHE_TTL", "40")
+	assert.NoError(t, w.Reload())
+	assert.Equal(t, 1, calls)
+
+	// Components built without a Watcher subscribe to nothing.
+	var none *config.Watcher
+	none.Subscribe(func(config.Change) {})()
+}
+
+func TestWatcher_Reload_Secrets(t *testing.T) {
+	t.Setenv(config.ConfigFileEnv, "")
+	for key, value := range required {
+		t.Setenv(key, value)
+	}
+	t.Setenv("DB_PASS", "old-db-pass")
+
+	logger := logx.Provide(lifecycle.New())
+	cfg, err := config.Provide(logger, nil)
+	assert.NoError(t, err)
+	w, err := config.NewWatcher(cfg, logger, nil)
+	assert.NoError(t, err)
+
+	t.Setenv("DB_PASS", "new-db-pass")
+	assert.NoError(t, w.Reload())
+
+	var buf bytes.Buffer
+	logger.SetOutput(&buf)
+	defer logger.SetOutput(os.Stdout)
+
+	// test logic
+	// The old password is still in use until a restart.
+	logger.Warn("connecting with old-db-pass, then new-db-pass")
+	assert.NotContains(t, buf.String(), "db-pass")
+}
+
+func TestProvideWatcher_File(t *testing.T) {
+	for key, value := range required {
+		t.Setenv(key, value)
+	}
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	assert.NoError(t, os.WriteFile(path, []byte("cors:\n  allowed_origins: https://a.example.com\n"), 0o600))
+	t.Setenv(config.ConfigFileEnv, path)
+
+	logger := logx.Provide(lifecycle.New())
+	cfg, err := config.Provide(logger, nil)
+	assert.NoError(t, err)
+
+	lc := lifecycle.New()
+	w, err := config.ProvideWatcher(cfg, logger, nil, lc)
+	assert.NoError(t, err)
+
+	changes := make(chan config.Change, 10)
+	w.Subscribe(func(change config.Change) {
+		changes <- change
+	})
+	goroutines := runtime.NumGoroutine()
+	assert.NoError(t, lc.Start(context.Background()))
+
+	// test logic
+	assert.NoError(t, os.WriteFile(path, []byte("cors:\n  allowed_origins: https://b.example.com\nport: \"9000\"\n"), 0o600))
+	select {
+	case change := <-changes:
+		assert.Equal(t, []string{"CORS_ALLOWED_ORIGINS"}, change.Keys)
+		assert.Equal(t, "https://b.example.com", w.Current().CORS.AllowedOrigins)
+		assert.Equal(t, "80", w.Current().Port)
+	case <-time.After(5 * time.Second):
+		t.Fatal("config file change not reloaded")
+	}
+
+	// Replaced rather than written, the way editors save it.
+	replacement := filepath.Join(filepath.Dir(path), "config.yaml.tmp")
+	assert.NoError(t, os.WriteFile(replacement, []byte("cors:\n  allowed_origins: https://d.example.com\n"), 0o600))
+	assert.NoError(t, os.Rename(replacement, path))
+	select {
+	case <-changes:
+		assert.Equal(t, "https://d.example.com", w.Current().CORS.AllowedOrigins)
+	case <-time.After(5 * time.Second):
+		t.Fatal("config file replacement not reloaded")
+	}
+
+	// Changes are ignored once stopped, the file no longer being watched.
+	assert.NoError(t, lc.Stop(context.Background()))
+	assert.LessOrEqual(t, runtime.NumGoroutine(), goroutines)
+	assert.NoError(t, os.WriteFile(path, []byte("cors:\n  allowed_origins: https://c.example.com\n"), 0o600))
+	assert.Never(t, func() bool {
+		return len(changes) > 0
+	}, 300*time.Millisecond, 20*time.Millisecond)
+}
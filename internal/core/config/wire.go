@@ -12,9 +12,10 @@ import (
 
 var ProviderSet = wire.NewSet(
 	Provide,
+	ProvideWatcher,
 )
 
-func Wire(logx *logx.LogX) (*Configuration, error) {
-	wire.Build(ProviderSet)
+func Wire(logx *logx.LogX, args Args) (*Configuration, error) {
+	wire.Build(Provide)
 	return &Configuration{}, nil
 }
@@ -13,8 +13,11 @@ import (
 
 // Injectors from wire.go:
 
-func Wire(logx2 *logx.LogX) (*Configuration, error) {
-	configuration := Provide(logx2)
+func Wire(logx2 *logx.LogX, args Args) (*Configuration, error) {
+	configuration, err := Provide(logx2, args)
+	if err != nil {
+		return nil, err
+	}
 	return configuration, nil
 }
 
@@ -22,4 +25,5 @@ func Wire(logx2 *logx.LogX) (*Configuration, error) {
 
 var ProviderSet = wire.NewSet(
 	Provide,
+	ProvideWatcher,
 )
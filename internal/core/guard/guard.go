@@ -0,0 +1,150 @@
+//go:generate mockgen -source=guard.go -mock_names=Guard=MockGuard -destination=../../mock/mock_guard.go -package=mock
+package guard
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-fiber-api/internal/core/config"
+	"go-fiber-api/internal/wrapper/logx"
+	"go-fiber-api/internal/wrapper/redis"
+	"math"
+	"time"
+)
+
+// ErrTooManyAttempts matches the *WaitError of Check.
+var ErrTooManyAttempts = errors.New("too many failed attempts")
+
+// WaitError refuses an attempt until RetryAfter has elapsed.
+type WaitError struct {
+	RetryAfter time.Duration
+}
+
+func (e *WaitError) Error() string {
+	return fmt.Sprintf("%v, retry in %v", ErrTooManyAttempts, e.RetryAfter)
+}
+
+func (e *WaitError) Is(target error) bool {
+	return target == ErrTooManyAttempts
+}
+
+// RetryAfterSeconds is the Retry-After header value of e, in whole seconds.
+func (e *WaitError) RetryAfterSeconds() int {
+	return int(math.Ceil(e.RetryAfter.Seconds()))
+}
+
+// failScript counts a failure, the window starting at the first one.
+var failScript = redis.NewScript(`
+local failures = redis.call("INCR", KEYS[1])
+if failures == 1 then
+	redis.call("EXPIRE", KEYS[1], ARGV[1])
+end
+return failures
+`)
+
+// Guard counts the failed attempts of keys, such as a username or a client
+// IP, refusing their next attempts for a delay growing with the failures.
+// Redis errors are logged and let attempts through: an outage doesn't lock
+// everyone out.
+type Guard interface {
+	// Check returns a *WaitError while the attempts of any of keys are
+	// refused.
+	Check(ctx context.Context, keys ...string) error
+	// Fail records a failed attempt of key, returning its failures within
+	// the window, 0 when unknown. Past the free attempts, the attempts of key
+	// are refused for a delay doubling at each failure.
+	Fail(ctx context.Context, key string) int64
+	// Ban refuses the attempts of key for ttl.
+	Ban(ctx context.Context, key string, ttl time.Duration)
+	// Reset forgets the failures of key, after a successful attempt. A
+	// running delay or ban is kept.
+	Reset(ctx context.Context, key string)
+}
+
+type guardImpl struct {
+	rc           redis.Client
+	window       int // seconds
+	freeAttempts int64
+	baseDelay    time.Duration
+	maxDelay     time.Duration
+}
+
+func Provide(cfg *config.Configuration, rc redis.Client) Guard {
+	return &guardImpl{
+		rc:           rc,
+		window:       cfg.BruteForce.Window,
+		freeAttempts: int64(cfg.BruteForce.FreeAttempts),
+		baseDelay:    time.Duration(cfg.BruteForce.BaseDelay) * time.Second,
+		maxDelay:     time.Duration(cfg.BruteForce.MaxDelay) * time.Second,
+	}
+}
+
+// failuresKey and waitKey share a hash tag so both land on the same cluster
+// slot.
+func failuresKey(key string) string {
+	return fmt.Sprintf("guard:{%s}:failures", key)
+}
+
+func waitKey(key string) string {
+	return fmt.Sprintf("guard:{%s}:wait", key)
+}
+
+func (g *guardImpl) Check(ctx context.Context, keys ...string) error {
+	var wait time.Duration
+	for _, key := range keys {
+		ttl, err := g.rc.TTL(ctx, waitKey(key))
+		if err != nil {
+			logx.FromContext(ctx).Warnf("guard check %s: %v", key, err)
+			continue
+		}
+		wait = max(wait, ttl)
+	}
+
+	if wait > 0 {
+		return &WaitError{RetryAfter: wait}
+	}
+	return nil
+}
+
+func (g *guardImpl) Fail(ctx context.Context, key string) int64 {
+	res, err := g.rc.Eval(ctx, failScript, []string{failuresKey(key)}, g.window)
+	if err != nil {
+		logx.FromContext(ctx).Warnf("guard fail %s: %v", key, err)
+		return 0
+	}
+	failures, _ := res.(int64)
+
+	if delay := g.delay(failures); delay > 0 {
+		g.Ban(ctx, key, delay)
+	}
+
+	return failures
+}
+
+func (g *guardImpl) Ban(ctx context.Context, key string, ttl time.Duration) {
+	seconds := int(math.Ceil(ttl.Seconds()))
+	if err := g.rc.SetContext(ctx, waitKey(key), 1, seconds); err != nil {
+		logx.FromContext(ctx).Warnf("guard ban %s: %v", key, err)
+	}
+}
+
+func (g *guardImpl) Reset(ctx context.Context, key string) {
+	if err := g.rc.DelContext(ctx, failuresKey(key)); err != nil {
+		logx.FromContext(ctx).Warnf("guard reset %s: %v", key, err)
+	}
+}
+
+// delay is the wait after the failures of a key: none for the free attempts,
+// then the base delay doubling up to the max one.
+func (g *guardImpl) delay(failures int64) time.Duration {
+	over := failures - g.freeAttempts
+	if over <= 0 {
+		return 0
+	}
+
+	delay := g.baseDelay << min(over-1, 30)
+	if delay <= 0 || delay > g.maxDelay {
+		return g.maxDelay
+	}
+	return delay
+}
@@ -0,0 +1,126 @@
+package guard_test
+
+import (
+	"context"
+	"errors"
+	"go-fiber-api/internal/core/config"
+	"go-fiber-api/internal/core/guard"
+	"go-fiber-api/internal/core/lifecycle"
+	"go-fiber-api/internal/mock"
+	"go-fiber-api/internal/wrapper/redis"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func newGuard(t *testing.T) guard.Guard {
+	cfg := &config.Configuration{
+		Redis: config.RedisConfig{
+			Host: "localhost",
+			Port: "6379",
+			DB:   10,
+		},
+		BruteForce: config.BruteForceConfig{
+			Window:       60,
+			FreeAttempts: 2,
+			BaseDelay:    10,
+			MaxDelay:     30,
+		},
+	}
+
+	rc, err := redis.ProvideClient(cfg, nil, nil, lifecycle.New())
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	t.Cleanup(func() { _ = rc.Close() })
+
+	return guard.Provide(cfg, rc)
+}
+
+func retryAfter(t *testing.T, err error) time.Duration {
+	var waitErr *guard.WaitError
+	if !assert.ErrorAs(t, err, &waitErr) {
+		return 0
+	}
+	assert.ErrorIs(t, err, guard.ErrTooManyAttempts)
+
+	return waitErr.RetryAfter
+}
+
+func TestGuard_Fail(t *testing.T) {
+	ctx := context.Background()
+	g := newGuard(t)
+	key := "test:" + uuid.NewString()
+
+	tests := []struct {
+		name             string
+		expectedFailures int64
+		expectedWait     time.Duration
+	}{
+		{name: "when_attempt_is_free_should_not_wait", expectedFailures: 1},
+		{name: "when_last_free_attempt_should_not_wait", expectedFailures: 2},
+		{name: "when_attempts_are_used_should_wait_base_delay", expectedFailures: 3, expectedWait: 10 * time.Second},
+		{name: "when_failing_again_should_double_delay", expectedFailures: 4, expectedWait: 20 * time.Second},
+		{name: "when_delay_is_long_should_wait_max_delay", expectedFailures: 5, expectedWait: 30 * time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// test logic
+			failures := g.Fail(ctx, key)
+			assert.Equal(t, tt.expectedFailures, failures)
+
+			err := g.Check(ctx, key)
+			if tt.expectedWait == 0 {
+				assert.NoError(t, err)
+				return
+			}
+			assert.InDelta(t, tt.expectedWait, retryAfter(t, err), float64(time.Second))
+		})
+	}
+
+	t.Run("when_reset_should_count_again_but_keep_delay", func(t *testing.T) {
+		// test logic
+		g.Reset(ctx, key)
+		assert.Equal(t, int64(1), g.Fail(ctx, key))
+		assert.Error(t, g.Check(ctx, key))
+	})
+}
+
+func TestGuard_Ban(t *testing.T) {
+	ctx := context.Background()
+	g := newGuard(t)
+	banned, free := "test:"+uuid.NewString(), "test:"+uuid.NewString()
+
+	assert.NoError(t, g.Check(ctx, banned, free))
+
+	// test logic
+	g.Ban(ctx, banned, 90*time.Second)
+
+	err := g.Check(ctx, free, banned)
+	assert.InDelta(t, 90*time.Second, retryAfter(t, err), float64(time.Second))
+	assert.Equal(t, 90, err.(*guard.WaitError).RetryAfterSeconds())
+	assert.NoError(t, g.Check(ctx, free))
+}
+
+func TestGuard_RedisDown(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	down := errors.New("dial tcp: connection refused")
+	rc := mock.NewMockRedisClient(ctrl)
+	rc.EXPECT().TTL(gomock.Any(), gomock.Any()).Return(time.Duration(0), down).AnyTimes()
+	rc.EXPECT().Eval(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, down).AnyTimes()
+	rc.EXPECT().DelContext(gomock.Any(), gomock.Any()).Return(down).AnyTimes()
+
+	g := guard.Provide(&config.Configuration{BruteForce: config.BruteForceConfig{Window: 60, BaseDelay: 1, MaxDelay: 1}}, rc)
+
+	// test logic
+	assert.Equal(t, int64(0), g.Fail(ctx, "alice"))
+	assert.NoError(t, g.Check(ctx, "alice"), "attempts go through while Redis is down")
+	g.Reset(ctx, "alice")
+}
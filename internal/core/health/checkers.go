@@ -0,0 +1,36 @@
+package health
+
+import (
+	"context"
+	"go-fiber-api/internal/core/storage/db"
+	"go-fiber-api/internal/wrapper/redis"
+)
+
+// NewDBChecker pings Postgres and runs a trivial query, which also catches a
+// pool that connects but cannot serve queries.
+func NewDBChecker(client db.Client) Checker {
+	return NewChecker("postgres", func(ctx context.Context) error {
+		sqlDB, err := client.DB()
+		if err != nil {
+			return err
+		}
+
+		if err := sqlDB.PingContext(ctx); err != nil {
+			return err
+		}
+
+		var one int
+		return client.WithContext(ctx).Raw("SELECT 1").Scan(&one).Error
+	})
+}
+
+// NewMigrationChecker verifies that the schema has been migrated.
+func NewMigrationChecker(client db.Client) Checker {
+	return NewChecker("migrations", func(ctx context.Context) error {
+		return db.CheckMigrations(ctx, client)
+	})
+}
+
+func NewRedisChecker(rc redis.Client) Checker {
+	return NewChecker("redis", rc.Ping)
+}
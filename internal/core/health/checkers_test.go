@@ -0,0 +1,83 @@
+package health_test
+
+import (
+	"context"
+	"errors"
+	"go-fiber-api/internal/core/health"
+	"go-fiber-api/internal/core/storage/db"
+	"go-fiber-api/internal/mock"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestCheckers(t *testing.T) {
+	tests := []struct {
+		name          string
+		checker       func(t *testing.T, ctrl *gomock.Controller) health.Checker
+		expectedError string
+	}{
+		{
+			name: "when_db_is_reachable_should_pass",
+			checker: func(t *testing.T, ctrl *gomock.Controller) health.Checker {
+				client, err := db.GetDbTestMode()
+				assert.NoError(t, err)
+				return health.NewDBChecker(client)
+			},
+		},
+		{
+			name: "when_db_is_closed_should_fail",
+			checker: func(t *testing.T, ctrl *gomock.Controller) health.Checker {
+				client, err := db.GetDbTestMode()
+				assert.NoError(t, err)
+				sqlDB, err := client.DB()
+				assert.NoError(t, err)
+				assert.NoError(t, sqlDB.Close())
+				return health.NewDBChecker(client)
+			},
+			expectedError: "sql: database is closed",
+		},
+		{
+			name: "when_schema_is_migrated_should_pass",
+			checker: func(t *testing.T, ctrl *gomock.Controller) health.Checker {
+				client, err := db.GetDbTestMode()
+				assert.NoError(t, err)
+				return health.NewMigrationChecker(client)
+			},
+		},
+		{
+			name: "when_table_is_missing_should_fail",
+			checker: func(t *testing.T, ctrl *gomock.Controller) health.Checker {
+				client, err := db.GetDbTestMode()
+				assert.NoError(t, err)
+				assert.NoError(t, client.Migrator().DropTable("users"))
+				return health.NewMigrationChecker(client)
+			},
+			expectedError: "table of model.User is missing",
+		},
+		{
+			name: "when_redis_ping_fails_should_fail",
+			checker: func(t *testing.T, ctrl *gomock.Controller) health.Checker {
+				rc := mock.NewMockRedisClient(ctrl)
+				rc.EXPECT().Ping(gomock.Any()).Return(errors.New("mock error"))
+				return health.NewRedisChecker(rc)
+			},
+			expectedError: "mock error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			err := tt.checker(t, ctrl).Check(context.Background())
+			if tt.expectedError != "" {
+				assert.EqualError(t, err, tt.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package health
+
+import (
+	"go-fiber-api/internal/core/config"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type Handler interface {
+	Livez(c *fiber.Ctx) error
+	Readyz(c *fiber.Ctx) error
+}
+
+type handlerImpl struct {
+	registry *Registry
+	devMode  bool
+}
+
+func ProvideHandler(cfg *config.Configuration, registry *Registry) Handler {
+	return &handlerImpl{
+		registry: registry,
+		devMode:  cfg.DevMode,
+	}
+}
+
+// Livez only tells the process is serving requests; dependencies are left to
+// Readyz so that an outage does not get every instance restarted.
+func (h *handlerImpl) Livez(c *fiber.Ctx) error {
+	return c.JSON(fiber.Map{
+		"status": StatusUp,
+	})
+}
+
+// Readyz answers 503 with the report while a required dependency is down.
+// Outside of dev mode, the errors of the checks are only logged.
+func (h *handlerImpl) Readyz(c *fiber.Ctx) error {
+	report := h.registry.Check(c.UserContext())
+	if !h.devMode {
+		report = report.withoutErrors()
+	}
+
+	status := fiber.StatusOK
+	if report.Status != StatusUp {
+		status = fiber.StatusServiceUnavailable
+	}
+
+	return c.Status(status).JSON(report)
+}
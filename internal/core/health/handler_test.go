@@ -0,0 +1,83 @@
+package health_test
+
+import (
+	"context"
+	"errors"
+	"go-fiber-api/internal/core/config"
+	"go-fiber-api/internal/core/health"
+	"io"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler(t *testing.T) {
+	tests := []struct {
+		name           string
+		url            string
+		devMode        bool
+		checkErr       error
+		expectedStatus int
+		expectedBody   []string
+		unexpected     string
+	}{
+		{
+			name:           "when_livez_should_be_up_whatever_the_dependencies",
+			url:            "/livez",
+			checkErr:       errors.New("mock error"),
+			expectedStatus: fiber.StatusOK,
+			expectedBody:   []string{`{"status":"up"}`},
+		},
+		{
+			name:           "when_dependencies_are_up_should_be_ready",
+			url:            "/readyz",
+			expectedStatus: fiber.StatusOK,
+			expectedBody:   []string{`"status":"up"`, `"postgres":{"status":"up"`},
+		},
+		{
+			name:           "when_dependency_is_down_should_not_be_ready_without_its_error",
+			url:            "/readyz",
+			checkErr:       errors.New("dial tcp 10.0.0.5:5432: password authentication failed"),
+			expectedStatus: fiber.StatusServiceUnavailable,
+			expectedBody:   []string{`"status":"down"`, `"postgres":{"status":"down","latencyMs":`},
+			unexpected:     "10.0.0.5",
+		},
+		{
+			name:           "when_dependency_is_down_in_dev_mode_should_tell_its_error",
+			url:            "/readyz",
+			devMode:        true,
+			checkErr:       errors.New("mock error"),
+			expectedStatus: fiber.StatusServiceUnavailable,
+			expectedBody:   []string{`"status":"down"`, `"error":"mock error"`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := health.NewRegistry(time.Second, 0)
+			r.Register(health.NewChecker("postgres", func(ctx context.Context) error { return tt.checkErr }))
+
+			h := health.ProvideHandler(&config.Configuration{DevMode: tt.devMode}, r)
+
+			app := fiber.New()
+			app.Get("/livez", h.Livez)
+			app.Get("/readyz", h.Readyz)
+
+			resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, tt.url, nil))
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+
+			body, err := io.ReadAll(resp.Body)
+			assert.NoError(t, err)
+			for _, expected := range tt.expectedBody {
+				assert.Contains(t, string(body), expected)
+			}
+			if tt.unexpected != "" {
+				assert.NotContains(t, string(body), tt.unexpected)
+			}
+		})
+	}
+}
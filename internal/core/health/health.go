@@ -0,0 +1,217 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"go-fiber-api/internal/wrapper/logx"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	StatusUp   = "up"
+	StatusDown = "down"
+)
+
+// Checker reports whether a dependency is usable. Check must honour the
+// deadline of ctx.
+type Checker interface {
+	Name() string
+	Check(ctx context.Context) error
+}
+
+type checkerFunc struct {
+	name  string
+	check func(ctx context.Context) error
+}
+
+// NewChecker builds a Checker from a function.
+func NewChecker(name string, check func(ctx context.Context) error) Checker {
+	return checkerFunc{name: name, check: check}
+}
+
+func (c checkerFunc) Name() string {
+	return c.name
+}
+
+func (c checkerFunc) Check(ctx context.Context) error {
+	return c.check(ctx)
+}
+
+// Report is the readiness of the application and of every checked component.
+type Report struct {
+	Status       string                 `json:"status"`
+	ShuttingDown bool                   `json:"shuttingDown,omitempty"`
+	Checks       map[string]CheckResult `json:"checks"`
+	CheckedAt    time.Time              `json:"checkedAt"`
+}
+
+// withoutErrors returns a copy of r whose checks tell no error, which may
+// reveal the addresses and credentials of the dependencies.
+func (r Report) withoutErrors() Report {
+	checks := make(map[string]CheckResult, len(r.Checks))
+	for name, result := range r.Checks {
+		result.Error = ""
+		checks[name] = result
+	}
+	r.Checks = checks
+
+	return r
+}
+
+type CheckResult struct {
+	Status    string `json:"status"`
+	Optional  bool   `json:"optional,omitempty"`
+	Error     string `json:"error,omitempty"`
+	LatencyMs int64  `json:"latencyMs"`
+}
+
+type registration struct {
+	checker  Checker
+	optional bool
+}
+
+// Registry runs the registered checkers concurrently, each bounded by the
+// timeout, and caches the report for ttl so that probes do not hammer the
+// dependencies. A failing optional checker shows in the report without
+// making the application unready.
+type Registry struct {
+	timeout time.Duration
+	ttl     time.Duration
+
+	mu       sync.Mutex
+	checkers []registration
+	report   Report
+	expires  time.Time
+
+	shuttingDown atomic.Bool
+}
+
+func NewRegistry(timeout, ttl time.Duration) *Registry {
+	return &Registry{
+		timeout: timeout,
+		ttl:     ttl,
+	}
+}
+
+// Register adds checkers that must pass for the application to be ready.
+func (r *Registry) Register(checkers ...Checker) {
+	r.register(false, checkers...)
+}
+
+// RegisterOptional adds checkers of dependencies the application can run
+// without, degraded.
+func (r *Registry) RegisterOptional(checkers ...Checker) {
+	r.register(true, checkers...)
+}
+
+func (r *Registry) register(optional bool, checkers ...Checker) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, c := range checkers {
+		r.checkers = append(r.checkers, registration{checker: c, optional: optional})
+	}
+	r.expires = time.Time{}
+}
+
+// ShutDown makes every later report down, so the instance is taken out of
+// rotation before it stops accepting connections.
+func (r *Registry) ShutDown() {
+	r.shuttingDown.Store(true)
+}
+
+// Check returns the cached report, running the checkers when it expired.
+// Concurrent callers wait for a single run.
+func (r *Registry) Check(ctx context.Context) Report {
+	if r.shuttingDown.Load() {
+		return Report{
+			Status:       StatusDown,
+			ShuttingDown: true,
+			Checks:       map[string]CheckResult{},
+			CheckedAt:    time.Now(),
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	if now.Before(r.expires) {
+		return r.report
+	}
+
+	// A probe giving up must not cache its cancellation for everyone else.
+	r.report = r.run(context.WithoutCancel(ctx))
+	r.expires = now.Add(r.ttl)
+
+	return r.report
+}
+
+func (r *Registry) run(ctx context.Context) Report {
+	report := Report{
+		Status:    StatusUp,
+		Checks:    make(map[string]CheckResult, len(r.checkers)),
+		CheckedAt: time.Now(),
+	}
+
+	results := make([]CheckResult, len(r.checkers))
+
+	var wg sync.WaitGroup
+	for i, reg := range r.checkers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = r.check(ctx, reg)
+		}()
+	}
+	wg.Wait()
+
+	for i, reg := range r.checkers {
+		report.Checks[reg.checker.Name()] = results[i]
+		if results[i].Status == StatusDown {
+			logx.FromContext(ctx).Warnf("health check %s failed: %s", reg.checker.Name(), results[i].Error)
+			if !reg.optional {
+				report.Status = StatusDown
+			}
+		}
+	}
+
+	return report
+}
+
+func (r *Registry) check(ctx context.Context, reg registration) (result CheckResult) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	start := time.Now()
+	defer func() {
+		result.LatencyMs = time.Since(start).Milliseconds()
+		result.Optional = reg.optional
+	}()
+
+	errCh := make(chan error, 1)
+	go func() {
+		defer func() {
+			if p := recover(); p != nil {
+				errCh <- fmt.Errorf("check panicked: %v", p)
+			}
+		}()
+		errCh <- reg.checker.Check(ctx)
+	}()
+
+	// A checker ignoring ctx still cannot hold the probe past the timeout.
+	var err error
+	select {
+	case err = <-errCh:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	if err != nil {
+		return CheckResult{Status: StatusDown, Error: err.Error()}
+	}
+
+	return CheckResult{Status: StatusUp}
+}
@@ -0,0 +1,125 @@
+package health_test
+
+import (
+	"context"
+	"errors"
+	"go-fiber-api/internal/core/health"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegistry_Check(t *testing.T) {
+	up := health.NewChecker("up", func(ctx context.Context) error { return nil })
+	down := health.NewChecker("down", func(ctx context.Context) error { return errors.New("mock error") })
+	slow := health.NewChecker("slow", func(ctx context.Context) error {
+		time.Sleep(time.Second)
+		return nil
+	})
+	panics := health.NewChecker("panics", func(ctx context.Context) error { panic("boom") })
+
+	tests := []struct {
+		name           string
+		required       []health.Checker
+		optional       []health.Checker
+		expectedStatus string
+		expectedChecks map[string]health.CheckResult
+	}{
+		{
+			name:           "when_all_checks_pass_should_be_up",
+			required:       []health.Checker{up},
+			expectedStatus: health.StatusUp,
+			expectedChecks: map[string]health.CheckResult{
+				"up": {Status: health.StatusUp},
+			},
+		},
+		{
+			name:           "when_required_check_fails_should_be_down",
+			required:       []health.Checker{up, down},
+			expectedStatus: health.StatusDown,
+			expectedChecks: map[string]health.CheckResult{
+				"up":   {Status: health.StatusUp},
+				"down": {Status: health.StatusDown, Error: "mock error"},
+			},
+		},
+		{
+			name:           "when_optional_check_fails_should_stay_up",
+			required:       []health.Checker{up},
+			optional:       []health.Checker{down},
+			expectedStatus: health.StatusUp,
+			expectedChecks: map[string]health.CheckResult{
+				"up":   {Status: health.StatusUp},
+				"down": {Status: health.StatusDown, Optional: true, Error: "mock error"},
+			},
+		},
+		{
+			name:           "when_check_times_out_should_be_down",
+			required:       []health.Checker{slow},
+			expectedStatus: health.StatusDown,
+			expectedChecks: map[string]health.CheckResult{
+				"slow": {Status: health.StatusDown, Error: context.DeadlineExceeded.Error()},
+			},
+		},
+		{
+			name:           "when_check_panics_should_be_down",
+			required:       []health.Checker{panics},
+			expectedStatus: health.StatusDown,
+			expectedChecks: map[string]health.CheckResult{
+				"panics": {Status: health.StatusDown, Error: "check panicked: boom"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := health.NewRegistry(50*time.Millisecond, 0)
+			r.Register(tt.required...)
+			r.RegisterOptional(tt.optional...)
+
+			report := r.Check(context.Background())
+
+			assert.Equal(t, tt.expectedStatus, report.Status)
+			for name, result := range report.Checks {
+				result.LatencyMs = 0
+				report.Checks[name] = result
+			}
+			assert.Equal(t, tt.expectedChecks, report.Checks)
+		})
+	}
+}
+
+func TestRegistry_Check_Cache(t *testing.T) {
+	var calls atomic.Int32
+	counting := health.NewChecker("counting", func(ctx context.Context) error {
+		calls.Add(1)
+		return nil
+	})
+
+	r := health.NewRegistry(time.Second, 100*time.Millisecond)
+	r.Register(counting)
+
+	first := r.Check(context.Background())
+	second := r.Check(context.Background())
+	assert.Equal(t, int32(1), calls.Load())
+	assert.Equal(t, first.CheckedAt, second.CheckedAt)
+
+	time.Sleep(150 * time.Millisecond)
+	r.Check(context.Background())
+	assert.Equal(t, int32(2), calls.Load())
+}
+
+func TestRegistry_ShutDown(t *testing.T) {
+	r := health.NewRegistry(time.Second, time.Minute)
+	r.Register(health.NewChecker("up", func(ctx context.Context) error { return nil }))
+
+	assert.Equal(t, health.StatusUp, r.Check(context.Background()).Status)
+
+	r.ShutDown()
+
+	// The cached report must not hide the shutdown.
+	report := r.Check(context.Background())
+	assert.Equal(t, health.StatusDown, report.Status)
+	assert.True(t, report.ShuttingDown)
+}
@@ -0,0 +1,25 @@
+package health
+
+import (
+	"go-fiber-api/internal/core/config"
+	"go-fiber-api/internal/core/storage/db"
+	"go-fiber-api/internal/wrapper/redis"
+	"time"
+)
+
+// ProvideRegistry registers the checkers of the application dependencies.
+// Redis is optional since the application keeps serving, uncached, without
+// it.
+func ProvideRegistry(cfg *config.Configuration, client db.Client, rc redis.Client) *Registry {
+	r := NewRegistry(
+		time.Duration(cfg.HealthCheckTimeout)*time.Second,
+		time.Duration(cfg.HealthCacheTTL)*time.Second,
+	)
+	r.Register(
+		NewDBChecker(client),
+		NewMigrationChecker(client),
+	)
+	r.RegisterOptional(NewRedisChecker(rc))
+
+	return r
+}
@@ -0,0 +1,25 @@
+//go:build wireinject
+// +build wireinject
+
+//go:generate wire
+package health
+
+import (
+	"go-fiber-api/internal/core/config"
+	"go-fiber-api/internal/core/storage/db"
+	"go-fiber-api/internal/wrapper/redis"
+
+	"github.com/google/wire"
+)
+
+var ProviderSet = wire.NewSet(
+	ProvideRegistry,
+
+	ProvideHandler,
+)
+
+func Wire(cfg *config.Configuration, client db.Client, rc redis.Client) (Handler, error) {
+	wire.Build(ProviderSet)
+
+	return &handlerImpl{}, nil
+}
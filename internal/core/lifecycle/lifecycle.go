@@ -0,0 +1,108 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// Hook is run when the application starts and stops. Either function may be
+// nil; a hook without OnStart is considered started as soon as it is
+// appended, which suits resources already opened by their provider.
+type Hook struct {
+	Name    string
+	OnStart func(ctx context.Context) error
+	OnStop  func(ctx context.Context) error
+}
+
+type entry struct {
+	hook    Hook
+	started bool
+}
+
+// Lifecycle collects the hooks of one application instance. Providers append
+// to it while the dependency graph is built; Start runs the OnStart hooks in
+// that order and Stop the OnStop hooks in reverse, so a component is stopped
+// before whatever it was built on.
+type Lifecycle struct {
+	mu      sync.Mutex
+	entries []*entry
+}
+
+func New() *Lifecycle {
+	return &Lifecycle{}
+}
+
+// Provide returns a new Lifecycle along with a cleanup stopping it, which wire
+// runs when a later provider fails: the resources opened by the providers
+// before it are released rather than leaked.
+func Provide() (*Lifecycle, func()) {
+	l := New()
+
+	return l, func() {
+		_ = l.Stop(context.Background())
+	}
+}
+
+// Append registers h. Appended after Start, its OnStart is left to the
+// caller and it is only stopped.
+func (l *Lifecycle) Append(h Hook) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.entries = append(l.entries, &entry{
+		hook:    h,
+		started: h.OnStart == nil,
+	})
+}
+
+// Start runs every OnStart hook not run yet. When one fails, the hooks
+// started so far are stopped and the error is returned.
+func (l *Lifecycle) Start(ctx context.Context) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for _, e := range l.entries {
+		if e.started {
+			continue
+		}
+
+		if err := e.hook.OnStart(ctx); err != nil {
+			err = fmt.Errorf("start %s: %w", e.hook.Name, err)
+			return errors.Join(err, l.stop(ctx))
+		}
+		e.started = true
+	}
+
+	return nil
+}
+
+// Stop runs the OnStop hook of every started entry in reverse order. All of
+// them run even if some fail; their errors are joined.
+func (l *Lifecycle) Stop(ctx context.Context) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.stop(ctx)
+}
+
+func (l *Lifecycle) stop(ctx context.Context) error {
+	var errs []error
+	for i := len(l.entries) - 1; i >= 0; i-- {
+		e := l.entries[i]
+		if !e.started {
+			continue
+		}
+		e.started = false
+
+		if e.hook.OnStop == nil {
+			continue
+		}
+		if err := e.hook.OnStop(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("stop %s: %w", e.hook.Name, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
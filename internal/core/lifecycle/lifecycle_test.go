@@ -0,0 +1,121 @@
+package lifecycle_test
+
+import (
+	"context"
+	"errors"
+	"go-fiber-api/internal/core/lifecycle"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLifecycle(t *testing.T) {
+	tests := []struct {
+		name          string
+		failStart     string
+		failStop      string
+		expectedCalls []string
+		expectedStart string
+		expectedStop  string
+	}{
+		{
+			name: "should_start_in_order_and_stop_in_reverse",
+			expectedCalls: []string{
+				"start b", "start c",
+				"stop c", "stop b", "stop a",
+			},
+		},
+		{
+			name:      "when_start_fails_should_stop_started_hooks",
+			failStart: "c",
+			expectedCalls: []string{
+				"start b", "start c",
+				"stop b", "stop a",
+			},
+			expectedStart: "start c: boom",
+		},
+		{
+			name:     "when_stop_fails_should_still_stop_every_hook",
+			failStop: "b",
+			expectedCalls: []string{
+				"start b", "start c",
+				"stop c", "stop b", "stop a",
+			},
+			expectedStop: "stop b: boom",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls []string
+			hook := func(name string, withStart bool) lifecycle.Hook {
+				h := lifecycle.Hook{
+					Name: name,
+					OnStop: func(context.Context) error {
+						calls = append(calls, "stop "+name)
+						if name == tt.failStop {
+							return errors.New("boom")
+						}
+						return nil
+					},
+				}
+				if withStart {
+					h.OnStart = func(context.Context) error {
+						calls = append(calls, "start "+name)
+						if name == tt.failStart {
+							return errors.New("boom")
+						}
+						return nil
+					}
+				}
+				return h
+			}
+
+			lc := lifecycle.New()
+			// "a" is opened by its provider, it has nothing to start.
+			lc.Append(hook("a", false))
+			lc.Append(hook("b", true))
+			lc.Append(hook("c", true))
+
+			err := lc.Start(context.Background())
+			if tt.expectedStart != "" {
+				assert.EqualError(t, err, tt.expectedStart)
+			} else {
+				assert.NoError(t, err)
+
+				err = lc.Stop(context.Background())
+				if tt.expectedStop != "" {
+					assert.EqualError(t, err, tt.expectedStop)
+				} else {
+					assert.NoError(t, err)
+				}
+			}
+
+			assert.Equal(t, tt.expectedCalls, calls)
+
+			// Stopped hooks are not stopped twice.
+			assert.NoError(t, lc.Stop(context.Background()))
+			assert.Len(t, calls, len(tt.expectedCalls))
+		})
+	}
+}
+
+func TestProvide(t *testing.T) {
+	lc, cleanup := lifecycle.Provide()
+
+	var calls []string
+	lc.Append(lifecycle.Hook{
+		Name:   "opened",
+		OnStop: func(context.Context) error { calls = append(calls, "stop opened"); return nil },
+	})
+	lc.Append(lifecycle.Hook{
+		Name:    "not started",
+		OnStart: func(context.Context) error { calls = append(calls, "start not started"); return nil },
+		OnStop:  func(context.Context) error { calls = append(calls, "stop not started"); return nil },
+	})
+
+	// test logic
+	// A later provider failed: only what was opened is closed.
+	cleanup()
+	assert.Equal(t, []string{"stop opened"}, calls)
+}
@@ -0,0 +1,243 @@
+package lock
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-fiber-api/internal/wrapper/redis"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	// ErrNotAcquired is returned by Acquire when another holder owns the lock.
+	ErrNotAcquired = errors.New("lock not acquired")
+	// ErrLockLost is returned when a lease expired or was taken over before
+	// it was released.
+	ErrLockLost = errors.New("lock lost")
+)
+
+var (
+	// acquireScript sets the lock only if it is free and, in the same step,
+	// increments the fencing counter so every holder gets a larger token.
+	acquireScript = redis.NewScript(`
+if redis.call("SET", KEYS[1], ARGV[1], "NX", "PX", ARGV[2]) then
+	return redis.call("INCR", KEYS[2])
+end
+return 0
+`)
+
+	// renewScript and releaseScript only touch the lock while it still holds
+	// the value written by this lease.
+	renewScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+return 0
+`)
+
+	releaseScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+)
+
+type Locker interface {
+	// Acquire takes the lock for key without waiting and keeps it alive until
+	// the lease is released. It returns ErrNotAcquired when the lock is held.
+	Acquire(ctx context.Context, key string, ttl time.Duration) (*Lease, error)
+}
+
+type lockerImpl struct {
+	rc redis.Client
+}
+
+func Provide(rc redis.Client) Locker {
+	return &lockerImpl{
+		rc: rc,
+	}
+}
+
+// lockKey and fenceKey share a hash tag so both land on the same cluster slot.
+func lockKey(key string) string {
+	return fmt.Sprintf("lock:{%s}", key)
+}
+
+func fenceKey(key string) string {
+	return fmt.Sprintf("lock:{%s}:fence", key)
+}
+
+func (l *lockerImpl) Acquire(ctx context.Context, key string, ttl time.Duration) (*Lease, error) {
+	if ttl < time.Millisecond {
+		return nil, fmt.Errorf("lock ttl must be at least 1ms")
+	}
+
+	value := uuid.New().String()
+	res, err := l.rc.Eval(ctx, acquireScript, []string{lockKey(key), fenceKey(key)}, value, ttl.Milliseconds())
+	if err != nil {
+		return nil, fmt.Errorf("acquire lock %s failed: %v", key, err)
+	}
+
+	token, _ := res.(int64)
+	if token == 0 {
+		return nil, ErrNotAcquired
+	}
+
+	lease := &Lease{
+		key:     key,
+		value:   value,
+		token:   token,
+		ttl:     ttl,
+		rc:      l.rc,
+		stop:    make(chan struct{}),
+		stopped: make(chan struct{}),
+		lost:    make(chan struct{}),
+	}
+	go lease.renew()
+
+	return lease, nil
+}
+
+// Lease is a held lock, renewed in the background every third of its TTL
+// until Release is called or renewal fails.
+type Lease struct {
+	key   string
+	value string
+	token int64
+	ttl   time.Duration
+	rc    redis.Client
+
+	stop     chan struct{}
+	stopped  chan struct{}
+	lost     chan struct{}
+	lostOnce sync.Once
+	release  sync.Once
+}
+
+func (l *Lease) Key() string {
+	return l.key
+}
+
+// Token is the fencing token of this lease. It grows with every acquisition
+// of the same key, so storage can reject writes from a stale holder.
+func (l *Lease) Token() int64 {
+	return l.token
+}
+
+// Lost is closed once the lease can no longer be guaranteed.
+func (l *Lease) Lost() <-chan struct{} {
+	return l.lost
+}
+
+func (l *Lease) markLost() {
+	l.lostOnce.Do(func() {
+		close(l.lost)
+	})
+}
+
+func (l *Lease) renew() {
+	defer close(l.stopped)
+
+	interval := l.ttl / 3
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	expiresAt := time.Now().Add(l.ttl)
+	for {
+		select {
+		case <-l.stop:
+			return
+		case <-ticker.C:
+			// Past its expiry the lock may have another holder already.
+			if !time.Now().Before(expiresAt) {
+				logrus.Warnf("lock %s expired before being renewed", l.key)
+				l.markLost()
+				return
+			}
+
+			// Counted from before the call, Redis may renew it late.
+			sent := time.Now()
+			ctx, cancel := context.WithDeadline(context.Background(), expiresAt)
+			res, err := l.rc.Eval(ctx, renewScript, []string{lockKey(l.key)}, l.value, l.ttl.Milliseconds())
+			cancel()
+
+			if err != nil {
+				logrus.Warnf("renew lock %s failed: %v", l.key, err)
+				// Transient errors are retried while the next renewal can
+				// still happen before the lease runs out.
+				if time.Until(expiresAt) < interval {
+					l.markLost()
+					return
+				}
+				continue
+			}
+
+			if renewed, _ := res.(int64); renewed == 0 {
+				logrus.Warnf("lock %s lost", l.key)
+				l.markLost()
+				return
+			}
+			expiresAt = sent.Add(l.ttl)
+		}
+	}
+}
+
+// Release stops the renewal and deletes the lock if this lease still owns it.
+// It returns ErrLockLost when the lock had already expired or moved on.
+func (l *Lease) Release(ctx context.Context) error {
+	var err error
+	l.release.Do(func() {
+		close(l.stop)
+		<-l.stopped
+
+		var res any
+		res, err = l.rc.Eval(ctx, releaseScript, []string{lockKey(l.key)}, l.value)
+		if err != nil {
+			err = fmt.Errorf("release lock %s failed: %v", l.key, err)
+			return
+		}
+
+		if released, _ := res.(int64); released == 0 {
+			l.markLost()
+			err = ErrLockLost
+		}
+	})
+
+	return err
+}
+
+// WithLock runs fn while holding the lock for key. The context given to fn
+// is cancelled if the lease is lost, and the lock is released afterwards.
+func WithLock(ctx context.Context, locker Locker, key string, ttl time.Duration, fn func(ctx context.Context) error) error {
+	lease, err := locker.Acquire(ctx, key, ttl)
+	if err != nil {
+		return err
+	}
+
+	fnCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	go func() {
+		select {
+		case <-lease.Lost():
+			cancel()
+		case <-fnCtx.Done():
+		}
+	}()
+
+	fnErr := fn(fnCtx)
+
+	if err := lease.Release(context.WithoutCancel(ctx)); err != nil {
+		if fnErr != nil {
+			return fnErr
+		}
+		return err
+	}
+
+	return fnErr
+}
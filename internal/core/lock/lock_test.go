@@ -0,0 +1,196 @@
+package lock_test
+
+import (
+	"context"
+	"errors"
+	"go-fiber-api/internal/core/lock"
+	"go-fiber-api/internal/mock"
+	"go-fiber-api/internal/wrapper/redis"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestLocker_Acquire(t *testing.T) {
+	type dependency struct {
+		redisClient func(*gomock.Controller) redis.Client
+	}
+
+	tests := []struct {
+		name string
+		ttl  time.Duration
+		dependency
+		expectedToken  int64
+		expectedErr    bool
+		expectedErrMsg string
+	}{
+		{
+			name: "when_lock_is_free_should_get_fencing_token",
+			ttl:  time.Minute,
+			dependency: dependency{
+				redisClient: func(ctrl *gomock.Controller) redis.Client {
+					m := mock.NewMockRedisClient(ctrl)
+					m.EXPECT().
+						Eval(gomock.Any(), gomock.Any(), []string{"lock:{job}", "lock:{job}:fence"}, gomock.Any(), int64(60000)).
+						Return(int64(7), nil)
+					m.EXPECT().
+						Eval(gomock.Any(), gomock.Any(), []string{"lock:{job}"}, gomock.Any()).
+						Return(int64(1), nil)
+					return m
+				},
+			},
+			expectedToken: 7,
+		},
+		{
+			name: "when_lock_is_held_should_get_not_acquired",
+			ttl:  time.Minute,
+			dependency: dependency{
+				redisClient: func(ctrl *gomock.Controller) redis.Client {
+					m := mock.NewMockRedisClient(ctrl)
+					m.EXPECT().
+						Eval(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+						Return(int64(0), nil)
+					return m
+				},
+			},
+			expectedErr:    true,
+			expectedErrMsg: lock.ErrNotAcquired.Error(),
+		},
+		{
+			name: "when_redis_fails_should_get_error",
+			ttl:  time.Minute,
+			dependency: dependency{
+				redisClient: func(ctrl *gomock.Controller) redis.Client {
+					m := mock.NewMockRedisClient(ctrl)
+					m.EXPECT().
+						Eval(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+						Return(nil, errors.New("mock error"))
+					return m
+				},
+			},
+			expectedErr:    true,
+			expectedErrMsg: "acquire lock job failed: mock error",
+		},
+		{
+			name: "when_ttl_is_too_short_should_get_error",
+			ttl:  time.Microsecond,
+			dependency: dependency{
+				redisClient: func(ctrl *gomock.Controller) redis.Client {
+					return mock.NewMockRedisClient(ctrl)
+				},
+			},
+			expectedErr:    true,
+			expectedErrMsg: "lock ttl must be at least 1ms",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			l := lock.Provide(tt.redisClient(ctrl))
+
+			ctx := context.Background()
+			lease, err := l.Acquire(ctx, "job", tt.ttl)
+			if tt.expectedErr && assert.Error(t, err) {
+				assert.Equal(t, tt.expectedErrMsg, err.Error())
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedToken, lease.Token())
+			assert.NoError(t, lease.Release(ctx))
+		})
+	}
+}
+
+func TestLease_Renew(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	m := mock.NewMockRedisClient(ctrl)
+	m.EXPECT().
+		Eval(gomock.Any(), gomock.Any(), []string{"lock:{job}", "lock:{job}:fence"}, gomock.Any(), gomock.Any()).
+		Return(int64(1), nil)
+	// The lock was taken over, so renewal fails and so does the release.
+	m.EXPECT().
+		Eval(gomock.Any(), gomock.Any(), []string{"lock:{job}"}, gomock.Any(), gomock.Any()).
+		Return(int64(0), nil)
+	m.EXPECT().
+		Eval(gomock.Any(), gomock.Any(), []string{"lock:{job}"}, gomock.Any()).
+		Return(int64(0), nil)
+
+	l := lock.Provide(m)
+
+	err := lock.WithLock(context.Background(), l, "job", 30*time.Millisecond, func(ctx context.Context) error {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(time.Second):
+			return nil
+		}
+	})
+
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestLease_Renew_Failing(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ttl := 300 * time.Millisecond
+	m := mock.NewMockRedisClient(ctrl)
+	m.EXPECT().
+		Eval(gomock.Any(), gomock.Any(), []string{"lock:{job}", "lock:{job}:fence"}, gomock.Any(), gomock.Any()).
+		Return(int64(1), nil)
+	// Redis is unreachable, the lock expires there meanwhile.
+	m.EXPECT().
+		Eval(gomock.Any(), gomock.Any(), []string{"lock:{job}"}, gomock.Any(), gomock.Any()).
+		Return(nil, errors.New("dial tcp: connection refused")).
+		AnyTimes()
+	m.EXPECT().
+		Eval(gomock.Any(), gomock.Any(), []string{"lock:{job}"}, gomock.Any()).
+		Return(nil, errors.New("dial tcp: connection refused"))
+
+	acquiredAt := time.Now()
+	lease, err := lock.Provide(m).Acquire(context.Background(), "job", ttl)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	// test logic
+	select {
+	case <-lease.Lost():
+		assert.Less(t, time.Since(acquiredAt), ttl, "lost before another holder can take the lock")
+	case <-time.After(time.Second):
+		t.Fatal("lease not lost")
+	}
+	assert.Error(t, lease.Release(context.Background()))
+}
+
+func TestWithLock(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	m := mock.NewMockRedisClient(ctrl)
+	m.EXPECT().
+		Eval(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(int64(1), nil)
+	m.EXPECT().
+		Eval(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(int64(1), nil)
+
+	l := lock.Provide(m)
+
+	called := false
+	err := lock.WithLock(context.Background(), l, "job", time.Minute, func(ctx context.Context) error {
+		called = true
+		return errors.New("mock error")
+	})
+
+	assert.True(t, called)
+	assert.EqualError(t, err, "mock error")
+}
@@ -1,69 +1,158 @@
 package apikey
 
 import (
+	"errors"
 	"go-fiber-api/internal/core/config"
+	"go-fiber-api/internal/core/guard"
 	"go-fiber-api/internal/core/model"
 	"go-fiber-api/internal/feature/apikey"
+	"go-fiber-api/internal/wrapper/jwtx"
+	"go-fiber-api/internal/wrapper/logx"
+	"go-fiber-api/internal/wrapper/metrics"
+	"go-fiber-api/internal/wrapper/tlsx"
+	"go-fiber-api/toolkit/apperror"
 	"strings"
-	"sync"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/sirupsen/logrus"
 )
 
-var (
-	m     *middlewareImpl
-	mOnce sync.Once
-	log   *logrus.Entry
-)
-
 type Middleware interface {
 	Validate() fiber.Handler
 }
 
+// Reasons an API key is rejected, recorded in the auth failure metric.
+const (
+	ReasonMissing = "missing"
+	ReasonInvalid = "invalid"
+	ReasonRevoked = "revoked"
+	ReasonBanned  = "banned"
+)
+
+// Errors of requests without a valid API key.
+var (
+	ErrAPIKeyRequired = apperror.Unauthorized("API_KEY_REQUIRED", "api key is required")
+	ErrInvalidAPIKey  = apperror.Unauthorized("INVALID_API_KEY", "invalid or expired api key")
+	ErrAPIKeyRevoked  = apperror.Unauthorized("API_KEY_REVOKED", "api key not found or revoked")
+	ErrAPIKeyBanned   = apperror.RateLimited("API_KEY_BANNED", "too many invalid api keys", 0)
+)
+
+type principalKey struct{}
+
 type middlewareImpl struct {
-	cfg *config.Configuration
-	s   apikey.Service
+	s          apikey.Service
+	keyring    *jwtx.Keyring
+	metrics    *metrics.Metrics
+	principals tlsx.Principals
+	guard      guard.Guard
+
+	banThreshold int64
+	banDuration  time.Duration
 }
 
-func Provide(cfg *config.Configuration, apiKeySvc apikey.Service) Middleware {
-	mOnce.Do(func() {
-		m = &middlewareImpl{
-			cfg: cfg,
-			s:   apiKeySvc,
-		}
-	})
+func Provide(cfg *config.Configuration, apiKeySvc apikey.Service, keyring *jwtx.Keyring, metrics *metrics.Metrics, g guard.Guard) Middleware {
+	return &middlewareImpl{
+		s:            apiKeySvc,
+		keyring:      keyring,
+		metrics:      metrics,
+		principals:   tlsx.ParsePrincipals(cfg.TLSClientPrincipals),
+		guard:        g,
+		banThreshold: int64(cfg.BruteForce.APIKeyBanThreshold),
+		banDuration:  time.Duration(cfg.BruteForce.APIKeyBanDuration) * time.Second,
+	}
+}
 
-	return m
+// Principal returns the client certificate principal the request was
+// authenticated as, empty when it used an API key.
+func Principal(c *fiber.Ctx) string {
+	principal, _ := c.Locals(principalKey{}).(string)
+	return principal
 }
 
+// Validate accepts callers presenting a client certificate mapped to a
+// principal, and otherwise requires a valid, unrevoked X-API-Key. Client IPs
+// sending invalid keys are slowed down, then banned for a while.
 func (m *middlewareImpl) Validate() fiber.Handler {
 	return func(c *fiber.Ctx) error {
+		if principal, ok := m.principals.Lookup(c.Context().TLSConnectionState()); ok {
+			c.Locals(principalKey{}, principal)
+			logx.AddFields(c, logrus.Fields{
+				"principal": principal,
+			})
+
+			return c.Next()
+		}
+
+		if waitErr := m.checkBan(c); waitErr != nil {
+			m.metrics.AuthFailure("apikey", ReasonBanned)
+			return apperror.RateLimited(ErrAPIKeyBanned.Code, ErrAPIKeyBanned.Message, waitErr.RetryAfter)
+		}
+
 		key := strings.Trim(c.Get("X-API-Key"), " ")
 		if len(key) == 0 {
-			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-				"message": "Unauthorized",
-			})
+			m.metrics.AuthFailure("apikey", ReasonMissing)
+			return ErrAPIKeyRequired
 		}
 
-		// Parse and validate token
-		token, err := jwt.Parse(key, func(token *jwt.Token) (any, error) {
-			return []byte(m.cfg.SecretKey), nil
-		})
+		// Parse and validate token with the key of its kid
+		token, err := m.keyring.Parse(key, jwt.MapClaims{})
 
 		if err != nil || !token.Valid {
-			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"message": "Invalid or expired api key"})
+			m.metrics.AuthFailure("apikey", ReasonInvalid)
+			m.fail(c)
+			return ErrInvalidAPIKey
 		}
 
 		// If token valid, we then check is api key is revoke or not
-		apiKey, err := m.s.FindByID(c.Context(), key)
+		apiKey, err := m.s.FindByID(c.UserContext(), key)
 		if err != nil || apiKey == (model.APIKeyDTO{}) {
-			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-				"message": "Api key not found or was been revoked",
-			})
+			m.metrics.AuthFailure("apikey", ReasonRevoked)
+			m.fail(c)
+			return ErrAPIKeyRevoked
 		}
 
+		logx.AddFields(c, logrus.Fields{
+			"apikey": apiKey.Name,
+		})
+
 		return c.Next()
 	}
 }
+
+func banKey(c *fiber.Ctx) string {
+	return "apikey:ip:" + c.IP()
+}
+
+// checkBan returns a *guard.WaitError while the client IP is slowed down or
+// banned for sending invalid keys.
+func (m *middlewareImpl) checkBan(c *fiber.Ctx) *guard.WaitError {
+	if m.banThreshold == 0 {
+		return nil
+	}
+
+	var waitErr *guard.WaitError
+	if errors.As(m.guard.Check(c.UserContext(), banKey(c)), &waitErr) {
+		return waitErr
+	}
+	return nil
+}
+
+// fail records an invalid key of the client IP, banning it once its
+// failures reach the ban threshold.
+func (m *middlewareImpl) fail(c *fiber.Ctx) {
+	if m.banThreshold == 0 {
+		return
+	}
+
+	key := banKey(c)
+	failures := m.guard.Fail(c.UserContext(), key)
+	if failures < m.banThreshold {
+		return
+	}
+
+	m.guard.Ban(c.UserContext(), key, m.banDuration)
+	m.guard.Reset(c.UserContext(), key)
+	logx.FromContext(c.UserContext()).Warnf("apikey middleware banned %s for %v after %d invalid api keys", c.IP(), m.banDuration, failures)
+}
@@ -0,0 +1,165 @@
+package apikey_test
+
+import (
+	"go-fiber-api/internal/core/config"
+	"go-fiber-api/internal/core/guard"
+	"go-fiber-api/internal/core/model"
+	"go-fiber-api/internal/mock"
+	"go-fiber-api/internal/wrapper/jwtx"
+	"go-fiber-api/toolkit/errorhandler"
+	"io"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	apikey_middleware "go-fiber-api/internal/core/middleware/apikey"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestMiddleware_Validate(t *testing.T) {
+	cfg := &config.Configuration{
+		SecretKey: "TEST_SECRET_KEY",
+		JWT:       config.JWTConfig{LegacyEnabled: true},
+		BruteForce: config.BruteForceConfig{
+			APIKeyBanThreshold: 10,
+			APIKeyBanDuration:  900,
+		},
+	}
+	keyring, err := jwtx.Provide(cfg)
+	assert.NoError(t, err)
+	key, err := keyring.Sign(jwt.MapClaims{"name": "ci"})
+	assert.NoError(t, err)
+
+	const banKey = "apikey:ip:0.0.0.0"
+
+	tests := []struct {
+		name           string
+		apiKey         string
+		bansDisabled   bool
+		s              func(m *mock.MockAPIKeyService)
+		g              func(m *mock.MockGuard)
+		expectedStatus int
+		expectedBody   string
+		expectedRetry  string
+	}{
+		{
+			name:   "when_key_is_valid_should_pass",
+			apiKey: key,
+			s: func(m *mock.MockAPIKeyService) {
+				m.EXPECT().FindByID(gomock.Any(), key).Return(model.APIKeyDTO{Name: "ci"}, nil)
+			},
+			g: func(m *mock.MockGuard) {
+				m.EXPECT().Check(gomock.Any(), banKey).Return(nil)
+			},
+			expectedStatus: fiber.StatusOK,
+			expectedBody:   "ok",
+		},
+		{
+			name: "when_key_is_missing_should_return_401_without_counting_it",
+			s:    func(m *mock.MockAPIKeyService) {},
+			g: func(m *mock.MockGuard) {
+				m.EXPECT().Check(gomock.Any(), banKey).Return(nil)
+			},
+			expectedStatus: fiber.StatusUnauthorized,
+			expectedBody:   `{"code":"API_KEY_REQUIRED","message":"api key is required","ok":false}`,
+		},
+		{
+			name:   "when_key_is_invalid_should_count_it",
+			apiKey: "forged",
+			s:      func(m *mock.MockAPIKeyService) {},
+			g: func(m *mock.MockGuard) {
+				m.EXPECT().Check(gomock.Any(), banKey).Return(nil)
+				m.EXPECT().Fail(gomock.Any(), banKey).Return(int64(9))
+			},
+			expectedStatus: fiber.StatusUnauthorized,
+			expectedBody:   `{"code":"INVALID_API_KEY","message":"invalid or expired api key","ok":false}`,
+		},
+		{
+			name:   "when_key_is_revoked_should_count_it",
+			apiKey: key,
+			s: func(m *mock.MockAPIKeyService) {
+				m.EXPECT().FindByID(gomock.Any(), key).Return(model.APIKeyDTO{}, nil)
+			},
+			g: func(m *mock.MockGuard) {
+				m.EXPECT().Check(gomock.Any(), banKey).Return(nil)
+				m.EXPECT().Fail(gomock.Any(), banKey).Return(int64(1))
+			},
+			expectedStatus: fiber.StatusUnauthorized,
+			expectedBody:   `{"code":"API_KEY_REVOKED","message":"api key not found or revoked","ok":false}`,
+		},
+		{
+			name:   "when_invalid_keys_reach_threshold_should_ban_ip",
+			apiKey: "forged",
+			s:      func(m *mock.MockAPIKeyService) {},
+			g: func(m *mock.MockGuard) {
+				m.EXPECT().Check(gomock.Any(), banKey).Return(nil)
+				m.EXPECT().Fail(gomock.Any(), banKey).Return(int64(10))
+				m.EXPECT().Ban(gomock.Any(), banKey, 15*time.Minute)
+				m.EXPECT().Reset(gomock.Any(), banKey)
+			},
+			expectedStatus: fiber.StatusUnauthorized,
+			expectedBody:   `{"code":"INVALID_API_KEY","message":"invalid or expired api key","ok":false}`,
+		},
+		{
+			name:   "when_ip_is_banned_should_return_429_with_retry_after",
+			apiKey: key,
+			s:      func(m *mock.MockAPIKeyService) {},
+			g: func(m *mock.MockGuard) {
+				m.EXPECT().Check(gomock.Any(), banKey).Return(&guard.WaitError{RetryAfter: 10 * time.Minute})
+			},
+			expectedStatus: fiber.StatusTooManyRequests,
+			expectedBody:   `{"code":"API_KEY_BANNED","message":"too many invalid api keys","ok":false}`,
+			expectedRetry:  "600",
+		},
+		{
+			name:           "when_bans_are_disabled_should_not_count_keys",
+			apiKey:         "forged",
+			bansDisabled:   true,
+			s:              func(m *mock.MockAPIKeyService) {},
+			g:              func(m *mock.MockGuard) {},
+			expectedStatus: fiber.StatusUnauthorized,
+			expectedBody:   `{"code":"INVALID_API_KEY","message":"invalid or expired api key","ok":false}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			s := mock.NewMockAPIKeyService(ctrl)
+			tt.s(s)
+			g := mock.NewMockGuard(ctrl)
+			tt.g(g)
+
+			cfg := *cfg
+			if tt.bansDisabled {
+				cfg.BruteForce.APIKeyBanThreshold = 0
+			}
+
+			app := fiber.New(fiber.Config{ErrorHandler: errorhandler.Handler()})
+			app.Use(apikey_middleware.Provide(&cfg, s, keyring, nil, g).Validate())
+			app.Get("/", func(c *fiber.Ctx) error {
+				return c.SendString("ok")
+			})
+
+			req := httptest.NewRequest(fiber.MethodGet, "/", nil)
+			if tt.apiKey != "" {
+				req.Header.Set("X-API-Key", tt.apiKey)
+			}
+
+			// test logic
+			resp, err := app.Test(req)
+			assert.NoError(t, err)
+			body, _ := io.ReadAll(resp.Body)
+
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+			assert.Equal(t, tt.expectedBody, string(body))
+			assert.Equal(t, tt.expectedRetry, resp.Header.Get(fiber.HeaderRetryAfter))
+		})
+	}
+}
@@ -6,7 +6,10 @@ package apikey
 
 import (
 	"go-fiber-api/internal/core/config"
+	"go-fiber-api/internal/core/guard"
 	"go-fiber-api/internal/feature/apikey"
+	"go-fiber-api/internal/wrapper/jwtx"
+	"go-fiber-api/internal/wrapper/metrics"
 
 	"github.com/google/wire"
 )
@@ -15,7 +18,7 @@ var ProviderSet = wire.NewSet(
 	Provide,
 )
 
-func Wire(config *config.Configuration, apikeyService apikey.Service) (Middleware, error) {
+func Wire(config *config.Configuration, apikeyService apikey.Service, keyring *jwtx.Keyring, m *metrics.Metrics, g guard.Guard) (Middleware, error) {
 	wire.Build(ProviderSet)
 
 	return &middlewareImpl{}, nil
@@ -9,13 +9,16 @@ package apikey
 import (
 	"github.com/google/wire"
 	"go-fiber-api/internal/core/config"
+	"go-fiber-api/internal/core/guard"
 	"go-fiber-api/internal/feature/apikey"
+	"go-fiber-api/internal/wrapper/jwtx"
+	"go-fiber-api/internal/wrapper/metrics"
 )
 
 // Injectors from wire.go:
 
-func Wire(config2 *config.Configuration, apikeyService apikey.Service) (Middleware, error) {
-	middleware := Provide(config2, apikeyService)
+func Wire(config2 *config.Configuration, apikeyService apikey.Service, keyring *jwtx.Keyring, m *metrics.Metrics, g guard.Guard) (Middleware, error) {
+	middleware := Provide(config2, apikeyService, keyring, m, g)
 	return middleware, nil
 }
 
@@ -0,0 +1,86 @@
+package auth
+
+import (
+	"errors"
+	"go-fiber-api/internal/core/model"
+	"go-fiber-api/internal/feature/auth"
+	"go-fiber-api/internal/wrapper/logx"
+	"go-fiber-api/internal/wrapper/metrics"
+	"go-fiber-api/toolkit/apperror"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+type Middleware interface {
+	Authenticate() fiber.Handler
+}
+
+// Reasons an access token is rejected, recorded in the auth failure metric.
+const (
+	ReasonMissing = "missing"
+	ReasonInvalid = "invalid"
+	ReasonLocked  = "locked"
+	ReasonBlocked = "blocked"
+)
+
+// ErrTokenRequired is returned for requests without an access token.
+var ErrTokenRequired = apperror.Unauthorized("TOKEN_REQUIRED", "access token is required")
+
+type userKey struct{}
+
+type middlewareImpl struct {
+	s       auth.Service
+	metrics *metrics.Metrics
+}
+
+func Provide(authSvc auth.Service, metrics *metrics.Metrics) Middleware {
+	return &middlewareImpl{
+		s:       authSvc,
+		metrics: metrics,
+	}
+}
+
+// User returns the user the request was authenticated as by Authenticate.
+func User(c *fiber.Ctx) (model.UserDTO, bool) {
+	user, ok := c.Locals(userKey{}).(model.UserDTO)
+	return user, ok
+}
+
+// Authenticate requires an "Authorization: Bearer" access token of a user
+// who is neither locked nor blocked, loading the user into the context.
+func (m *middlewareImpl) Authenticate() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		scheme, token, _ := strings.Cut(c.Get(fiber.HeaderAuthorization), " ")
+		token = strings.TrimSpace(token)
+		if !strings.EqualFold(scheme, "Bearer") || token == "" {
+			m.metrics.AuthFailure("user", ReasonMissing)
+			c.Set(fiber.HeaderWWWAuthenticate, "Bearer")
+			return ErrTokenRequired
+		}
+
+		user, err := m.s.Authenticate(c.UserContext(), token)
+		switch {
+		case errors.Is(err, auth.ErrUserLocked):
+			m.metrics.AuthFailure("user", ReasonLocked)
+			return err
+		case errors.Is(err, auth.ErrUserBlocked):
+			m.metrics.AuthFailure("user", ReasonBlocked)
+			return err
+		case errors.Is(err, auth.ErrInvalidToken):
+			m.metrics.AuthFailure("user", ReasonInvalid)
+			c.Set(fiber.HeaderWWWAuthenticate, `Bearer error="invalid_token"`)
+			return err
+		case err != nil:
+			return err
+		}
+
+		c.Locals(userKey{}, user)
+		logx.AddFields(c, logrus.Fields{
+			"user_id": user.ID,
+		})
+
+		return c.Next()
+	}
+}
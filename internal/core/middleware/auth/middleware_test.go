@@ -0,0 +1,137 @@
+package auth_test
+
+import (
+	"errors"
+	"go-fiber-api/internal/core/model"
+	"go-fiber-api/internal/feature/auth"
+	"go-fiber-api/internal/mock"
+	"go-fiber-api/toolkit/errorhandler"
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	auth_middleware "go-fiber-api/internal/core/middleware/auth"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+)
+
+func TestMiddleware_Authenticate(t *testing.T) {
+	alice := model.UserDTO{Model: gorm.Model{ID: 7}, Username: "alice", Status: model.UserStatusNormal}
+
+	tests := []struct {
+		name                  string
+		authorization         string
+		accept                string
+		err                   error
+		expectedStatus        int
+		expectedBody          string
+		expectedAuthenticate  string
+		expectedWWWAuthHeader string
+		expectedContentType   string
+	}{
+		{
+			name:                  "when_token_is_missing_should_return_401",
+			expectedStatus:        fiber.StatusUnauthorized,
+			expectedBody:          `{"code":"TOKEN_REQUIRED","message":"access token is required","ok":false}`,
+			expectedWWWAuthHeader: "Bearer",
+		},
+		{
+			name:                  "when_scheme_is_not_bearer_should_return_401",
+			authorization:         "Basic YWxpY2U6cGFzc3dvcmQ=",
+			expectedStatus:        fiber.StatusUnauthorized,
+			expectedBody:          `{"code":"TOKEN_REQUIRED","message":"access token is required","ok":false}`,
+			expectedWWWAuthHeader: "Bearer",
+		},
+		{
+			name:                  "when_problem_is_accepted_should_return_401_as_problem",
+			accept:                "application/problem+json",
+			expectedStatus:        fiber.StatusUnauthorized,
+			expectedBody:          `{"type":"about:blank","title":"Unauthorized","status":401,"detail":"access token is required","code":"TOKEN_REQUIRED"}`,
+			expectedContentType:   "application/problem+json",
+			expectedWWWAuthHeader: "Bearer",
+		},
+		{
+			name:                  "when_token_is_invalid_should_return_401",
+			authorization:         "Bearer expired",
+			err:                   auth.ErrInvalidToken,
+			expectedStatus:        fiber.StatusUnauthorized,
+			expectedBody:          `{"code":"INVALID_TOKEN","message":"invalid or expired token","ok":false}`,
+			expectedAuthenticate:  "expired",
+			expectedWWWAuthHeader: `Bearer error="invalid_token"`,
+		},
+		{
+			name:                 "when_user_is_locked_should_return_403",
+			authorization:        "Bearer token",
+			err:                  auth.ErrUserLocked,
+			expectedStatus:       fiber.StatusForbidden,
+			expectedBody:         `{"code":"USER_LOCKED","message":"user is locked","ok":false}`,
+			expectedAuthenticate: "token",
+		},
+		{
+			name:                 "when_user_is_blocked_should_return_403",
+			authorization:        "Bearer token",
+			err:                  auth.ErrUserBlocked,
+			expectedStatus:       fiber.StatusForbidden,
+			expectedBody:         `{"code":"USER_BLOCKED","message":"user is blocked","ok":false}`,
+			expectedAuthenticate: "token",
+		},
+		{
+			name:                 "when_lookup_fails_should_return_500",
+			authorization:        "Bearer token",
+			err:                  errors.New("mock error"),
+			expectedStatus:       fiber.StatusInternalServerError,
+			expectedBody:         `{"code":"INTERNAL","message":"Internal Server Error","ok":false}`,
+			expectedAuthenticate: "token",
+		},
+		{
+			name:                 "when_token_is_valid_should_load_user",
+			authorization:        "bearer token",
+			expectedStatus:       fiber.StatusOK,
+			expectedBody:         "alice",
+			expectedAuthenticate: "token",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			s := mock.NewMockAuthService(ctrl)
+			if tt.expectedAuthenticate != "" {
+				s.EXPECT().Authenticate(gomock.Any(), tt.expectedAuthenticate).Return(alice, tt.err)
+			}
+
+			app := fiber.New(fiber.Config{ErrorHandler: errorhandler.Handler()})
+			app.Use(auth_middleware.Provide(s, nil).Authenticate())
+			app.Get("/", func(c *fiber.Ctx) error {
+				user, ok := auth_middleware.User(c)
+				assert.True(t, ok)
+				return c.SendString(user.Username)
+			})
+
+			req := httptest.NewRequest(fiber.MethodGet, "/", nil)
+			if tt.authorization != "" {
+				req.Header.Set(fiber.HeaderAuthorization, tt.authorization)
+			}
+			if tt.accept != "" {
+				req.Header.Set(fiber.HeaderAccept, tt.accept)
+			}
+
+			// test logic
+			resp, err := app.Test(req)
+			assert.NoError(t, err)
+			body, _ := io.ReadAll(resp.Body)
+
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+			assert.Equal(t, tt.expectedBody, string(body))
+			assert.Equal(t, tt.expectedWWWAuthHeader, resp.Header.Get(fiber.HeaderWWWAuthenticate))
+			if tt.expectedContentType != "" {
+				assert.Equal(t, tt.expectedContentType, resp.Header.Get(fiber.HeaderContentType))
+			}
+		})
+	}
+}
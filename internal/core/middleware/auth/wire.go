@@ -0,0 +1,22 @@
+//go:build wireinject
+// +build wireinject
+
+//go:generate wire
+package auth
+
+import (
+	"go-fiber-api/internal/feature/auth"
+	"go-fiber-api/internal/wrapper/metrics"
+
+	"github.com/google/wire"
+)
+
+var ProviderSet = wire.NewSet(
+	Provide,
+)
+
+func Wire(authService auth.Service, m *metrics.Metrics) (Middleware, error) {
+	wire.Build(ProviderSet)
+
+	return &middlewareImpl{}, nil
+}
@@ -0,0 +1,78 @@
+package authz
+
+import (
+	auth_middleware "go-fiber-api/internal/core/middleware/auth"
+	"go-fiber-api/internal/feature/rbac"
+	"go-fiber-api/internal/wrapper/metrics"
+	"go-fiber-api/toolkit/apperror"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type Middleware interface {
+	// Require lets through the users having every one of permissions. It
+	// follows the auth middleware, which loads the user.
+	Require(permissions ...string) fiber.Handler
+	// Bootstrap lets through the requests as long as no user has permission,
+	// so that another credential, such as an API key, appoints the first
+	// users having it. It follows the middleware checking that credential.
+	Bootstrap(permission string) fiber.Handler
+}
+
+// ReasonForbidden is recorded in the auth failure metric when a user lacks a
+// permission.
+const ReasonForbidden = "forbidden"
+
+var (
+	// ErrPermissionDenied is returned to users lacking a required permission.
+	ErrPermissionDenied = apperror.Forbidden("PERMISSION_DENIED", "permission denied")
+	// ErrBootstrapClosed is returned by Bootstrap once a user has the
+	// permission.
+	ErrBootstrapClosed = apperror.Forbidden("BOOTSTRAP_CLOSED", "bootstrap is closed, sign in as an administrator")
+)
+
+type middlewareImpl struct {
+	s       rbac.Service
+	metrics *metrics.Metrics
+}
+
+func Provide(rbacSvc rbac.Service, metrics *metrics.Metrics) Middleware {
+	return &middlewareImpl{
+		s:       rbacSvc,
+		metrics: metrics,
+	}
+}
+
+func (m *middlewareImpl) Require(permissions ...string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		user, ok := auth_middleware.User(c)
+		if !ok {
+			return auth_middleware.ErrTokenRequired
+		}
+
+		allowed, err := m.s.HasPermissions(c.UserContext(), user.ID, permissions...)
+		if err != nil {
+			return err
+		}
+		if !allowed {
+			m.metrics.AuthFailure("user", ReasonForbidden)
+			return ErrPermissionDenied
+		}
+
+		return c.Next()
+	}
+}
+
+func (m *middlewareImpl) Bootstrap(permission string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		granted, err := m.s.IsGranted(c.UserContext(), permission)
+		if err != nil {
+			return err
+		}
+		if granted {
+			return ErrBootstrapClosed
+		}
+
+		return c.Next()
+	}
+}
@@ -0,0 +1,143 @@
+package authz_test
+
+import (
+	"errors"
+	"go-fiber-api/internal/core/model"
+	"go-fiber-api/internal/feature/rbac"
+	"go-fiber-api/internal/mock"
+	"go-fiber-api/toolkit/errorhandler"
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	auth_middleware "go-fiber-api/internal/core/middleware/auth"
+	"go-fiber-api/internal/core/middleware/authz"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+)
+
+func TestMiddleware_Require(t *testing.T) {
+	alice := model.UserDTO{Model: gorm.Model{ID: 7}, Username: "alice", Status: model.UserStatusNormal}
+
+	tests := []struct {
+		name           string
+		authenticated  bool
+		allowed        bool
+		err            error
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "when_user_is_missing_should_return_401",
+			expectedStatus: fiber.StatusUnauthorized,
+			expectedBody:   `{"code":"TOKEN_REQUIRED","message":"access token is required","ok":false}`,
+		},
+		{
+			name:           "when_user_lacks_permission_should_return_403",
+			authenticated:  true,
+			expectedStatus: fiber.StatusForbidden,
+			expectedBody:   `{"code":"PERMISSION_DENIED","message":"permission denied","ok":false}`,
+		},
+		{
+			name:           "when_lookup_fails_should_return_500",
+			authenticated:  true,
+			err:            errors.New("mock error"),
+			expectedStatus: fiber.StatusInternalServerError,
+			expectedBody:   `{"code":"INTERNAL","message":"Internal Server Error","ok":false}`,
+		},
+		{
+			name:           "when_user_has_permission_should_pass",
+			authenticated:  true,
+			allowed:        true,
+			expectedStatus: fiber.StatusOK,
+			expectedBody:   "ok",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			s := mock.NewMockRBACService(ctrl)
+			app := fiber.New(fiber.Config{ErrorHandler: errorhandler.Handler()})
+			if tt.authenticated {
+				authSvc := mock.NewMockAuthService(ctrl)
+				authSvc.EXPECT().Authenticate(gomock.Any(), "token").Return(alice, nil)
+				app.Use(auth_middleware.Provide(authSvc, nil).Authenticate())
+
+				s.EXPECT().HasPermissions(gomock.Any(), alice.ID, rbac.PermissionRolesManage).Return(tt.allowed, tt.err)
+			}
+			app.Use(authz.Provide(s, nil).Require(rbac.PermissionRolesManage))
+			app.Get("/", func(c *fiber.Ctx) error {
+				return c.SendString("ok")
+			})
+
+			req := httptest.NewRequest(fiber.MethodGet, "/", nil)
+			req.Header.Set(fiber.HeaderAuthorization, "Bearer token")
+
+			// test logic
+			resp, err := app.Test(req)
+			assert.NoError(t, err)
+			body, _ := io.ReadAll(resp.Body)
+
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+			assert.Equal(t, tt.expectedBody, string(body))
+		})
+	}
+}
+
+func TestMiddleware_Bootstrap(t *testing.T) {
+	tests := []struct {
+		name           string
+		granted        bool
+		err            error
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "when_no_user_has_permission_should_pass",
+			expectedStatus: fiber.StatusOK,
+			expectedBody:   "ok",
+		},
+		{
+			name:           "when_a_user_has_permission_should_return_403",
+			granted:        true,
+			expectedStatus: fiber.StatusForbidden,
+			expectedBody:   `{"code":"BOOTSTRAP_CLOSED","message":"bootstrap is closed, sign in as an administrator","ok":false}`,
+		},
+		{
+			name:           "when_lookup_fails_should_return_500",
+			err:            errors.New("mock error"),
+			expectedStatus: fiber.StatusInternalServerError,
+			expectedBody:   `{"code":"INTERNAL","message":"Internal Server Error","ok":false}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			s := mock.NewMockRBACService(ctrl)
+			s.EXPECT().IsGranted(gomock.Any(), rbac.PermissionAdmin).Return(tt.granted, tt.err)
+
+			app := fiber.New(fiber.Config{ErrorHandler: errorhandler.Handler()})
+			app.Use(authz.Provide(s, nil).Bootstrap(rbac.PermissionAdmin))
+			app.Get("/", func(c *fiber.Ctx) error {
+				return c.SendString("ok")
+			})
+
+			// test logic
+			resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/", nil))
+			assert.NoError(t, err)
+			body, _ := io.ReadAll(resp.Body)
+
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+			assert.Equal(t, tt.expectedBody, string(body))
+		})
+	}
+}
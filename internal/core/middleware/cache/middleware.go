@@ -1,10 +1,13 @@
 package cache
 
 import (
+	"errors"
 	"fmt"
-	"go-fiber-api/internal/wrapper/redis"
+	"go-fiber-api/internal/core/config"
+	cache_storage "go-fiber-api/internal/core/storage/cache"
+	"go-fiber-api/internal/wrapper/metrics"
 	"strings"
-	"sync"
+	"sync/atomic"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/sirupsen/logrus"
@@ -14,34 +17,77 @@ const (
 	baseKey string = "cache:%s:%s:%s"
 )
 
-var (
-	m     *cacheMiddlewareImpl
-	mOnce sync.Once
-)
-
 type CacheMiddleware interface {
-	RedisCacheMiddleware(ttl int) fiber.Handler
+	RedisCacheMiddleware() fiber.Handler
+	Stats() Stats
+}
+
+// Stats counts how requests were served by the cache middleware. Bypasses are
+// requests that skipped caching because the backend was unavailable.
+type Stats struct {
+	Hits     uint64 `json:"hits"`
+	Misses   uint64 `json:"misses"`
+	Bypasses uint64 `json:"bypasses"`
 }
 
 type cacheMiddlewareImpl struct {
-	rc redis.Client
+	c       cache_storage.Cache
+	metrics *metrics.Metrics
+	ttl     atomic.Int64 // seconds, CACHE_TTL
+
+	hits     atomic.Uint64
+	misses   atomic.Uint64
+	bypasses atomic.Uint64
+	degraded atomic.Bool
 }
 
-func New(rc redis.Client) CacheMiddleware {
-	mOnce.Do(func() {
-		m = &cacheMiddlewareImpl{
-			rc: rc,
-		}
-	})
+// New caches responses for CACHE_TTL of cfg, following its changes through w.
+func New(c cache_storage.Cache, metrics *metrics.Metrics, cfg *config.Configuration, w *config.Watcher) CacheMiddleware {
+	m := &cacheMiddlewareImpl{
+		c:       c,
+		metrics: metrics,
+	}
+	m.ttl.Store(int64(cfg.CacheTTL))
+
+	w.Subscribe(func(change config.Change) {
+		m.ttl.Store(int64(change.New.CacheTTL))
+	}, "CACHE_TTL")
 
 	return m
 }
 
-func Close() {
-	mOnce = sync.Once{}
+func (m *cacheMiddlewareImpl) Stats() Stats {
+	return Stats{
+		Hits:     m.hits.Load(),
+		Misses:   m.misses.Load(),
+		Bypasses: m.bypasses.Load(),
+	}
+}
+
+// bypass records a request served without cache because of err, warning only
+// once per outage to keep logs readable.
+func (m *cacheMiddlewareImpl) bypass(err error) {
+	m.bypasses.Add(1)
+	m.metrics.CacheResult(metrics.CacheBypass)
+	if m.degraded.CompareAndSwap(false, true) {
+		logrus.Warnf("cache backend unavailable, bypassing cache: %v", err)
+	}
 }
 
-func (m *cacheMiddlewareImpl) RedisCacheMiddleware(ttl int) fiber.Handler {
+func (m *cacheMiddlewareImpl) recover() {
+	if m.degraded.CompareAndSwap(true, false) {
+		logrus.Infof("cache backend available again")
+	}
+}
+
+// hasClientCertificate reports whether the client of c presented a TLS
+// certificate, a credential too.
+func hasClientCertificate(c *fiber.Ctx) bool {
+	state := c.Context().TLSConnectionState()
+	return state != nil && len(state.PeerCertificates) > 0
+}
+
+func (m *cacheMiddlewareImpl) RedisCacheMiddleware() fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		// Clear cache for POST requests
 		if c.Method() == fiber.MethodPost {
@@ -49,7 +95,7 @@ func (m *cacheMiddlewareImpl) RedisCacheMiddleware(ttl int) fiber.Handler {
 				return c.Next()
 			}
 			cacheKey := fmt.Sprintf(baseKey, fiber.MethodGet, c.Path(), "")
-			err := m.rc.Del(cacheKey)
+			err := m.c.Del(c.UserContext(), cacheKey)
 			if err != nil {
 				logrus.Warnf("failed to clear cache: %v", err)
 			}
@@ -61,6 +107,11 @@ func (m *cacheMiddlewareImpl) RedisCacheMiddleware(ttl int) fiber.Handler {
 			return c.Next()
 		}
 
+		// Responses to credentials are for their holder only, never shared
+		if c.Get(fiber.HeaderAuthorization) != "" || c.Get("X-API-Key") != "" || hasClientCertificate(c) {
+			return c.Next()
+		}
+
 		// Generate cache key using method + path
 		queries := ""
 		if len(c.Queries()) > 0 {
@@ -70,15 +121,37 @@ func (m *cacheMiddlewareImpl) RedisCacheMiddleware(ttl int) fiber.Handler {
 
 		var cachedResponse any
 
-		err := m.rc.Get(cacheKey, &cachedResponse)
-		if err == nil {
+		err := m.c.Get(c.UserContext(), cacheKey, &cachedResponse)
+		switch {
+		case err == nil:
+			m.recover()
+			m.hits.Add(1)
+			m.metrics.CacheResult(metrics.CacheHit)
+
 			// Return cached response
 			c.Set("X-Cache", "HIT") // Mark response as cache hit
 
 			return c.JSON(cachedResponse)
-		} else {
+		case errors.Is(err, cache_storage.ErrMiss), errors.Is(err, cache_storage.ErrDecode):
+			m.recover()
+			if errors.Is(err, cache_storage.ErrDecode) {
+				// Not a response cached by this middleware, replace it
+				logrus.Warnf("dropping undecodable cached response %s: %v", cacheKey, err)
+				if err := m.c.Del(c.UserContext(), cacheKey); err != nil {
+					logrus.Warnf("failed to clear cache: %v", err)
+				}
+			}
+			m.misses.Add(1)
+			m.metrics.CacheResult(metrics.CacheMiss)
+
 			// Mark as cache miss
 			c.Set("X-Cache", "MISS")
+		default:
+			// Backend is down, serve the request without caching
+			m.bypass(err)
+			c.Set("X-Cache", "BYPASS")
+
+			return c.Next()
 		}
 
 		// Continue with request processing
@@ -87,16 +160,16 @@ func (m *cacheMiddlewareImpl) RedisCacheMiddleware(ttl int) fiber.Handler {
 			return err
 		}
 
-		// Store response in Redis
+		// Store response in cache, errors such as a 401 are not replayed, and
+		// only JSON is, as it is replayed with c.JSON
 		responseBody := c.Response().Body()
-		if len(responseBody) > 0 {
-			err := m.rc.Set(cacheKey, responseBody, ttl)
+		contentType := string(c.Response().Header.ContentType())
+		if c.Response().StatusCode() == fiber.StatusOK && len(responseBody) > 0 &&
+			strings.HasPrefix(contentType, fiber.MIMEApplicationJSON) {
+			err := m.c.Set(c.UserContext(), cacheKey, responseBody, int(m.ttl.Load()))
 			if err != nil {
 				logrus.Warnf("failed to set cache: %v", err)
-				return nil
 			}
-
-			logrus.Warnf("success to set cache: %v", err)
 		}
 
 		return nil
@@ -3,10 +3,12 @@ package cache_test
 import (
 	"encoding/json"
 	"fmt"
+	"go-fiber-api/internal/core/config"
 	"go-fiber-api/internal/core/middleware/cache"
 	"go-fiber-api/internal/core/response"
+	cache_storage "go-fiber-api/internal/core/storage/cache"
 	"go-fiber-api/internal/mock"
-	"go-fiber-api/internal/wrapper/redis"
+	"go-fiber-api/internal/wrapper/metrics"
 	"io"
 	"net/http"
 	"net/http/httptest"
@@ -21,14 +23,16 @@ import (
 
 func TestRedisCacheMiddleware(t *testing.T) {
 	type dependency struct {
-		redisClient func(*gomock.Controller) redis.Client
+		cacheClient func(*gomock.Controller) cache_storage.Cache
 	}
 
 	tests := []struct {
-		name   string
-		method string
-		url    string
-		body   string
+		name          string
+		method        string
+		url           string
+		body          string
+		authorization string
+		apiKey        string
 		dependency
 		statusCode       int
 		cacheHeader      string
@@ -39,11 +43,11 @@ func TestRedisCacheMiddleware(t *testing.T) {
 			method: http.MethodGet,
 			url:    "/test",
 			dependency: dependency{
-				redisClient: func(ctrl *gomock.Controller) redis.Client {
-					m := mock.NewMockRedisClient(ctrl)
+				cacheClient: func(ctrl *gomock.Controller) cache_storage.Cache {
+					m := mock.NewMockCache(ctrl)
 					cacheKey := "cache:GET:/test:"
-					m.EXPECT().Get(cacheKey, gomock.Any()).Return(fmt.Errorf("cache miss"))
-					m.EXPECT().Set(cacheKey, []byte("{\"message\":\"get success\",\"data\":null}"), 60).Return(nil)
+					m.EXPECT().Get(gomock.Any(), cacheKey, gomock.Any()).Return(cache_storage.ErrMiss)
+					m.EXPECT().Set(gomock.Any(), cacheKey, []byte("{\"message\":\"get success\",\"data\":null}"), 45).Return(nil)
 					return m
 				},
 			},
@@ -56,11 +60,11 @@ func TestRedisCacheMiddleware(t *testing.T) {
 			method: http.MethodGet,
 			url:    "/test",
 			dependency: dependency{
-				redisClient: func(ctrl *gomock.Controller) redis.Client {
-					m := mock.NewMockRedisClient(ctrl)
+				cacheClient: func(ctrl *gomock.Controller) cache_storage.Cache {
+					m := mock.NewMockCache(ctrl)
 
 					cacheKey := "cache:GET:/test:"
-					m.EXPECT().Get(cacheKey, gomock.Any()).SetArg(1, response.ResponseDTO{
+					m.EXPECT().Get(gomock.Any(), cacheKey, gomock.Any()).SetArg(2, response.ResponseDTO{
 						Message: "Cache get success",
 					}).Return(nil)
 
@@ -71,17 +75,110 @@ func TestRedisCacheMiddleware(t *testing.T) {
 			cacheHeader:      "HIT",
 			expectedResponse: "map[data:<nil> message:Cache get success]",
 		},
+		{
+			name:   "should bypass cache when backend is unavailable",
+			method: http.MethodGet,
+			url:    "/test",
+			dependency: dependency{
+				cacheClient: func(ctrl *gomock.Controller) cache_storage.Cache {
+					m := mock.NewMockCache(ctrl)
+
+					cacheKey := "cache:GET:/test:"
+					m.EXPECT().Get(gomock.Any(), cacheKey, gomock.Any()).Return(fmt.Errorf("dial tcp: connection refused"))
+
+					return m
+				},
+			},
+			statusCode:       http.StatusOK,
+			cacheHeader:      "BYPASS",
+			expectedResponse: "map[data:<nil> message:get success]",
+		},
+		{
+			name:   "should replace undecodable cached response",
+			method: http.MethodGet,
+			url:    "/test",
+			dependency: dependency{
+				cacheClient: func(ctrl *gomock.Controller) cache_storage.Cache {
+					m := mock.NewMockCache(ctrl)
+
+					cacheKey := "cache:GET:/test:"
+					m.EXPECT().Get(gomock.Any(), cacheKey, gomock.Any()).Return(fmt.Errorf("%w: invalid character", cache_storage.ErrDecode))
+					m.EXPECT().Del(gomock.Any(), cacheKey).Return(nil)
+					m.EXPECT().Set(gomock.Any(), cacheKey, []byte("{\"message\":\"get success\",\"data\":null}"), 45).Return(nil)
+
+					return m
+				},
+			},
+			statusCode:       http.StatusOK,
+			cacheHeader:      "MISS",
+			expectedResponse: "map[data:<nil> message:get success]",
+		},
+		{
+			name:   "should not cache non JSON response",
+			method: http.MethodGet,
+			url:    "/text",
+			dependency: dependency{
+				cacheClient: func(ctrl *gomock.Controller) cache_storage.Cache {
+					m := mock.NewMockCache(ctrl)
+					m.EXPECT().Get(gomock.Any(), "cache:GET:/text:", gomock.Any()).Return(cache_storage.ErrMiss)
+					return m
+				},
+			},
+			statusCode:  http.StatusOK,
+			cacheHeader: "MISS",
+		},
+		{
+			name:          "should skip cache for GET request with authorization",
+			method:        http.MethodGet,
+			url:           "/test",
+			authorization: "Bearer token",
+			dependency: dependency{
+				cacheClient: func(ctrl *gomock.Controller) cache_storage.Cache {
+					return mock.NewMockCache(ctrl)
+				},
+			},
+			statusCode:       http.StatusOK,
+			expectedResponse: "map[data:<nil> message:get success]",
+		},
+		{
+			name:   "should skip cache for GET request with api key",
+			method: http.MethodGet,
+			url:    "/test",
+			apiKey: "key",
+			dependency: dependency{
+				cacheClient: func(ctrl *gomock.Controller) cache_storage.Cache {
+					return mock.NewMockCache(ctrl)
+				},
+			},
+			statusCode:       http.StatusOK,
+			expectedResponse: "map[data:<nil> message:get success]",
+		},
+		{
+			name:   "should not cache error response",
+			method: http.MethodGet,
+			url:    "/error",
+			dependency: dependency{
+				cacheClient: func(ctrl *gomock.Controller) cache_storage.Cache {
+					m := mock.NewMockCache(ctrl)
+					m.EXPECT().Get(gomock.Any(), "cache:GET:/error:", gomock.Any()).Return(cache_storage.ErrMiss)
+					return m
+				},
+			},
+			statusCode:       http.StatusUnauthorized,
+			cacheHeader:      "MISS",
+			expectedResponse: "map[data:<nil> message:Unauthorized]",
+		},
 		{
 			name:   "should clear cache for POST request",
 			method: http.MethodPost,
 			url:    "/test",
 			body:   `{}`,
 			dependency: dependency{
-				redisClient: func(ctrl *gomock.Controller) redis.Client {
-					m := mock.NewMockRedisClient(ctrl)
+				cacheClient: func(ctrl *gomock.Controller) cache_storage.Cache {
+					m := mock.NewMockCache(ctrl)
 
 					cacheKey := "cache:GET:/test:"
-					m.EXPECT().Del(cacheKey).Return(nil)
+					m.EXPECT().Del(gomock.Any(), cacheKey).Return(nil)
 
 					return m
 				},
@@ -96,11 +193,11 @@ func TestRedisCacheMiddleware(t *testing.T) {
 			ctrl := gomock.NewController(t)
 			defer ctrl.Finish()
 
-			c := cache.New(tt.redisClient(ctrl))
-			defer cache.Close()
+			m := metrics.New()
+			c := cache.New(tt.cacheClient(ctrl), m, &config.Configuration{CacheTTL: 45}, nil)
 
 			app := fiber.New()
-			app.Use(c.RedisCacheMiddleware(60))
+			app.Use(c.RedisCacheMiddleware())
 
 			app.Get("/test", func(c *fiber.Ctx) error {
 				return c.JSON(&response.ResponseDTO{
@@ -108,6 +205,16 @@ func TestRedisCacheMiddleware(t *testing.T) {
 				})
 			})
 
+			app.Get("/text", func(c *fiber.Ctx) error {
+				return c.SendString("get success")
+			})
+
+			app.Get("/error", func(c *fiber.Ctx) error {
+				return c.Status(fiber.StatusUnauthorized).JSON(&response.ResponseDTO{
+					Message: "Unauthorized",
+				})
+			})
+
 			app.Post("/test", func(c *fiber.Ctx) error {
 				return c.JSON(&response.ResponseDTO{
 					Message: "post success",
@@ -118,6 +225,12 @@ func TestRedisCacheMiddleware(t *testing.T) {
 			if tt.method == http.MethodPost {
 				req.Header.Set("Content-Type", "application/json")
 			}
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			if tt.apiKey != "" {
+				req.Header.Set("X-API-Key", tt.apiKey)
+			}
 			tenSecond := 10 * time.Second
 			resp, err := app.Test(req, int(tenSecond.Milliseconds()))
 
@@ -133,18 +246,42 @@ func TestRedisCacheMiddleware(t *testing.T) {
 			var data map[string]interface{}
 
 			// Unmarshal the JSON data into the map
-			err = json.Unmarshal(jsonBody, &data)
-			if err != nil {
-				fmt.Println("Error unmarshaling JSON:", err)
-				return
-			}
-			v := fmt.Sprintf("%+v", data)
+			if tt.expectedResponse != "" {
+				err = json.Unmarshal(jsonBody, &data)
+				if err != nil {
+					fmt.Println("Error unmarshaling JSON:", err)
+					return
+				}
+				v := fmt.Sprintf("%+v", data)
 
-			assert.Equal(t, tt.expectedResponse, v)
+				assert.Equal(t, tt.expectedResponse, v)
+			}
 
 			if tt.cacheHeader != "" {
 				assert.Equal(t, tt.cacheHeader, resp.Header.Get("X-Cache"))
+				assert.Equal(t, 1.0, cacheRequests(t, m, strings.ToLower(tt.cacheHeader)))
 			}
 		})
 	}
 }
+
+func cacheRequests(t *testing.T, m *metrics.Metrics, result string) float64 {
+	families, err := m.Registry().Gather()
+	assert.NoError(t, err)
+
+	for _, family := range families {
+		if family.GetName() != "go_fiber_api_cache_requests_total" {
+			continue
+		}
+
+		for _, metric := range family.GetMetric() {
+			for _, label := range metric.GetLabel() {
+				if label.GetName() == "result" && label.GetValue() == result {
+					return metric.GetCounter().GetValue()
+				}
+			}
+		}
+	}
+
+	return 0
+}
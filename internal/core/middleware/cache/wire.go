@@ -5,7 +5,9 @@
 package cache
 
 import (
-	"go-fiber-api/internal/wrapper/redis"
+	"go-fiber-api/internal/core/config"
+	cache_storage "go-fiber-api/internal/core/storage/cache"
+	"go-fiber-api/internal/wrapper/metrics"
 
 	"github.com/google/wire"
 )
@@ -14,7 +16,7 @@ var ProviderSet = wire.NewSet(
 	New,
 )
 
-func Wire(client redis.Client) CacheMiddleware {
+func Wire(c cache_storage.Cache, m *metrics.Metrics, cfg *config.Configuration, w *config.Watcher) CacheMiddleware {
 	wire.Build(ProviderSet)
 
 	return &cacheMiddlewareImpl{}
@@ -8,13 +8,15 @@ package cache
 
 import (
 	"github.com/google/wire"
-	"go-fiber-api/internal/wrapper/redis"
+	"go-fiber-api/internal/core/config"
+	"go-fiber-api/internal/core/storage/cache"
+	"go-fiber-api/internal/wrapper/metrics"
 )
 
 // Injectors from wire.go:
 
-func Wire(client redis.Client) CacheMiddleware {
-	cacheMiddleware := New(client)
+func Wire(c cache.Cache, m *metrics.Metrics, cfg *config.Configuration, w *config.Watcher) CacheMiddleware {
+	cacheMiddleware := New(c, m, cfg, w)
 	return cacheMiddleware
 }
 
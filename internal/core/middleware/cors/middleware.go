@@ -0,0 +1,46 @@
+package cors
+
+import (
+	"go-fiber-api/internal/core/config"
+	"sync/atomic"
+
+	"github.com/gofiber/fiber/v2"
+	fiber_cors "github.com/gofiber/fiber/v2/middleware/cors"
+)
+
+type Middleware interface {
+	CORS() fiber.Handler
+}
+
+type middlewareImpl struct {
+	handler atomic.Pointer[fiber.Handler]
+}
+
+// Provide allows the origins and headers of cfg, following their changes
+// through w.
+func Provide(cfg *config.Configuration, w *config.Watcher) Middleware {
+	m := &middlewareImpl{}
+	m.set(cfg.CORS)
+
+	w.Subscribe(func(change config.Change) {
+		m.set(change.New.CORS)
+	}, "CORS_ALLOWED_ORIGINS", "CORS_ALLOWED_HEADERS")
+
+	return m
+}
+
+// set swaps in a handler for cfg, the cors middleware being configured once
+// and for all when created.
+func (m *middlewareImpl) set(cfg config.CORSConfig) {
+	handler := fiber_cors.New(fiber_cors.Config{
+		AllowOrigins: cfg.AllowedOrigins, // Allow requests from frontend
+		AllowHeaders: cfg.AllowedHeaders,
+	})
+	m.handler.Store(&handler)
+}
+
+func (m *middlewareImpl) CORS() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		return (*m.handler.Load())(c)
+	}
+}
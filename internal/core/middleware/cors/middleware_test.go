@@ -0,0 +1,86 @@
+package cors_test
+
+import (
+	"go-fiber-api/internal/core/config"
+	"go-fiber-api/internal/core/lifecycle"
+	"go-fiber-api/internal/core/middleware/cors"
+	"go-fiber-api/internal/wrapper/logx"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCORS(t *testing.T) {
+	tests := []struct {
+		name           string
+		origins        string // allowed at startup
+		reloaded       string // allowed after a reload, if set
+		origin         string
+		expectedOrigin string
+	}{
+		{
+			name:           "when_origin_is_allowed_should_set_header",
+			origins:        "https://a.example.com",
+			origin:         "https://a.example.com",
+			expectedOrigin: "https://a.example.com",
+		},
+		{
+			name:    "when_origin_is_not_allowed_should_not_set_header",
+			origins: "https://a.example.com",
+			origin:  "https://b.example.com",
+		},
+		{
+			name:           "when_origins_are_reloaded_should_allow_new_ones",
+			origins:        "https://a.example.com",
+			reloaded:       "https://b.example.com",
+			origin:         "https://b.example.com",
+			expectedOrigin: "https://b.example.com",
+		},
+		{
+			name:     "when_origins_are_reloaded_should_drop_old_ones",
+			origins:  "https://a.example.com",
+			reloaded: "https://b.example.com",
+			origin:   "https://a.example.com",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(config.ConfigFileEnv, "")
+			t.Setenv("SECRET_KEY", "secret")
+			t.Setenv("DB_DRIVER", "sqlite")
+			t.Setenv("DB_NAME", ":memory:")
+			t.Setenv("REDIS_HOST", "localhost")
+			t.Setenv("REDIS_PORT", "6379")
+			t.Setenv("CORS_ALLOWED_ORIGINS", tt.origins)
+
+			log := logx.Provide(lifecycle.New())
+			cfg, err := config.Provide(log, nil)
+			assert.NoError(t, err)
+			w, err := config.NewWatcher(cfg, log, nil)
+			assert.NoError(t, err)
+
+			app := fiber.New()
+			app.Use(cors.Provide(cfg, w).CORS())
+			app.Get("/", func(c *fiber.Ctx) error {
+				return c.SendString("ok")
+			})
+
+			if tt.reloaded != "" {
+				t.Setenv("CORS_ALLOWED_ORIGINS", tt.reloaded)
+				assert.NoError(t, w.Reload())
+			}
+
+			// test logic
+			req := httptest.NewRequest(fiber.MethodGet, "/", nil)
+			req.Header.Set(fiber.HeaderOrigin, tt.origin)
+
+			resp, err := app.Test(req)
+			assert.NoError(t, err)
+			assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+			assert.Equal(t, tt.expectedOrigin, resp.Header.Get(fiber.HeaderAccessControlAllowOrigin))
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package logger
+
+import (
+	"go-fiber-api/internal/wrapper/logx"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/requestid"
+	"github.com/sirupsen/logrus"
+)
+
+type Middleware interface {
+	RequestLogger() fiber.Handler
+}
+
+type middlewareImpl struct {
+	log *logx.LogX
+}
+
+func Provide(log *logx.LogX) Middleware {
+	return &middlewareImpl{
+		log: log,
+	}
+}
+
+// RequestLogger stores a request scoped logger on the context, retrievable
+// with logx.FromContext, and logs a completion line once the request is
+// handled. It must run after requestid.New().
+func (m *middlewareImpl) RequestLogger() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		start := time.Now()
+
+		entry := m.log.WithFields(logrus.Fields{
+			"request_id": c.Locals(requestid.ConfigDefault.ContextKey),
+			"method":     c.Method(),
+			"path":       c.Path(),
+			"ip":         c.IP(),
+		}).WithContext(c.UserContext())
+		logx.Inject(c, entry)
+
+		entry.Debug("Incoming request")
+
+		// Errors are rendered here instead of after the middleware returns,
+		// so the completion line reports the final status.
+		chainErr := c.Next()
+		if chainErr != nil {
+			if err := c.App().ErrorHandler(c, chainErr); err != nil {
+				_ = c.SendStatus(fiber.StatusInternalServerError)
+			}
+		}
+
+		status := c.Response().StatusCode()
+		entry = logx.FromContext(c.UserContext()).WithFields(logrus.Fields{
+			"route":      c.Route().Path,
+			"status":     status,
+			"latency_ms": time.Since(start).Milliseconds(),
+			"bytes":      len(c.Response().Body()),
+		})
+		// With its cause, hidden from the client in production.
+		if chainErr != nil {
+			entry = entry.WithError(chainErr)
+		}
+
+		switch {
+		case status >= fiber.StatusInternalServerError:
+			entry.Error("Request completed")
+		case status >= fiber.StatusBadRequest:
+			entry.Warn("Request completed")
+		default:
+			entry.Info("Request completed")
+		}
+
+		return nil
+	}
+}
@@ -0,0 +1,113 @@
+package logger_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"go-fiber-api/internal/core/middleware/logger"
+	"go-fiber-api/internal/wrapper/logx"
+	"go-fiber-api/toolkit/errorhandler"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/requestid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestLogger(t *testing.T) {
+	tests := []struct {
+		name           string
+		handler        fiber.Handler
+		expectedStatus int
+		expectedLevel  string
+		expectedError  string
+	}{
+		{
+			name: "when_request_succeeds_should_log_info",
+			handler: func(c *fiber.Ctx) error {
+				logx.FromContext(c.Context()).Info("from service")
+				return c.SendString("hello")
+			},
+			expectedStatus: fiber.StatusOK,
+			expectedLevel:  "info",
+		},
+		{
+			name: "when_handler_returns_error_should_log_final_status",
+			handler: func(c *fiber.Ctx) error {
+				logx.FromContext(c.UserContext()).Info("from service")
+				return fiber.NewError(fiber.StatusNotFound, "not found")
+			},
+			expectedStatus: fiber.StatusNotFound,
+			expectedLevel:  "warning",
+			expectedError:  "not found",
+		},
+		{
+			name: "when_handler_fails_should_log_cause",
+			handler: func(c *fiber.Ctx) error {
+				logx.FromContext(c.UserContext()).Info("from service")
+				return errors.New("connection refused")
+			},
+			expectedStatus: fiber.StatusInternalServerError,
+			expectedLevel:  "error",
+			expectedError:  "connection refused",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			l := logrus.New()
+			l.SetOutput(&buf)
+			l.SetFormatter(&logrus.JSONFormatter{})
+
+			m := logger.Provide(&logx.LogX{Logger: l})
+
+			app := fiber.New(fiber.Config{ErrorHandler: errorhandler.Handler()})
+			app.Use(requestid.New())
+			app.Use(m.RequestLogger())
+			app.Use(func(c *fiber.Ctx) error {
+				logx.AddFields(c, logrus.Fields{"apikey": "test"})
+				return c.Next()
+			})
+			app.Get("/test/:id", tt.handler)
+
+			req := httptest.NewRequest(http.MethodGet, "/test/1", nil)
+			req.Header.Set(fiber.HeaderXRequestID, "test-request-id")
+
+			resp, err := app.Test(req)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+
+			lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+			if !assert.Len(t, lines, 2) {
+				return
+			}
+
+			var service, completion map[string]any
+			assert.NoError(t, json.Unmarshal([]byte(lines[0]), &service))
+			assert.NoError(t, json.Unmarshal([]byte(lines[1]), &completion))
+
+			assert.Equal(t, "from service", service["msg"])
+			assert.Equal(t, "test-request-id", service["request_id"])
+			assert.Equal(t, "test", service["apikey"])
+
+			assert.Equal(t, "Request completed", completion["msg"])
+			assert.Equal(t, tt.expectedLevel, completion["level"])
+			assert.Equal(t, "test-request-id", completion["request_id"])
+			assert.Equal(t, "test", completion["apikey"])
+			assert.Equal(t, "/test/:id", completion["route"])
+			assert.Equal(t, float64(tt.expectedStatus), completion["status"])
+			assert.Contains(t, completion, "latency_ms")
+			assert.Contains(t, completion, "bytes")
+			if tt.expectedError != "" {
+				assert.Equal(t, tt.expectedError, completion["error"])
+			} else {
+				assert.NotContains(t, completion, "error")
+			}
+		})
+	}
+}
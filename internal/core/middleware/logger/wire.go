@@ -0,0 +1,21 @@
+//go:build wireinject
+// +build wireinject
+
+//go:generate wire
+package logger
+
+import (
+	"go-fiber-api/internal/wrapper/logx"
+
+	"github.com/google/wire"
+)
+
+var ProviderSet = wire.NewSet(
+	Provide,
+)
+
+func Wire(log *logx.LogX) (Middleware, error) {
+	wire.Build(ProviderSet)
+
+	return &middlewareImpl{}, nil
+}
@@ -0,0 +1,25 @@
+// Code generated by Wire. DO NOT EDIT.
+
+//go:generate go run -mod=mod github.com/google/wire/cmd/wire
+//go:build !wireinject
+// +build !wireinject
+
+package logger
+
+import (
+	"github.com/google/wire"
+	"go-fiber-api/internal/wrapper/logx"
+)
+
+// Injectors from wire.go:
+
+func Wire(log *logx.LogX) (Middleware, error) {
+	middleware := Provide(log)
+	return middleware, nil
+}
+
+// wire.go:
+
+var ProviderSet = wire.NewSet(
+	Provide,
+)
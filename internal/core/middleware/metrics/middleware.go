@@ -0,0 +1,45 @@
+package metrics
+
+import (
+	metrics_wrapper "go-fiber-api/internal/wrapper/metrics"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type Middleware interface {
+	RequestMetrics() fiber.Handler
+}
+
+type middlewareImpl struct {
+	metrics *metrics_wrapper.Metrics
+}
+
+func Provide(metrics *metrics_wrapper.Metrics) Middleware {
+	return &middlewareImpl{
+		metrics: metrics,
+	}
+}
+
+// RequestMetrics counts and times every request by its route template, so
+// /users/1 and /users/2 share the /users/:id series. It must run before
+// logger.RequestLogger, which renders errors, to see the final status.
+func (m *middlewareImpl) RequestMetrics() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		start := time.Now()
+
+		err := c.Next()
+
+		status := c.Response().StatusCode()
+		if err != nil {
+			status = fiber.StatusInternalServerError
+			if e, ok := err.(*fiber.Error); ok {
+				status = e.Code
+			}
+		}
+
+		m.metrics.ObserveHTTP(c.Method(), c.Route().Path, status, time.Since(start))
+
+		return err
+	}
+}
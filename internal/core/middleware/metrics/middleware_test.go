@@ -0,0 +1,58 @@
+package metrics_test
+
+import (
+	"go-fiber-api/internal/core/middleware/metrics"
+	metrics_wrapper "go-fiber-api/internal/wrapper/metrics"
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestMetrics(t *testing.T) {
+	tests := []struct {
+		name     string
+		url      string
+		expected string
+	}{
+		{
+			name:     "when_route_has_params_should_label_with_template",
+			url:      "/users/42",
+			expected: `go_fiber_api_http_requests_total{method="GET",route="/users/:id",status="200"} 1`,
+		},
+		{
+			name:     "when_handler_returns_error_should_label_with_error_status",
+			url:      "/fail",
+			expected: `go_fiber_api_http_requests_total{method="GET",route="/fail",status="418"} 1`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := metrics_wrapper.New()
+			mw := metrics.Provide(m)
+
+			app := fiber.New()
+			app.Use(mw.RequestMetrics())
+			app.Get("/users/:id", func(c *fiber.Ctx) error {
+				return c.SendString(c.Params("id"))
+			})
+			app.Get("/fail", func(c *fiber.Ctx) error {
+				return fiber.NewError(fiber.StatusTeapot, "mock error")
+			})
+			app.Get("/metrics", m.Handler())
+
+			_, err := app.Test(httptest.NewRequest(fiber.MethodGet, tt.url, nil))
+			assert.NoError(t, err)
+
+			resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/metrics", nil))
+			assert.NoError(t, err)
+
+			body, err := io.ReadAll(resp.Body)
+			assert.NoError(t, err)
+			assert.Contains(t, string(body), tt.expected)
+		})
+	}
+}
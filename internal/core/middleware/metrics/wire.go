@@ -0,0 +1,21 @@
+//go:build wireinject
+// +build wireinject
+
+//go:generate wire
+package metrics
+
+import (
+	metrics_wrapper "go-fiber-api/internal/wrapper/metrics"
+
+	"github.com/google/wire"
+)
+
+var ProviderSet = wire.NewSet(
+	Provide,
+)
+
+func Wire(metrics *metrics_wrapper.Metrics) Middleware {
+	wire.Build(ProviderSet)
+
+	return &middlewareImpl{}
+}
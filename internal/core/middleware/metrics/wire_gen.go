@@ -0,0 +1,25 @@
+// Code generated by Wire. DO NOT EDIT.
+
+//go:generate go run -mod=mod github.com/google/wire/cmd/wire
+//go:build !wireinject
+// +build !wireinject
+
+package metrics
+
+import (
+	"github.com/google/wire"
+	"go-fiber-api/internal/wrapper/metrics"
+)
+
+// Injectors from wire.go:
+
+func Wire(metrics2 *metrics.Metrics) Middleware {
+	middleware := Provide(metrics2)
+	return middleware
+}
+
+// wire.go:
+
+var ProviderSet = wire.NewSet(
+	Provide,
+)
@@ -0,0 +1,61 @@
+package ratelimit
+
+import (
+	"go-fiber-api/internal/core/config"
+	"go-fiber-api/toolkit/apperror"
+	"sync/atomic"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/limiter"
+)
+
+type Middleware interface {
+	RateLimit() fiber.Handler
+}
+
+// ErrTooManyRequests is returned to clients over RATE_LIMIT_MAX.
+var ErrTooManyRequests = apperror.RateLimited(apperror.CodeRateLimited, "too many requests", 0)
+
+type middlewareImpl struct {
+	handler atomic.Pointer[fiber.Handler]
+}
+
+// Provide limits the requests of each client IP to RATE_LIMIT_MAX of cfg per
+// RATE_LIMIT_WINDOW, following their changes through w.
+func Provide(cfg *config.Configuration, w *config.Watcher) Middleware {
+	m := &middlewareImpl{}
+	m.set(cfg.RateLimitMax, cfg.RateLimitWindow)
+
+	w.Subscribe(func(change config.Change) {
+		m.set(change.New.RateLimitMax, change.New.RateLimitWindow)
+	}, "RATE_LIMIT_MAX", "RATE_LIMIT_WINDOW")
+
+	return m
+}
+
+// set swaps in a handler for max requests per window seconds, 0 allowing
+// them all. The counters are kept in memory by the handler, they start over
+// when the limits change.
+func (m *middlewareImpl) set(max, window int) {
+	handler := func(c *fiber.Ctx) error {
+		return c.Next()
+	}
+	if max > 0 {
+		handler = limiter.New(limiter.Config{
+			Max:        max,
+			Expiration: time.Duration(window) * time.Second,
+			// Retry-After is set by the limiter.
+			LimitReached: func(c *fiber.Ctx) error {
+				return ErrTooManyRequests
+			},
+		})
+	}
+	m.handler.Store(&handler)
+}
+
+func (m *middlewareImpl) RateLimit() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		return (*m.handler.Load())(c)
+	}
+}
@@ -0,0 +1,87 @@
+package ratelimit_test
+
+import (
+	"go-fiber-api/internal/core/config"
+	"go-fiber-api/internal/core/lifecycle"
+	"go-fiber-api/internal/core/middleware/ratelimit"
+	"go-fiber-api/internal/wrapper/logx"
+	"go-fiber-api/toolkit/errorhandler"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimit(t *testing.T) {
+	tests := []struct {
+		name           string
+		max            int // at startup
+		reloaded       int // after a reload, if not negative
+		expectedStatus []int
+	}{
+		{
+			name:           "when_disabled_should_allow_every_request",
+			reloaded:       -1,
+			expectedStatus: []int{fiber.StatusOK, fiber.StatusOK, fiber.StatusOK},
+		},
+		{
+			name:           "when_max_is_reached_should_return_429",
+			max:            2,
+			reloaded:       -1,
+			expectedStatus: []int{fiber.StatusOK, fiber.StatusOK, fiber.StatusTooManyRequests},
+		},
+		{
+			name:           "when_max_is_reloaded_should_apply_it",
+			max:            3,
+			reloaded:       1,
+			expectedStatus: []int{fiber.StatusOK, fiber.StatusTooManyRequests, fiber.StatusTooManyRequests},
+		},
+		{
+			name:           "when_disabled_by_reload_should_allow_every_request",
+			max:            1,
+			reloaded:       0,
+			expectedStatus: []int{fiber.StatusOK, fiber.StatusOK, fiber.StatusOK},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(config.ConfigFileEnv, "")
+			t.Setenv("SECRET_KEY", "secret")
+			t.Setenv("DB_DRIVER", "sqlite")
+			t.Setenv("DB_NAME", ":memory:")
+			t.Setenv("REDIS_HOST", "localhost")
+			t.Setenv("REDIS_PORT", "6379")
+			t.Setenv("RATE_LIMIT_MAX", strconv.Itoa(tt.max))
+
+			log := logx.Provide(lifecycle.New())
+			cfg, err := config.Provide(log, nil)
+			assert.NoError(t, err)
+			w, err := config.NewWatcher(cfg, log, nil)
+			assert.NoError(t, err)
+
+			app := fiber.New(fiber.Config{ErrorHandler: errorhandler.Handler()})
+			app.Use(ratelimit.Provide(cfg, w).RateLimit())
+			app.Get("/", func(c *fiber.Ctx) error {
+				return c.SendString("ok")
+			})
+
+			if tt.reloaded >= 0 {
+				t.Setenv("RATE_LIMIT_MAX", strconv.Itoa(tt.reloaded))
+				assert.NoError(t, w.Reload())
+			}
+
+			// test logic
+			for _, expected := range tt.expectedStatus {
+				resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/", nil))
+				assert.NoError(t, err)
+				assert.Equal(t, expected, resp.StatusCode)
+				if expected == fiber.StatusTooManyRequests {
+					assert.NotEmpty(t, resp.Header.Get(fiber.HeaderRetryAfter))
+				}
+			}
+		})
+	}
+}
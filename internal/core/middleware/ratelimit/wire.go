@@ -0,0 +1,21 @@
+//go:build wireinject
+// +build wireinject
+
+//go:generate wire
+package ratelimit
+
+import (
+	"go-fiber-api/internal/core/config"
+
+	"github.com/google/wire"
+)
+
+var ProviderSet = wire.NewSet(
+	Provide,
+)
+
+func Wire(cfg *config.Configuration, w *config.Watcher) (Middleware, error) {
+	wire.Build(ProviderSet)
+
+	return &middlewareImpl{}, nil
+}
@@ -0,0 +1,25 @@
+// Code generated by Wire. DO NOT EDIT.
+
+//go:generate go run -mod=mod github.com/google/wire/cmd/wire
+//go:build !wireinject
+// +build !wireinject
+
+package ratelimit
+
+import (
+	"github.com/google/wire"
+	"go-fiber-api/internal/core/config"
+)
+
+// Injectors from wire.go:
+
+func Wire(cfg *config.Configuration, w *config.Watcher) (Middleware, error) {
+	middleware := Provide(cfg, w)
+	return middleware, nil
+}
+
+// wire.go:
+
+var ProviderSet = wire.NewSet(
+	Provide,
+)
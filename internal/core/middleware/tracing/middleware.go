@@ -0,0 +1,94 @@
+package tracing
+
+import (
+	"fmt"
+	tracing_wrapper "go-fiber-api/internal/wrapper/tracing"
+
+	"github.com/gofiber/fiber/v2"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type Middleware interface {
+	RequestTracing() fiber.Handler
+}
+
+type middlewareImpl struct {
+	t *tracing_wrapper.Tracing
+}
+
+func Provide(t *tracing_wrapper.Tracing) Middleware {
+	return &middlewareImpl{
+		t: t,
+	}
+}
+
+// RequestTracing starts a server span for every request, continuing the trace
+// of an incoming traceparent header, and stores it in c.UserContext() so that
+// spans started from that context become its children. The span is named
+// after the route template once it is known. It must run before
+// logger.RequestLogger so the request logger picks up the span.
+func (m *middlewareImpl) RequestTracing() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		ctx := m.t.Propagator().Extract(c.UserContext(), headerCarrier{c: c})
+
+		ctx, span := m.t.Start(ctx, c.Method(),
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				semconv.HTTPRequestMethodKey.String(c.Method()),
+				semconv.URLPath(c.Path()),
+				semconv.ClientAddress(c.IP()),
+			),
+		)
+		defer span.End()
+
+		c.SetUserContext(ctx)
+
+		err := c.Next()
+
+		status := c.Response().StatusCode()
+		if err != nil {
+			status = fiber.StatusInternalServerError
+			if e, ok := err.(*fiber.Error); ok {
+				status = e.Code
+			}
+			span.RecordError(err)
+		}
+
+		route := c.Route().Path
+		span.SetName(fmt.Sprintf("%s %s", c.Method(), route))
+		span.SetAttributes(
+			semconv.HTTPRoute(route),
+			semconv.HTTPResponseStatusCode(status),
+		)
+		if status >= fiber.StatusInternalServerError {
+			span.SetStatus(codes.Error, fmt.Sprintf("status %d", status))
+		}
+
+		return err
+	}
+}
+
+// headerCarrier reads the trace context from the request headers.
+type headerCarrier struct {
+	c *fiber.Ctx
+}
+
+func (h headerCarrier) Get(key string) string {
+	return h.c.Get(key)
+}
+
+func (h headerCarrier) Set(key, value string) {
+	h.c.Request().Header.Set(key, value)
+}
+
+func (h headerCarrier) Keys() []string {
+	headers := h.c.GetReqHeaders()
+	keys := make([]string, 0, len(headers))
+	for key := range headers {
+		keys = append(keys, key)
+	}
+
+	return keys
+}
@@ -0,0 +1,99 @@
+package tracing_test
+
+import (
+	"go-fiber-api/internal/core/config"
+	"go-fiber-api/internal/core/middleware/tracing"
+	tracing_wrapper "go-fiber-api/internal/wrapper/tracing"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestRequestTracing(t *testing.T) {
+	const (
+		traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+		spanID  = "00f067aa0ba902b7"
+	)
+
+	tests := []struct {
+		name           string
+		url            string
+		traceparent    string
+		expectedName   string
+		expectedParent bool
+		expectedStatus codes.Code
+	}{
+		{
+			name:           "when_traceparent_is_sent_should_continue_trace",
+			url:            "/users/42",
+			traceparent:    "00-" + traceID + "-" + spanID + "-01",
+			expectedName:   "GET /users/:id",
+			expectedParent: true,
+			expectedStatus: codes.Unset,
+		},
+		{
+			name:           "when_no_traceparent_should_start_new_trace",
+			url:            "/users/42",
+			expectedName:   "GET /users/:id",
+			expectedStatus: codes.Unset,
+		},
+		{
+			name:           "when_handler_fails_should_mark_span_as_error",
+			url:            "/fail",
+			expectedName:   "GET /fail",
+			expectedStatus: codes.Error,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := tracetest.NewSpanRecorder()
+			tr, err := tracing_wrapper.New(&config.Configuration{TracingSampleRatio: 1}, sdktrace.WithSpanProcessor(recorder))
+			assert.NoError(t, err)
+
+			mw := tracing.Provide(tr)
+
+			var handlerSpan trace.SpanContext
+			app := fiber.New()
+			app.Use(mw.RequestTracing())
+			app.Get("/users/:id", func(c *fiber.Ctx) error {
+				handlerSpan = trace.SpanContextFromContext(c.UserContext())
+				return c.SendString(c.Params("id"))
+			})
+			app.Get("/fail", func(c *fiber.Ctx) error {
+				return fiber.NewError(fiber.StatusServiceUnavailable, "mock error")
+			})
+
+			req := httptest.NewRequest(fiber.MethodGet, tt.url, nil)
+			if tt.traceparent != "" {
+				req.Header.Set("traceparent", tt.traceparent)
+			}
+			_, err = app.Test(req)
+			assert.NoError(t, err)
+
+			spans := recorder.Ended()
+			assert.Len(t, spans, 1)
+
+			span := spans[0]
+			assert.Equal(t, tt.expectedName, span.Name())
+			assert.Equal(t, trace.SpanKindServer, span.SpanKind())
+			assert.Equal(t, tt.expectedStatus, span.Status().Code)
+			if handlerSpan.IsValid() {
+				assert.Equal(t, span.SpanContext().SpanID(), handlerSpan.SpanID())
+			}
+
+			if tt.expectedParent {
+				assert.Equal(t, traceID, span.SpanContext().TraceID().String())
+				assert.Equal(t, spanID, span.Parent().SpanID().String())
+			} else {
+				assert.False(t, span.Parent().IsValid())
+			}
+		})
+	}
+}
@@ -0,0 +1,21 @@
+//go:build wireinject
+// +build wireinject
+
+//go:generate wire
+package tracing
+
+import (
+	tracing_wrapper "go-fiber-api/internal/wrapper/tracing"
+
+	"github.com/google/wire"
+)
+
+var ProviderSet = wire.NewSet(
+	Provide,
+)
+
+func Wire(t *tracing_wrapper.Tracing) Middleware {
+	wire.Build(ProviderSet)
+
+	return &middlewareImpl{}
+}
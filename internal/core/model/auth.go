@@ -0,0 +1,43 @@
+package model
+
+import "time"
+
+// RegisterDTO creates a user signing in with Username and Password. The
+// password is limited to 72 bytes, the most bcrypt hashes.
+type RegisterDTO struct {
+	Username  string `json:"username" validate:"required,min=3,max=64"`
+	Password  string `json:"password" validate:"required,min=8,max=72"`
+	FirstName string `json:"firstName" validate:"max=100"`
+	LastName  string `json:"lastName" validate:"max=100"`
+}
+
+type LoginDTO struct {
+	Username string `json:"username" validate:"required"`
+	Password string `json:"password" validate:"required"`
+}
+
+// RefreshDTO holds the refresh token to rotate, or to revoke on logout.
+type RefreshDTO struct {
+	RefreshToken string `json:"refreshToken" validate:"required"`
+}
+
+// TokenDTO is a session of a user: a short-lived access token sent as
+// "Authorization: Bearer", and the refresh token exchanged for the next pair.
+type TokenDTO struct {
+	AccessToken  string `json:"accessToken"`
+	TokenType    string `json:"tokenType"`
+	ExpiresIn    int    `json:"expiresIn"` // seconds the access token is valid
+	RefreshToken string `json:"refreshToken"`
+}
+
+// RefreshToken is a refresh token issued to a user, of which only the
+// SHA-256 is stored. Every token rotated from the same login shares its
+// FamilyID, the session ID of their access tokens.
+type RefreshToken struct {
+	Base
+	UserID    uint   `gorm:"index"`
+	TokenHash string `gorm:"uniqueIndex"`
+	FamilyID  string `gorm:"index"`
+	ExpiresAt time.Time
+	RevokedAt *time.Time
+}
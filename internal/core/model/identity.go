@@ -0,0 +1,11 @@
+package model
+
+// Identity links a user to their subject at an OpenID Connect provider.
+type Identity struct {
+	Base
+
+	UserID   uint   `gorm:"index" json:"userId"`
+	Provider string `gorm:"uniqueIndex:idx_identity_subject" json:"provider"`
+	Subject  string `gorm:"uniqueIndex:idx_identity_subject" json:"subject"`
+	Email    string `json:"email"`
+}
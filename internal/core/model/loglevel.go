@@ -0,0 +1,10 @@
+package model
+
+// LogLevelDTO changes the log level of the whole application, or of a single
+// component when Component is set. Duration is a Go duration such as "15m";
+// the level reverts by itself once it elapses.
+type LogLevelDTO struct {
+	Level     string `json:"level" validate:"required"`
+	Component string `json:"component"`
+	Duration  string `json:"duration"`
+}
@@ -0,0 +1,43 @@
+package model
+
+// Permission is the right to do something, named such as "roles:manage".
+type Permission struct {
+	Base
+	Name string `gorm:"uniqueIndex" json:"name"`
+}
+
+// Role grants its permissions to the users it is assigned to.
+type Role struct {
+	Base
+	Name        string       `gorm:"uniqueIndex" json:"name"`
+	Description string       `json:"description"`
+	Permissions []Permission `gorm:"many2many:role_permissions" json:"permissions"`
+}
+
+func (r Role) ToDTO() RoleDTO {
+	permissions := make([]string, 0, len(r.Permissions))
+	for _, p := range r.Permissions {
+		permissions = append(permissions, p.Name)
+	}
+
+	return RoleDTO{
+		ID:          r.ID,
+		Name:        r.Name,
+		Description: r.Description,
+		Permissions: permissions,
+	}
+}
+
+// RoleDTO is a role with the names of its permissions, "*" granting all of
+// them.
+type RoleDTO struct {
+	ID          uint     `json:"id"`
+	Name        string   `json:"name" validate:"required,max=64"`
+	Description string   `json:"description" validate:"max=255"`
+	Permissions []string `json:"permissions" validate:"dive,required,max=128"`
+}
+
+// UserRolesDTO holds the names of the roles of a user.
+type UserRolesDTO struct {
+	Roles []string `json:"roles" validate:"dive,required"`
+}
@@ -1,6 +1,8 @@
 package model
 
 import (
+	"time"
+
 	"gorm.io/gorm"
 )
 
@@ -15,10 +17,13 @@ const (
 type User struct {
 	gorm.Model
 
-	Username  string     `json:"username"`
-	FirstName string     `json:"firstName"`
-	LastName  string     `json:"lastName"`
-	Status    UserStatus `json:"status"`
+	Username     string     `gorm:"uniqueIndex" json:"username"`
+	PasswordHash string     `json:"-"`
+	FirstName    string     `json:"firstName"`
+	LastName     string     `json:"lastName"`
+	Status       UserStatus `json:"status"`
+	LockedUntil  *time.Time `json:"lockedUntil,omitempty"` // end of a temporary lock, nil when lifted by hand only
+	Roles        []Role     `gorm:"many2many:user_roles" json:"roles,omitempty"`
 }
 
 func (u User) ToDTO() UserDTO {
@@ -32,8 +37,11 @@ func (u User) FromDTO(dto UserDTO) any {
 type UserDTO struct {
 	gorm.Model
 
-	Username  string     `json:"username"`
-	FirstName string     `json:"firstName"`
-	LastName  string     `json:"lastName"`
-	Status    UserStatus `json:"status"`
+	Username     string     `gorm:"uniqueIndex" json:"username"`
+	PasswordHash string     `json:"-"` // never rendered
+	FirstName    string     `json:"firstName"`
+	LastName     string     `json:"lastName"`
+	Status       UserStatus `json:"status"`
+	LockedUntil  *time.Time `json:"lockedUntil,omitempty"` // end of a temporary lock, nil when lifted by hand only
+	Roles        []Role     `gorm:"many2many:user_roles" json:"roles,omitempty"`
 }
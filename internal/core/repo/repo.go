@@ -25,6 +25,9 @@ type Repo[E GormModel[D], D any] interface {
 	FindByID(ctx context.Context, id any) (D, error)
 	Insert(ctx context.Context, dto *D) error
 	Update(ctx context.Context, dto *D) error
+	// UpdateColumns updates only the columns in values of the record of id,
+	// leaving the others as they are, unlike Update saving them all.
+	UpdateColumns(ctx context.Context, id any, values map[string]any) error
 	Delete(ctx context.Context, dto *D) error
 	DeleteById(ctx context.Context, id any) error
 }
@@ -140,6 +143,11 @@ func (r *repoImpl[E, D]) Update(ctx context.Context, dto *D) error {
 	return nil
 }
 
+func (r *repoImpl[E, D]) UpdateColumns(ctx context.Context, id any, values map[string]any) error {
+	entity := new(E)
+	return r.db.WithContext(ctx).Model(entity).Where("id = ?", id).Updates(values).Error
+}
+
 func (r *repoImpl[E, D]) Delete(ctx context.Context, dto *D) error {
 	var entity E
 	model := entity.FromDTO(*dto).(E)
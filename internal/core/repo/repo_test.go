@@ -124,6 +124,19 @@ func TestGormRepository_Count(t *testing.T) {
 	}
 }
 
+func TestGormRepository_UpdateColumns(t *testing.T) {
+	client, _ := getDB()
+	repo := repository.NewRepository[Product, ProductDTO](client)
+	ctx := context.Background()
+
+	assert.NoError(t, repo.UpdateColumns(ctx, 8, map[string]any{"weight": 200}))
+
+	actual, err := repo.FindByID(ctx, 8)
+	assert.NoError(t, err)
+	// Other columns are left as they are.
+	assert.Equal(t, ProductDTO{ID: 8, Name: "product1", Weight: 200, IsAvailable: true}, actual)
+}
+
 func TestGormRepository_DeleteByID(t *testing.T) {
 	client, _ := getDB()
 	repository := repository.NewRepository[Product, ProductDTO](client)
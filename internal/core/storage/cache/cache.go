@@ -0,0 +1,71 @@
+//go:generate mockgen -source=cache.go -mock_names=Cache=MockCache -destination=../../../mock/mock_cache.go -package=mock
+
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-fiber-api/internal/core/config"
+	"go-fiber-api/internal/wrapper/redis"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	BackendRedis  = "redis"
+	BackendMemory = "memory"
+
+	defaultTTL = 300 // seconds, same default as the redis wrapper
+)
+
+var (
+	// ErrMiss is returned by Get and Take when the key is not cached. Any
+	// other error, but ErrDecode, means the backend itself is unavailable.
+	ErrMiss = errors.New("cache miss")
+	// ErrDecode wraps the error of a cached value not fitting out, such as
+	// one cached in another format.
+	ErrDecode = errors.New("cache decode failed")
+)
+
+// Cache is the key/value store used for response caching, backed by Redis or
+// by process memory for runs without Redis.
+type Cache interface {
+	Set(ctx context.Context, key string, value any, ttl ...int) error
+	Get(ctx context.Context, key string, out any) error
+	Del(ctx context.Context, key string) error
+	// Take is Get deleting the key at once, so only one caller gets it.
+	Take(ctx context.Context, key string, out any) error
+	Ping(ctx context.Context) error
+}
+
+func Provide(cfg *config.Configuration, rc redis.Client) (Cache, error) {
+	var (
+		c   Cache
+		err error
+	)
+	switch cfg.CacheBackend {
+	case BackendMemory:
+		c, err = NewMemory(cfg.CacheMemorySize)
+	case BackendRedis, "":
+		c = NewRedis(rc)
+	default:
+		err = fmt.Errorf("unknown cache backend %q", cfg.CacheBackend)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	logrus.Infof("cache backend: %s", cfg.CacheBackend)
+
+	return c, nil
+}
+
+// decode is redis.Decode, its errors wrapping ErrDecode.
+func decode(data []byte, out any) error {
+	if err := redis.Decode(data, out); err != nil {
+		return fmt.Errorf("%w: %v", ErrDecode, err)
+	}
+
+	return nil
+}
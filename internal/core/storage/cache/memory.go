@@ -0,0 +1,108 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"go-fiber-api/internal/wrapper/redis"
+	"sync"
+	"time"
+
+	lru "github.com/hashicorp/golang-lru/v2"
+)
+
+type memoryEntry struct {
+	data      []byte
+	expiresAt time.Time
+}
+
+type memoryCache struct {
+	entries *lru.Cache[string, memoryEntry]
+
+	// Serializes Set with the removals of Get and Take, which would
+	// otherwise remove an entry set since they got the previous one.
+	mu sync.Mutex
+}
+
+// NewMemory returns a Cache kept in process memory, holding at most size
+// entries. Values are stored serialized, like in Redis.
+func NewMemory(size int) (Cache, error) {
+	entries, err := lru.New[string, memoryEntry](size)
+	if err != nil {
+		return nil, fmt.Errorf("create memory cache failed: %v", err)
+	}
+
+	return &memoryCache{entries: entries}, nil
+}
+
+func (m *memoryCache) Set(ctx context.Context, key string, value any, ttl ...int) error {
+	if key == "" {
+		return fmt.Errorf("key cannot be empty")
+	}
+
+	expiration := defaultTTL
+	if len(ttl) > 0 {
+		if ttl[0] <= 0 {
+			return fmt.Errorf("TTL must be greater than 0")
+		}
+
+		expiration = ttl[0]
+	}
+
+	data, err := redis.Encode(value)
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.entries.Add(key, memoryEntry{
+		data:      data,
+		expiresAt: time.Now().Add(time.Duration(expiration) * time.Second),
+	})
+
+	return nil
+}
+
+func (m *memoryCache) Get(ctx context.Context, key string, out any) error {
+	entry, ok := m.entries.Get(key)
+	if !ok {
+		return ErrMiss
+	}
+
+	if !time.Now().Before(entry.expiresAt) {
+		m.mu.Lock()
+		// Unless it was set again meanwhile.
+		if entry, ok := m.entries.Peek(key); ok && !time.Now().Before(entry.expiresAt) {
+			m.entries.Remove(key)
+		}
+		m.mu.Unlock()
+		return ErrMiss
+	}
+
+	return decode(entry.data, out)
+}
+
+func (m *memoryCache) Del(ctx context.Context, key string) error {
+	m.entries.Remove(key)
+	return nil
+}
+
+func (m *memoryCache) Take(ctx context.Context, key string, out any) error {
+	m.mu.Lock()
+	entry, ok := m.entries.Get(key)
+	if ok {
+		m.entries.Remove(key)
+	}
+	m.mu.Unlock()
+
+	if !ok || !time.Now().Before(entry.expiresAt) {
+		return ErrMiss
+	}
+
+	return decode(entry.data, out)
+}
+
+func (m *memoryCache) Ping(ctx context.Context) error {
+	return nil
+}
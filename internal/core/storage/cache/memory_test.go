@@ -0,0 +1,113 @@
+package cache_test
+
+import (
+	"context"
+	"go-fiber-api/internal/core/response"
+	"go-fiber-api/internal/core/storage/cache"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryCache_Set(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name          string
+		key           string
+		value         any
+		ttl           []int
+		wantErr       bool
+		expectedError string
+	}{
+		{
+			name:  "success",
+			key:   "test-key-success-case",
+			value: response.ResponseDTO{Message: "test-message"},
+			ttl:   []int{5},
+		},
+		{
+			name:          "empty key",
+			key:           "",
+			value:         "test-value",
+			ttl:           []int{5},
+			wantErr:       true,
+			expectedError: "key cannot be empty",
+		},
+		{
+			name:          "negative ttl",
+			key:           "test-key-negative-ttl",
+			value:         "test-value",
+			ttl:           []int{-1},
+			wantErr:       true,
+			expectedError: "TTL must be greater than 0",
+		},
+		{
+			name:          "marshal error",
+			key:           "test-key-will-fail",
+			value:         make(chan int),
+			wantErr:       true,
+			expectedError: "failed to marshal value: json: unsupported type: chan int",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := cache.NewMemory(10)
+			assert.NoError(t, err)
+
+			err = c.Set(ctx, tt.key, tt.value, tt.ttl...)
+			if tt.wantErr {
+				assert.EqualError(t, err, tt.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+
+			var val response.ResponseDTO
+			assert.NoError(t, c.Get(ctx, tt.key, &val))
+			assert.Equal(t, tt.value, val)
+		})
+	}
+}
+
+func TestMemoryCache_Get(t *testing.T) {
+	ctx := context.Background()
+	c, err := cache.NewMemory(1)
+	assert.NoError(t, err)
+
+	var val []byte
+	assert.ErrorIs(t, c.Get(ctx, "test-key-missing", &val), cache.ErrMiss)
+
+	assert.NoError(t, c.Set(ctx, "test-key-raw", []byte("raw"), 1))
+	assert.NoError(t, c.Get(ctx, "test-key-raw", &val))
+	assert.Equal(t, []byte("raw"), val)
+
+	var dto response.ResponseDTO
+	assert.ErrorIs(t, c.Get(ctx, "test-key-raw", &dto), cache.ErrDecode)
+
+	// Size is 1, so a second key evicts the first one.
+	assert.NoError(t, c.Set(ctx, "test-key-evict", []byte("raw"), 1))
+	assert.ErrorIs(t, c.Get(ctx, "test-key-raw", &val), cache.ErrMiss)
+
+	time.Sleep(1100 * time.Millisecond)
+	assert.ErrorIs(t, c.Get(ctx, "test-key-evict", &val), cache.ErrMiss)
+
+	assert.NoError(t, c.Del(ctx, "test-key-evict"))
+}
+
+func TestMemoryCache_Take(t *testing.T) {
+	ctx := context.Background()
+	c, err := cache.NewMemory(10)
+	assert.NoError(t, err)
+
+	var val []byte
+	assert.NoError(t, c.Set(ctx, "test-key-take", []byte("once"), 1))
+	assert.NoError(t, c.Take(ctx, "test-key-take", &val))
+	assert.Equal(t, []byte("once"), val)
+	assert.ErrorIs(t, c.Take(ctx, "test-key-take", &val), cache.ErrMiss)
+
+	assert.NoError(t, c.Set(ctx, "test-key-take-expired", []byte("once"), 1))
+	time.Sleep(1100 * time.Millisecond)
+	assert.ErrorIs(t, c.Take(ctx, "test-key-take-expired", &val), cache.ErrMiss)
+}
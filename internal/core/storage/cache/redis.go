@@ -0,0 +1,55 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"go-fiber-api/internal/wrapper/redis"
+)
+
+type redisCache struct {
+	rc redis.Client
+}
+
+// NewRedis returns a Cache backed by the redis wrapper, including its local
+// tier when enabled.
+func NewRedis(rc redis.Client) Cache {
+	return &redisCache{rc: rc}
+}
+
+func (r *redisCache) Set(ctx context.Context, key string, value any, ttl ...int) error {
+	return r.rc.SetContext(ctx, key, value, ttl...)
+}
+
+func (r *redisCache) Get(ctx context.Context, key string, out any) error {
+	var data []byte
+	err := r.rc.GetContext(ctx, key, &data)
+	if errors.Is(err, redis.Nil) {
+		return ErrMiss
+	}
+	if err != nil {
+		return err
+	}
+
+	return decode(data, out)
+}
+
+func (r *redisCache) Del(ctx context.Context, key string) error {
+	return r.rc.DelContext(ctx, key)
+}
+
+func (r *redisCache) Take(ctx context.Context, key string, out any) error {
+	var data []byte
+	err := r.rc.GetDel(ctx, key, &data)
+	if errors.Is(err, redis.Nil) {
+		return ErrMiss
+	}
+	if err != nil {
+		return err
+	}
+
+	return decode(data, out)
+}
+
+func (r *redisCache) Ping(ctx context.Context) error {
+	return r.rc.Ping(ctx)
+}
@@ -0,0 +1,7 @@
+package cache
+
+import "github.com/google/wire"
+
+var ProviderSet = wire.NewSet(
+	Provide,
+)
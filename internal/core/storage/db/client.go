@@ -4,28 +4,32 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
-	"log"
-	"sync"
 
 	"github.com/google/uuid"
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm/clause"
 
 	"go-fiber-api/internal/core/config"
+	"go-fiber-api/internal/core/lifecycle"
 	"go-fiber-api/internal/core/model"
+	"go-fiber-api/internal/wrapper/metrics"
+	"go-fiber-api/internal/wrapper/tracing"
 
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
 
 var (
-	dbCon    *gorm.DB
-	dbOnce   sync.Once
-	entities = []interface{}{model.User{}}
+	entities = []interface{}{model.User{}, model.RefreshToken{}, model.Role{}, model.Permission{}, model.Identity{}}
 )
 
 const (
 	DSNFormat = "host=%s user=%s password=%s dbname=%s port=%s sslmode=%s"
+
+	DriverPostgres = "postgres"
+	// DriverSQLite is meant for tests and local runs, DB_NAME is the database
+	// file or ":memory:".
+	DriverSQLite = "sqlite"
 )
 
 type Client interface {
@@ -98,33 +102,65 @@ type Client interface {
 	WithContext(ctx context.Context) *gorm.DB
 }
 
-func ProvideDB(cfg *config.Configuration) Client {
-	dbOnce.Do(func() {
-		fmt.Println(cfg.DBHost)
-		dbURI := fmt.Sprintf(DSNFormat, cfg.DBHost, cfg.DBUser, cfg.DBPass, cfg.DBName, cfg.DBPort, cfg.DBSSLMode)
-
-		pgConfig := postgres.Config{
-			DSN: dbURI,
-		}
+// ProvideDB opens a new connection pool, closed once lc stops.
+func ProvideDB(cfg *config.Configuration, m *metrics.Metrics, t *tracing.Tracing, lc *lifecycle.Lifecycle) (Client, error) {
+	dbCon, err := open(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("open db failed: %v", err)
+	}
 
-		var err error
-		dbCon, err = gorm.Open(postgres.New(pgConfig))
+	sqlDB, err := dbCon.DB()
+	if err != nil {
+		return nil, err
+	}
 
-		if err != nil {
-			log.Fatalf("e: %v", err)
-		}
+	if cfg.DB.Driver == DriverSQLite {
+		// Every connection to ":memory:" gets a database of its own, and
+		// SQLite serializes writes anyway.
+		sqlDB.SetMaxOpenConns(1)
+	}
 
-		if err := autoMigrate(dbCon); err != nil {
-			log.Fatalf("migrate schema failed: %v", err)
-		}
+	lc.Append(lifecycle.Hook{
+		Name: "db",
+		OnStop: func(context.Context) error {
+			return sqlDB.Close()
+		},
 	})
 
-	return dbCon
+	if err := instrument(dbCon, m, t, cfg.DB.Name); err != nil {
+		return nil, fmt.Errorf("instrument db failed: %v", err)
+	}
+
+	if err := autoMigrate(dbCon); err != nil {
+		return nil, fmt.Errorf("migrate schema failed: %v", err)
+	}
+
+	return dbCon, nil
+}
+
+func open(cfg *config.Configuration) (*gorm.DB, error) {
+	switch cfg.DB.Driver {
+	case DriverPostgres, "":
+		dbURI := fmt.Sprintf(DSNFormat, cfg.DB.Host, cfg.DB.User, cfg.DB.Pass, cfg.DB.Name, cfg.DB.Port, cfg.DB.SSLMode)
+		return gorm.Open(postgres.New(postgres.Config{
+			DSN: dbURI,
+		}), gormConfig())
+	case DriverSQLite:
+		return gorm.Open(sqlite.Open(cfg.DB.Name), gormConfig())
+	default:
+		return nil, fmt.Errorf("unknown db driver %q", cfg.DB.Driver)
+	}
+}
+
+// gormConfig reports unique violations as gorm.ErrDuplicatedKey, whatever the
+// driver, for apperror.From.
+func gormConfig() *gorm.Config {
+	return &gorm.Config{TranslateError: true}
 }
 
 func GetDbTestMode() (*gorm.DB, error) {
 	name := uuid.New().String()
-	dbCon, err := gorm.Open(sqlite.Open(fmt.Sprintf("file:%v?mode=memory&cache=shared", name)), &gorm.Config{})
+	dbCon, err := gorm.Open(sqlite.Open(fmt.Sprintf("file:%v?mode=memory&cache=shared", name)), gormConfig())
 
 	if err := dbCon.Migrator().DropTable(entities...); err != nil {
 		return nil, err
@@ -137,6 +173,44 @@ func GetDbTestMode() (*gorm.DB, error) {
 	return dbCon, err
 }
 
+// instrument records query durations and the connection pool stats of db in
+// m and query spans in t, for those that are set.
+func instrument(db *gorm.DB, m *metrics.Metrics, t *tracing.Tracing, name string) error {
+	if t != nil {
+		if err := db.Use(tracingPlugin{t: t}); err != nil {
+			return err
+		}
+	}
+
+	if m == nil {
+		return nil
+	}
+
+	if err := db.Use(metricsPlugin{m: m}); err != nil {
+		return err
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+	m.RegisterDBStats(sqlDB, name)
+
+	return nil
+}
+
+// CheckMigrations reports an error unless the table of every entity exists.
+func CheckMigrations(ctx context.Context, client Client) error {
+	migrator := client.WithContext(ctx).Migrator()
+	for _, entity := range entities {
+		if !migrator.HasTable(entity) {
+			return fmt.Errorf("table of %T is missing", entity)
+		}
+	}
+
+	return nil
+}
+
 func autoMigrate(db *gorm.DB) error {
 	return db.AutoMigrate(entities...)
 }
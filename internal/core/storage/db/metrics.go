@@ -0,0 +1,60 @@
+package db
+
+import (
+	"errors"
+	"go-fiber-api/internal/wrapper/metrics"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const startKey = "metrics:start"
+
+// metricsPlugin times every GORM operation and records it by operation and
+// table.
+type metricsPlugin struct {
+	m *metrics.Metrics
+}
+
+func (p metricsPlugin) Name() string {
+	return "metrics"
+}
+
+func (p metricsPlugin) Initialize(db *gorm.DB) error {
+	cb := db.Callback()
+
+	return errors.Join(
+		cb.Create().Before("gorm:create").Register("metrics:before_create", p.before),
+		cb.Create().After("gorm:create").Register("metrics:after_create", p.after("create")),
+		cb.Query().Before("gorm:query").Register("metrics:before_query", p.before),
+		cb.Query().After("gorm:query").Register("metrics:after_query", p.after("query")),
+		cb.Update().Before("gorm:update").Register("metrics:before_update", p.before),
+		cb.Update().After("gorm:update").Register("metrics:after_update", p.after("update")),
+		cb.Delete().Before("gorm:delete").Register("metrics:before_delete", p.before),
+		cb.Delete().After("gorm:delete").Register("metrics:after_delete", p.after("delete")),
+		cb.Row().Before("gorm:row").Register("metrics:before_row", p.before),
+		cb.Row().After("gorm:row").Register("metrics:after_row", p.after("row")),
+		cb.Raw().Before("gorm:raw").Register("metrics:before_raw", p.before),
+		cb.Raw().After("gorm:raw").Register("metrics:after_raw", p.after("raw")),
+	)
+}
+
+func (p metricsPlugin) before(tx *gorm.DB) {
+	tx.InstanceSet(startKey, time.Now())
+}
+
+func (p metricsPlugin) after(operation string) func(*gorm.DB) {
+	return func(tx *gorm.DB) {
+		v, ok := tx.InstanceGet(startKey)
+		if !ok {
+			return
+		}
+
+		start, ok := v.(time.Time)
+		if !ok {
+			return
+		}
+
+		p.m.ObserveQuery(operation, tx.Statement.Table, time.Since(start))
+	}
+}
@@ -3,16 +3,11 @@ package apikey
 import (
 	"go-fiber-api/internal/core/model"
 	"go-fiber-api/internal/core/response"
-	"sync"
+	"go-fiber-api/toolkit/apperror"
 
 	"github.com/gofiber/fiber/v2"
 )
 
-var (
-	h     *handlerImpl
-	hOnce sync.Once
-)
-
 type Handler interface {
 	Create(c *fiber.Ctx) error
 	FindAll(c *fiber.Ctx) error
@@ -25,28 +20,20 @@ type handlerImpl struct {
 }
 
 func ProvideHandler(s Service) Handler {
-	hOnce.Do(func() {
-		h = &handlerImpl{
-			s: s,
-		}
-	})
-
-	return h
-}
-
-func ResetHandler() {
-	hOnce = sync.Once{}
+	return &handlerImpl{
+		s: s,
+	}
 }
 
 func (c *handlerImpl) Create(ctx *fiber.Ctx) error {
 	var dto *model.APIKeyDTO
 	if err := ctx.BodyParser(&dto); err != nil {
-		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+		return apperror.Validation(apperror.CodeValidation, "invalid request body").Wrap(err)
 	}
 
-	token, err := c.s.Create(ctx.Context(), dto)
+	token, err := c.s.Create(ctx.UserContext(), dto)
 	if err != nil {
-		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+		return err
 	}
 
 	return ctx.JSON(&response.ResponseDTO{
@@ -56,9 +43,9 @@ func (c *handlerImpl) Create(ctx *fiber.Ctx) error {
 }
 
 func (c *handlerImpl) FindAll(ctx *fiber.Ctx) error {
-	res, err := c.s.FindAll(ctx.Context())
+	res, err := c.s.FindAll(ctx.UserContext())
 	if err != nil {
-		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+		return err
 	}
 
 	return ctx.JSON(&response.ResponseDTO{
@@ -70,9 +57,9 @@ func (c *handlerImpl) FindAll(ctx *fiber.Ctx) error {
 func (c *handlerImpl) FindOne(ctx *fiber.Ctx) error {
 	id := ctx.Params("id")
 
-	data, err := c.s.FindByID(ctx.Context(), id)
+	data, err := c.s.FindByID(ctx.UserContext(), id)
 	if err != nil {
-		return fiber.NewError(fiber.StatusNotFound, err.Error())
+		return err
 	}
 	return ctx.JSON(&response.ResponseDTO{
 		Message: "success",
@@ -83,9 +70,9 @@ func (c *handlerImpl) FindOne(ctx *fiber.Ctx) error {
 func (c *handlerImpl) DeleteByID(ctx *fiber.Ctx) error {
 	id := ctx.Params("id")
 
-	err := c.s.DeleteByID(ctx.Context(), id)
+	err := c.s.DeleteByID(ctx.UserContext(), id)
 	if err != nil {
-		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+		return err
 	}
 	return ctx.JSON(&response.ResponseDTO{
 		Message: "success",
@@ -5,6 +5,7 @@ import (
 	"go-fiber-api/internal/core/model"
 	"go-fiber-api/internal/feature/apikey"
 	"go-fiber-api/internal/mock"
+	"go-fiber-api/toolkit/errorhandler"
 	"io"
 	"net/http"
 	"net/http/httptest"
@@ -14,6 +15,7 @@ import (
 	"github.com/gofiber/fiber/v2"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
 )
 
 func TestApiKey_Handler_Create(t *testing.T) {
@@ -42,7 +44,7 @@ func TestApiKey_Handler_Create(t *testing.T) {
 			},
 			expectedStatus: fiber.StatusBadRequest,
 			expectedError:  true,
-			expectedBody:   `invalid character '}' looking for beginning of value`,
+			expectedBody:   `{"code":"VALIDATION_FAILED","message":"invalid request body","ok":false}`,
 		},
 		{
 			name: "when_create_fails_should_return_500",
@@ -56,7 +58,7 @@ func TestApiKey_Handler_Create(t *testing.T) {
 			},
 			expectedError:  true,
 			expectedStatus: fiber.StatusInternalServerError,
-			expectedBody:   `mock error`,
+			expectedBody:   `{"code":"INTERNAL","message":"Internal Server Error","ok":false}`,
 		},
 		{
 			name: "when_successful_should_return_token",
@@ -79,9 +81,8 @@ func TestApiKey_Handler_Create(t *testing.T) {
 			defer ctrl.Finish()
 
 			h := apikey.ProvideHandler(test.dependency.s(ctrl))
-			defer apikey.ResetHandler()
 
-			app := fiber.New()
+			app := fiber.New(fiber.Config{ErrorHandler: errorhandler.Handler()})
 			app.Post("/apikey", h.Create)
 
 			req := httptest.NewRequest(http.MethodPost, "/apikey", strings.NewReader(test.body))
@@ -131,7 +132,7 @@ func TestApiKey_Handler_GetAll(t *testing.T) {
 			},
 			expectedErr:    true,
 			expectedStatus: fiber.StatusInternalServerError,
-			expectedBody:   `mock error`,
+			expectedBody:   `{"code":"INTERNAL","message":"Internal Server Error","ok":false}`,
 		},
 		{
 			name: "when_successful_should_return_data",
@@ -153,9 +154,8 @@ func TestApiKey_Handler_GetAll(t *testing.T) {
 			defer ctrl.Finish()
 
 			h := apikey.ProvideHandler(test.dependency.s(ctrl))
-			defer apikey.ResetHandler()
 
-			app := fiber.New()
+			app := fiber.New(fiber.Config{ErrorHandler: errorhandler.Handler()})
 			app.Get("/apikeys", h.FindAll)
 
 			req := httptest.NewRequest(http.MethodGet, "/apikeys", nil)
@@ -194,18 +194,32 @@ func TestApiKey_Handler_GetItem(t *testing.T) {
 		expectedBody   string
 	}{
 		{
-			name:      "when_get_item_fails_should_return_404",
+			name:      "when_item_is_not_found_should_return_404",
 			pathParam: mockToken,
 			dependency: dependency{
 				s: func(ctrl *gomock.Controller) apikey.Service {
 					m := mock.NewMockAPIKeyService(ctrl)
-					m.EXPECT().FindByID(gomock.Any(), gomock.Any()).Return(model.APIKeyDTO{}, errors.New("mock error"))
+					m.EXPECT().FindByID(gomock.Any(), gomock.Any()).Return(model.APIKeyDTO{}, gorm.ErrRecordNotFound)
 					return m
 				},
 			},
 			expectedErr:    true,
 			expectedStatus: fiber.StatusNotFound,
-			expectedBody:   `mock error`,
+			expectedBody:   `{"code":"NOT_FOUND","message":"not found","ok":false}`,
+		},
+		{
+			name:      "when_get_item_fails_should_return_500",
+			pathParam: mockToken,
+			dependency: dependency{
+				s: func(ctrl *gomock.Controller) apikey.Service {
+					m := mock.NewMockAPIKeyService(ctrl)
+					m.EXPECT().FindByID(gomock.Any(), gomock.Any()).Return(model.APIKeyDTO{}, errors.New("mock error"))
+					return m
+				},
+			},
+			expectedErr:    true,
+			expectedStatus: fiber.StatusInternalServerError,
+			expectedBody:   `{"code":"INTERNAL","message":"Internal Server Error","ok":false}`,
 		},
 		{
 			name:      "when_successful_should_return_data",
@@ -228,9 +242,8 @@ func TestApiKey_Handler_GetItem(t *testing.T) {
 			defer ctrl.Finish()
 
 			h := apikey.ProvideHandler(test.dependency.s(ctrl))
-			defer apikey.ResetHandler()
 
-			app := fiber.New()
+			app := fiber.New(fiber.Config{ErrorHandler: errorhandler.Handler()})
 			app.Get("/apikey/:token", h.FindOne)
 
 			req := httptest.NewRequest(http.MethodGet, "/apikey/"+test.pathParam, nil)
@@ -279,7 +292,7 @@ func TestApiKey_Handler_DeleteItem(t *testing.T) {
 			},
 			expectedErr:    true,
 			expectedStatus: fiber.StatusInternalServerError,
-			expectedBody:   `mock error`,
+			expectedBody:   `{"code":"INTERNAL","message":"Internal Server Error","ok":false}`,
 		},
 		{
 			name:      "when_successful_should_return_success",
@@ -302,9 +315,8 @@ func TestApiKey_Handler_DeleteItem(t *testing.T) {
 			defer ctrl.Finish()
 
 			h := apikey.ProvideHandler(test.dependency.s(ctrl))
-			defer apikey.ResetHandler()
 
-			app := fiber.New()
+			app := fiber.New(fiber.Config{ErrorHandler: errorhandler.Handler()})
 			app.Delete("/apikey/:token", h.DeleteByID)
 
 			req := httptest.NewRequest(http.MethodDelete, "/apikey/"+test.pathParam, nil)
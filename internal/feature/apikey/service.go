@@ -3,20 +3,14 @@ package apikey
 
 import (
 	"context"
-	"go-fiber-api/internal/core/config"
 	"go-fiber-api/internal/core/model"
 	"go-fiber-api/internal/core/repo"
-	"sync"
+	"go-fiber-api/internal/wrapper/jwtx"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 )
 
-var (
-	svc     *serviceImpl
-	svcOnce sync.Once
-)
-
 type Service interface {
 	Create(ctx context.Context, dto *model.APIKeyDTO) (string, error)
 	FindAll(ctx context.Context) ([]model.APIKeyDTO, error)
@@ -25,20 +19,12 @@ type Service interface {
 }
 
 type serviceImpl struct {
-	cfg  *config.Configuration
-	repo repo.Repo[model.APIKey, model.APIKeyDTO]
-}
-
-func ProvideService(cfg *config.Configuration, repo repo.Repo[model.APIKey, model.APIKeyDTO]) Service {
-	svcOnce.Do(func() {
-		svc = &serviceImpl{cfg: cfg, repo: repo}
-	})
-
-	return svc
+	keyring *jwtx.Keyring
+	repo    repo.Repo[model.APIKey, model.APIKeyDTO]
 }
 
-func ResetService() {
-	svcOnce = sync.Once{}
+func ProvideService(keyring *jwtx.Keyring, repo repo.Repo[model.APIKey, model.APIKeyDTO]) Service {
+	return &serviceImpl{keyring: keyring, repo: repo}
 }
 
 func (s *serviceImpl) generateToken(name string, duration model.Duration) (string, error) {
@@ -65,9 +51,7 @@ func (s *serviceImpl) generateToken(name string, duration model.Duration) (strin
 		claims["exp"] = now.Unix()
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-
-	return token.SignedString([]byte(s.cfg.SecretKey))
+	return s.keyring.Sign(claims)
 }
 
 func (s *serviceImpl) Create(ctx context.Context, dto *model.APIKeyDTO) (string, error) {
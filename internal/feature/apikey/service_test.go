@@ -8,6 +8,7 @@ import (
 	"go-fiber-api/internal/core/repo"
 	"go-fiber-api/internal/feature/apikey"
 	"go-fiber-api/internal/mock"
+	"go-fiber-api/internal/wrapper/jwtx"
 	"testing"
 	"time"
 
@@ -25,6 +26,7 @@ func Test_Apikey_serviceImpl_Create(t *testing.T) {
 
 	cfg := &config.Configuration{
 		SecretKey: "TEST_SECRET_KEY",
+		JWT:       config.JWTConfig{LegacyEnabled: true},
 	}
 
 	tests := []struct {
@@ -136,8 +138,9 @@ func Test_Apikey_serviceImpl_Create(t *testing.T) {
 			ctrl := gomock.NewController(t)
 			defer ctrl.Finish()
 
-			s := apikey.ProvideService(test.cfg, test.dependency.repo(ctrl))
-			defer apikey.ResetService()
+			keyring, err := jwtx.Provide(test.cfg)
+			assert.NoError(t, err)
+			s := apikey.ProvideService(keyring, test.dependency.repo(ctrl))
 
 			ctx := context.TODO()
 			tokenStr, err := s.Create(ctx, test.dto)
@@ -237,8 +240,7 @@ func Test_Apikey_serviceImpl_Query(t *testing.T) {
 			ctrl := gomock.NewController(t)
 			defer ctrl.Finish()
 
-			s := apikey.ProvideService(&config.Configuration{}, test.dependency.repo(ctrl))
-			defer apikey.ResetService()
+			s := apikey.ProvideService(nil, test.dependency.repo(ctrl))
 
 			actual, err := s.FindAll(ctx)
 			if test.expectedErr && assert.Error(t, err) {
@@ -307,8 +309,7 @@ func Test_Apikey_serviceImpl_GetItem(t *testing.T) {
 			ctrl := gomock.NewController(t)
 			defer ctrl.Finish()
 
-			s := apikey.ProvideService(&config.Configuration{}, test.dependency.repo(ctrl))
-			defer apikey.ResetService()
+			s := apikey.ProvideService(nil, test.dependency.repo(ctrl))
 
 			actual, err := s.FindByID(ctx, test.pk)
 			if test.expectedErr && assert.Error(t, err) {
@@ -376,8 +377,7 @@ func Test_Apikey_serviceImpl_DeleteItem(t *testing.T) {
 			ctrl := gomock.NewController(t)
 			defer ctrl.Finish()
 
-			s := apikey.ProvideService(&config.Configuration{}, test.dependency.repo(ctrl))
-			defer apikey.ResetService()
+			s := apikey.ProvideService(nil, test.dependency.repo(ctrl))
 
 			err := s.DeleteByID(ctx, test.pk)
 			if test.expectedErr && assert.Error(t, err) {
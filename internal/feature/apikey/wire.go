@@ -5,8 +5,8 @@
 package apikey
 
 import (
-	"go-fiber-api/internal/core/config"
 	"go-fiber-api/internal/core/storage/db"
+	"go-fiber-api/internal/wrapper/jwtx"
 
 	"github.com/google/wire"
 )
@@ -19,7 +19,7 @@ var ProviderSet = wire.NewSet(
 	ProvideHandler,
 )
 
-func Wire(cfg *config.Configuration, client db.Client) (Handler, error) {
+func Wire(keyring *jwtx.Keyring, client db.Client) (Handler, error) {
 	wire.Build(ProviderSet)
 
 	return &handlerImpl{}, nil
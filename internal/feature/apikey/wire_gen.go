@@ -8,15 +8,15 @@ package apikey
 
 import (
 	"github.com/google/wire"
-	"go-fiber-api/internal/core/config"
 	"go-fiber-api/internal/core/storage/db"
+	"go-fiber-api/internal/wrapper/jwtx"
 )
 
 // Injectors from wire.go:
 
-func Wire(cfg *config.Configuration, client db.Client) (Handler, error) {
+func Wire(keyring *jwtx.Keyring, client db.Client) (Handler, error) {
 	repo := ProvideRepository(client)
-	service := ProvideService(cfg, repo)
+	service := ProvideService(keyring, repo)
 	handler := ProvideHandler(service)
 	return handler, nil
 }
@@ -0,0 +1,105 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"go-fiber-api/internal/core/lifecycle"
+	"go-fiber-api/internal/core/lock"
+	"go-fiber-api/internal/wrapper/logx"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	cleanupInterval = time.Hour
+	cleanupLockKey  = "auth:refresh-token-cleanup"
+	cleanupLockTTL  = time.Minute
+)
+
+// Cleanup deletes the expired refresh tokens. Every replica runs it, the lock
+// letting only one of them delete at a time.
+type Cleanup struct {
+	tokens Repository
+	locker lock.Locker
+	entry  *logrus.Entry
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+func NewCleanup(tokens Repository, locker lock.Locker, log *logx.LogX) *Cleanup {
+	return &Cleanup{
+		tokens: tokens,
+		locker: locker,
+		entry: log.WithFields(logrus.Fields{
+			"component": "auth",
+			"module":    "cleanup",
+		}),
+	}
+}
+
+// ProvideCleanup returns a Cleanup running every hour from the moment lc
+// starts.
+func ProvideCleanup(tokens Repository, locker lock.Locker, log *logx.LogX, lc *lifecycle.Lifecycle) *Cleanup {
+	c := NewCleanup(tokens, locker, log)
+
+	lc.Append(lifecycle.Hook{
+		Name: "refresh token cleanup",
+		OnStart: func(context.Context) error {
+			c.start()
+			return nil
+		},
+		OnStop: func(context.Context) error {
+			c.cancel()
+			<-c.done
+			return nil
+		},
+	})
+
+	return c
+}
+
+func (c *Cleanup) start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	c.cancel, c.done = cancel, make(chan struct{})
+
+	go func() {
+		defer close(c.done)
+
+		ticker := time.NewTicker(cleanupInterval)
+		defer ticker.Stop()
+		for {
+			if err := c.Run(ctx); err != nil && ctx.Err() == nil {
+				c.entry.Errorf("delete expired refresh tokens failed: %v", err)
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Run deletes the refresh tokens expired by now, unless another replica is
+// already doing so.
+func (c *Cleanup) Run(ctx context.Context) error {
+	err := lock.WithLock(ctx, c.locker, cleanupLockKey, cleanupLockTTL, func(ctx context.Context) error {
+		n, err := c.tokens.DeleteExpired(ctx, time.Now())
+		if err != nil {
+			return err
+		}
+		if n > 0 {
+			c.entry.Infof("deleted %d expired refresh tokens", n)
+		}
+
+		return nil
+	})
+	if errors.Is(err, lock.ErrNotAcquired) {
+		return nil
+	}
+
+	return err
+}
@@ -0,0 +1,85 @@
+package auth_test
+
+import (
+	"context"
+	"go-fiber-api/internal/core/config"
+	"go-fiber-api/internal/core/lifecycle"
+	"go-fiber-api/internal/core/lock"
+	"go-fiber-api/internal/core/model"
+	"go-fiber-api/internal/core/storage/db"
+	"go-fiber-api/internal/feature/auth"
+	"go-fiber-api/internal/wrapper/logx"
+	"go-fiber-api/internal/wrapper/redis"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCleanup_Run(t *testing.T) {
+	cfg := &config.Configuration{
+		Redis: config.RedisConfig{
+			Host: "localhost",
+			Port: "6379",
+			DB:   10,
+		},
+	}
+	rc, err := redis.ProvideClient(cfg, nil, nil, lifecycle.New())
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	t.Cleanup(func() { _ = rc.Close() })
+	locker := lock.Provide(rc)
+
+	tests := []struct {
+		name      string
+		lockHeld  bool
+		remaining []string
+	}{
+		{
+			name:      "when_lock_is_free_should_delete_expired_tokens",
+			remaining: []string{"live"},
+		},
+		{
+			name:      "when_lock_is_held_by_another_replica_should_delete_nothing",
+			lockHeld:  true,
+			remaining: []string{"expired", "live"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, err := db.GetDbTestMode()
+			if !assert.NoError(t, err) {
+				t.FailNow()
+			}
+			tokens := auth.ProvideRepository(client)
+
+			ctx := context.Background()
+			family := uuid.NewString()
+			for hash, expiresAt := range map[string]time.Time{
+				"expired": time.Now().Add(-time.Minute),
+				"live":    time.Now().Add(time.Hour),
+			} {
+				assert.NoError(t, tokens.Insert(ctx, &model.RefreshToken{UserID: 1, TokenHash: hash, FamilyID: family, ExpiresAt: expiresAt}))
+			}
+
+			if tt.lockHeld {
+				lease, err := locker.Acquire(ctx, "auth:refresh-token-cleanup", time.Minute)
+				if !assert.NoError(t, err) {
+					t.FailNow()
+				}
+				defer func() { _ = lease.Release(ctx) }()
+			}
+
+			// test logic
+			err = auth.NewCleanup(tokens, locker, logx.Provide(lifecycle.New())).Run(ctx)
+
+			assert.NoError(t, err)
+			var remaining []string
+			assert.NoError(t, client.Unscoped().Model(&model.RefreshToken{}).Order("token_hash").Pluck("token_hash", &remaining).Error)
+			assert.Equal(t, tt.remaining, remaining)
+		})
+	}
+}
@@ -0,0 +1,109 @@
+package auth
+
+import (
+	"errors"
+	"go-fiber-api/internal/core/guard"
+	"go-fiber-api/internal/core/model"
+	"go-fiber-api/internal/core/response"
+	"go-fiber-api/toolkit/apperror"
+	"go-fiber-api/toolkit/validate"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type Handler interface {
+	Register(c *fiber.Ctx) error
+	Login(c *fiber.Ctx) error
+	Refresh(c *fiber.Ctx) error
+	Logout(c *fiber.Ctx) error
+}
+
+type handlerImpl struct {
+	s Service
+}
+
+func ProvideHandler(s Service) Handler {
+	return &handlerImpl{
+		s: s,
+	}
+}
+
+func (h *handlerImpl) Register(ctx *fiber.Ctx) error {
+	var dto model.RegisterDTO
+	if err := parse(ctx, &dto); err != nil {
+		return err
+	}
+
+	user, err := h.s.Register(ctx.UserContext(), &dto)
+	if err != nil {
+		return err
+	}
+
+	return ctx.Status(fiber.StatusCreated).JSON(&response.ResponseDTO{
+		Message: "success",
+		Data:    user,
+	})
+}
+
+func (h *handlerImpl) Login(ctx *fiber.Ctx) error {
+	var dto model.LoginDTO
+	if err := parse(ctx, &dto); err != nil {
+		return err
+	}
+
+	token, err := h.s.Login(ctx.UserContext(), &dto, ctx.IP())
+	var waitErr *guard.WaitError
+	if errors.As(err, &waitErr) {
+		return apperror.RateLimited("TOO_MANY_ATTEMPTS", guard.ErrTooManyAttempts.Error(), waitErr.RetryAfter)
+	}
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(&response.ResponseDTO{
+		Message: "success",
+		Data:    token,
+	})
+}
+
+func (h *handlerImpl) Refresh(ctx *fiber.Ctx) error {
+	var dto model.RefreshDTO
+	if err := parse(ctx, &dto); err != nil {
+		return err
+	}
+
+	token, err := h.s.Refresh(ctx.UserContext(), dto.RefreshToken)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(&response.ResponseDTO{
+		Message: "success",
+		Data:    token,
+	})
+}
+
+func (h *handlerImpl) Logout(ctx *fiber.Ctx) error {
+	var dto model.RefreshDTO
+	if err := parse(ctx, &dto); err != nil {
+		return err
+	}
+
+	if err := h.s.Logout(ctx.UserContext(), dto.RefreshToken); err != nil {
+		return err
+	}
+
+	return ctx.JSON(&response.ResponseDTO{
+		Message: "success",
+	})
+}
+
+// parse reads the body into dto, returning an apperror.Validation error when
+// it is invalid.
+func parse(ctx *fiber.Ctx, dto any) error {
+	if err := ctx.BodyParser(dto); err != nil {
+		return apperror.Validation(apperror.CodeValidation, "invalid request body").Wrap(err)
+	}
+
+	return validate.Struct(dto)
+}
@@ -0,0 +1,227 @@
+package auth_test
+
+import (
+	"errors"
+	"go-fiber-api/internal/core/guard"
+	"go-fiber-api/internal/core/model"
+	"go-fiber-api/internal/feature/auth"
+	"go-fiber-api/internal/mock"
+	"go-fiber-api/toolkit/errorhandler"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestAuth_Handler(t *testing.T) {
+	type dependency struct {
+		s func(ctrl *gomock.Controller) auth.Service
+	}
+
+	none := func(ctrl *gomock.Controller) auth.Service {
+		return mock.NewMockAuthService(ctrl)
+	}
+	mockToken := model.TokenDTO{AccessToken: "access", TokenType: "Bearer", ExpiresIn: 900, RefreshToken: "refresh"}
+
+	tests := []struct {
+		name           string
+		path           string
+		body           string
+		dependency     dependency
+		expectedStatus int
+		expectedBody   string
+		expectedRetry  string
+	}{
+		{
+			name: "when_registered_should_return_201_without_password_hash",
+			path: "/auth/register",
+			body: `{"username": "alice", "password": "password"}`,
+			dependency: dependency{
+				s: func(ctrl *gomock.Controller) auth.Service {
+					m := mock.NewMockAuthService(ctrl)
+					m.EXPECT().Register(gomock.Any(), &model.RegisterDTO{Username: "alice", Password: "password"}).
+						Return(model.UserDTO{Username: "alice", PasswordHash: "hash", Status: model.UserStatusNormal}, nil)
+					return m
+				},
+			},
+			expectedStatus: fiber.StatusCreated,
+			expectedBody:   `{"message":"success","data":{"ID":0,"CreatedAt":"0001-01-01T00:00:00Z","UpdatedAt":"0001-01-01T00:00:00Z","DeletedAt":null,"username":"alice","firstName":"","lastName":"","status":"NORMAL"}}`,
+		},
+		{
+			name:           "when_password_is_too_short_should_return_400",
+			path:           "/auth/register",
+			body:           `{"username": "alice", "password": "short"}`,
+			dependency:     dependency{s: none},
+			expectedStatus: fiber.StatusBadRequest,
+			expectedBody:   `{"message":"invalid request","code":"VALIDATION_FAILED","ok":false,"data":[{"failedFields":"RegisterDTO.Password","tag":"min","value":"8"}]}`,
+		},
+		{
+			name: "when_username_is_taken_should_return_409",
+			path: "/auth/register",
+			body: `{"username": "alice", "password": "password"}`,
+			dependency: dependency{
+				s: func(ctrl *gomock.Controller) auth.Service {
+					m := mock.NewMockAuthService(ctrl)
+					m.EXPECT().Register(gomock.Any(), gomock.Any()).Return(model.UserDTO{}, auth.ErrUsernameTaken)
+					return m
+				},
+			},
+			expectedStatus: fiber.StatusConflict,
+			expectedBody:   `{"code":"USERNAME_TAKEN","message":"username is already taken","ok":false}`,
+		},
+		{
+			name: "when_logged_in_should_return_tokens",
+			path: "/auth/login",
+			body: `{"username": "alice", "password": "password"}`,
+			dependency: dependency{
+				s: func(ctrl *gomock.Controller) auth.Service {
+					m := mock.NewMockAuthService(ctrl)
+					m.EXPECT().Login(gomock.Any(), &model.LoginDTO{Username: "alice", Password: "password"}, "0.0.0.0").Return(mockToken, nil)
+					return m
+				},
+			},
+			expectedStatus: fiber.StatusOK,
+			expectedBody:   `{"message":"success","data":{"accessToken":"access","tokenType":"Bearer","expiresIn":900,"refreshToken":"refresh"}}`,
+		},
+		{
+			name: "when_credentials_are_invalid_should_return_401",
+			path: "/auth/login",
+			body: `{"username": "alice", "password": "wrong"}`,
+			dependency: dependency{
+				s: func(ctrl *gomock.Controller) auth.Service {
+					m := mock.NewMockAuthService(ctrl)
+					m.EXPECT().Login(gomock.Any(), gomock.Any(), gomock.Any()).Return(model.TokenDTO{}, auth.ErrInvalidCredentials)
+					return m
+				},
+			},
+			expectedStatus: fiber.StatusUnauthorized,
+			expectedBody:   `{"code":"INVALID_CREDENTIALS","message":"invalid username or password","ok":false}`,
+		},
+		{
+			name: "when_user_is_locked_should_return_403",
+			path: "/auth/login",
+			body: `{"username": "alice", "password": "password"}`,
+			dependency: dependency{
+				s: func(ctrl *gomock.Controller) auth.Service {
+					m := mock.NewMockAuthService(ctrl)
+					m.EXPECT().Login(gomock.Any(), gomock.Any(), gomock.Any()).Return(model.TokenDTO{}, auth.ErrUserLocked)
+					return m
+				},
+			},
+			expectedStatus: fiber.StatusForbidden,
+			expectedBody:   `{"code":"USER_LOCKED","message":"user is locked","ok":false}`,
+		},
+		{
+			name: "when_attempts_are_delayed_should_return_429_with_retry_after",
+			path: "/auth/login",
+			body: `{"username": "alice", "password": "password"}`,
+			dependency: dependency{
+				s: func(ctrl *gomock.Controller) auth.Service {
+					m := mock.NewMockAuthService(ctrl)
+					m.EXPECT().Login(gomock.Any(), gomock.Any(), gomock.Any()).Return(model.TokenDTO{}, &guard.WaitError{RetryAfter: 1500 * time.Millisecond})
+					return m
+				},
+			},
+			expectedStatus: fiber.StatusTooManyRequests,
+			expectedBody:   `{"code":"TOO_MANY_ATTEMPTS","message":"too many failed attempts","ok":false}`,
+			expectedRetry:  "2",
+		},
+		{
+			name: "when_refreshed_should_return_tokens",
+			path: "/auth/refresh",
+			body: `{"refreshToken": "refresh"}`,
+			dependency: dependency{
+				s: func(ctrl *gomock.Controller) auth.Service {
+					m := mock.NewMockAuthService(ctrl)
+					m.EXPECT().Refresh(gomock.Any(), "refresh").Return(mockToken, nil)
+					return m
+				},
+			},
+			expectedStatus: fiber.StatusOK,
+			expectedBody:   `{"message":"success","data":{"accessToken":"access","tokenType":"Bearer","expiresIn":900,"refreshToken":"refresh"}}`,
+		},
+		{
+			name: "when_refresh_token_is_invalid_should_return_401",
+			path: "/auth/refresh",
+			body: `{"refreshToken": "reused"}`,
+			dependency: dependency{
+				s: func(ctrl *gomock.Controller) auth.Service {
+					m := mock.NewMockAuthService(ctrl)
+					m.EXPECT().Refresh(gomock.Any(), "reused").Return(model.TokenDTO{}, auth.ErrInvalidToken)
+					return m
+				},
+			},
+			expectedStatus: fiber.StatusUnauthorized,
+			expectedBody:   `{"code":"INVALID_TOKEN","message":"invalid or expired token","ok":false}`,
+		},
+		{
+			name:           "when_refresh_token_is_missing_should_return_400",
+			path:           "/auth/logout",
+			body:           `{}`,
+			dependency:     dependency{s: none},
+			expectedStatus: fiber.StatusBadRequest,
+			expectedBody:   `{"message":"invalid request","code":"VALIDATION_FAILED","ok":false,"data":[{"failedFields":"RefreshDTO.RefreshToken","tag":"required","value":""}]}`,
+		},
+		{
+			name: "when_logout_fails_should_return_500",
+			path: "/auth/logout",
+			body: `{"refreshToken": "refresh"}`,
+			dependency: dependency{
+				s: func(ctrl *gomock.Controller) auth.Service {
+					m := mock.NewMockAuthService(ctrl)
+					m.EXPECT().Logout(gomock.Any(), "refresh").Return(errors.New("mock error"))
+					return m
+				},
+			},
+			expectedStatus: fiber.StatusInternalServerError,
+			expectedBody:   `{"code":"INTERNAL","message":"Internal Server Error","ok":false}`,
+		},
+		{
+			name: "when_logged_out_should_return_success",
+			path: "/auth/logout",
+			body: `{"refreshToken": "refresh"}`,
+			dependency: dependency{
+				s: func(ctrl *gomock.Controller) auth.Service {
+					m := mock.NewMockAuthService(ctrl)
+					m.EXPECT().Logout(gomock.Any(), "refresh").Return(nil)
+					return m
+				},
+			},
+			expectedStatus: fiber.StatusOK,
+			expectedBody:   `{"message":"success","data":null}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			h := auth.ProvideHandler(test.dependency.s(ctrl))
+
+			app := fiber.New(fiber.Config{ErrorHandler: errorhandler.Handler()})
+			app.Post("/auth/register", h.Register)
+			app.Post("/auth/login", h.Login)
+			app.Post("/auth/refresh", h.Refresh)
+			app.Post("/auth/logout", h.Logout)
+
+			req := httptest.NewRequest(http.MethodPost, test.path, strings.NewReader(test.body))
+			req.Header.Add("Content-Type", "application/json")
+
+			// test logic
+			resp, err := app.Test(req)
+			assert.NoError(t, err)
+			bodyBytes, _ := io.ReadAll(resp.Body)
+
+			assert.Equal(t, test.expectedStatus, resp.StatusCode)
+			assert.Equal(t, test.expectedRetry, resp.Header.Get(fiber.HeaderRetryAfter))
+			assert.JSONEq(t, test.expectedBody, string(bodyBytes))
+		})
+	}
+}
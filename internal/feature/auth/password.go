@@ -0,0 +1,106 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Algorithms of AUTH_PASSWORD_HASH.
+const (
+	HashArgon2id = "argon2id"
+	HashBcrypt   = "bcrypt"
+)
+
+// Parameters of new argon2id hashes, the ones of a hash are read from it.
+const (
+	argon2Time    = 1
+	argon2Memory  = 64 * 1024 // KiB
+	argon2Threads = 4
+	argon2KeyLen  = 32
+	argon2SaltLen = 16
+)
+
+var errUnknownHash = errors.New("unknown password hash")
+
+// HashPassword hashes password with algorithm, an argon2id hash being
+// encoded in the PHC string format.
+func HashPassword(algorithm, password string) (string, error) {
+	switch algorithm {
+	case HashArgon2id:
+		salt := make([]byte, argon2SaltLen)
+		if _, err := rand.Read(salt); err != nil {
+			return "", err
+		}
+		key := argon2.IDKey([]byte(password), salt, argon2Time, argon2Memory, argon2Threads, argon2KeyLen)
+
+		return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+			argon2.Version, argon2Memory, argon2Time, argon2Threads,
+			base64.RawStdEncoding.EncodeToString(salt),
+			base64.RawStdEncoding.EncodeToString(key),
+		), nil
+	case HashBcrypt:
+		hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+		return string(hash), err
+	default:
+		return "", fmt.Errorf("unsupported password hash %q", algorithm)
+	}
+}
+
+// CheckPassword reports whether password matches hash, whichever algorithm
+// made it.
+func CheckPassword(hash, password string) (bool, error) {
+	switch hashAlgorithm(hash) {
+	case HashArgon2id:
+		var (
+			version, memory, iterations int
+			threads                     uint8
+		)
+		parts := strings.Split(hash, "$")
+		if len(parts) != 6 {
+			return false, errUnknownHash
+		}
+		if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+			return false, errUnknownHash
+		}
+		if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &memory, &iterations, &threads); err != nil {
+			return false, errUnknownHash
+		}
+		salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+		if err != nil {
+			return false, errUnknownHash
+		}
+		key, err := base64.RawStdEncoding.DecodeString(parts[5])
+		if err != nil {
+			return false, errUnknownHash
+		}
+
+		other := argon2.IDKey([]byte(password), salt, uint32(iterations), uint32(memory), threads, uint32(len(key)))
+		return subtle.ConstantTimeCompare(key, other) == 1, nil
+	case HashBcrypt:
+		err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
+		if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+			return false, nil
+		}
+		return err == nil, err
+	default:
+		return false, errUnknownHash
+	}
+}
+
+func hashAlgorithm(hash string) string {
+	switch {
+	case strings.HasPrefix(hash, "$argon2id$"):
+		return HashArgon2id
+	case strings.HasPrefix(hash, "$2a$"), strings.HasPrefix(hash, "$2b$"), strings.HasPrefix(hash, "$2y$"):
+		return HashBcrypt
+	default:
+		return ""
+	}
+}
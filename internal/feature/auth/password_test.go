@@ -0,0 +1,76 @@
+package auth_test
+
+import (
+	"go-fiber-api/internal/feature/auth"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHashPassword(t *testing.T) {
+	tests := []struct {
+		name           string
+		algorithm      string
+		expectedPrefix string
+		expectedError  string
+	}{
+		{
+			name:           "when_algorithm_is_argon2id_should_get_phc_string",
+			algorithm:      auth.HashArgon2id,
+			expectedPrefix: "$argon2id$v=19$m=65536,t=1,p=4$",
+		},
+		{
+			name:           "when_algorithm_is_bcrypt_should_get_bcrypt_hash",
+			algorithm:      auth.HashBcrypt,
+			expectedPrefix: "$2a$10$",
+		},
+		{
+			name:          "when_algorithm_is_unknown_should_get_error",
+			algorithm:     "md5",
+			expectedError: `unsupported password hash "md5"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// test logic
+			hash, err := auth.HashPassword(tt.algorithm, "password")
+			if tt.expectedError != "" {
+				assert.EqualError(t, err, tt.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.True(t, strings.HasPrefix(hash, tt.expectedPrefix), hash)
+
+			other, err := auth.HashPassword(tt.algorithm, "password")
+			assert.NoError(t, err)
+			assert.NotEqual(t, hash, other, "hashes are salted")
+
+			ok, err := auth.CheckPassword(hash, "password")
+			assert.NoError(t, err)
+			assert.True(t, ok)
+
+			ok, err = auth.CheckPassword(hash, "Password")
+			assert.NoError(t, err)
+			assert.False(t, ok)
+		})
+	}
+}
+
+func TestCheckPassword_Invalid(t *testing.T) {
+	for _, hash := range []string{
+		"",
+		"plain-text",
+		"$argon2id$v=19$m=65536,t=1,p=4$salt",
+		"$argon2id$v=18$m=65536,t=1,p=4$c2FsdA$a2V5",
+		"$argon2i$v=19$m=65536,t=1,p=4$c2FsdA$a2V5",
+	} {
+		t.Run(hash, func(t *testing.T) {
+			// test logic
+			ok, err := auth.CheckPassword(hash, "password")
+			assert.EqualError(t, err, "unknown password hash")
+			assert.False(t, ok)
+		})
+	}
+}
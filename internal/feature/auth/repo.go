@@ -0,0 +1,78 @@
+package auth
+
+import (
+	"context"
+	"go-fiber-api/internal/core/model"
+	"go-fiber-api/internal/core/repo"
+	"go-fiber-api/internal/core/storage/db"
+	"time"
+)
+
+// Repository stores the refresh tokens of users.
+type Repository interface {
+	Insert(ctx context.Context, token *model.RefreshToken) error
+	FindByHash(ctx context.Context, hash string) (model.RefreshToken, error)
+	// Revoke revokes the token of id, reporting false when it already was.
+	Revoke(ctx context.Context, id uint) (bool, error)
+	RevokeFamily(ctx context.Context, familyID string) error
+	// IsActive reports whether a token of the family is not revoked.
+	IsActive(ctx context.Context, familyID string) (bool, error)
+	// DeleteExpired deletes the tokens expired before, returning how many.
+	DeleteExpired(ctx context.Context, before time.Time) (int64, error)
+}
+
+type repoImpl struct {
+	db db.Client
+}
+
+func ProvideRepository(db db.Client) Repository {
+	return &repoImpl{
+		db: db,
+	}
+}
+
+func (r *repoImpl) Insert(ctx context.Context, token *model.RefreshToken) error {
+	return r.db.WithContext(ctx).Create(token).Error
+}
+
+func (r *repoImpl) FindByHash(ctx context.Context, hash string) (model.RefreshToken, error) {
+	var token model.RefreshToken
+	spec := repo.Equal("token_hash", hash)
+	err := r.db.WithContext(ctx).Where(spec.GetQuery(), spec.GetValues()...).First(&token).Error
+
+	return token, err
+}
+
+func (r *repoImpl) Revoke(ctx context.Context, id uint) (bool, error) {
+	spec := repo.And(repo.Equal("id", id), repo.IsNull("revoked_at"))
+	// Conditional, of two concurrent rotations of a token only one wins.
+	tx := r.db.WithContext(ctx).Model(&model.RefreshToken{}).
+		Where(spec.GetQuery(), spec.GetValues()...).
+		Update("revoked_at", time.Now())
+
+	return tx.RowsAffected == 1, tx.Error
+}
+
+func (r *repoImpl) RevokeFamily(ctx context.Context, familyID string) error {
+	spec := repo.And(repo.Equal("family_id", familyID), repo.IsNull("revoked_at"))
+
+	return r.db.WithContext(ctx).Model(&model.RefreshToken{}).
+		Where(spec.GetQuery(), spec.GetValues()...).
+		Update("revoked_at", time.Now()).Error
+}
+
+func (r *repoImpl) IsActive(ctx context.Context, familyID string) (bool, error) {
+	var count int64
+	spec := repo.And(repo.Equal("family_id", familyID), repo.IsNull("revoked_at"))
+	err := r.db.WithContext(ctx).Model(&model.RefreshToken{}).
+		Where(spec.GetQuery(), spec.GetValues()...).
+		Count(&count).Error
+
+	return count > 0, err
+}
+
+func (r *repoImpl) DeleteExpired(ctx context.Context, before time.Time) (int64, error) {
+	tx := r.db.WithContext(ctx).Unscoped().Where("expires_at < ?", before).Delete(&model.RefreshToken{})
+
+	return tx.RowsAffected, tx.Error
+}
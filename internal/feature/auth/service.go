@@ -0,0 +1,432 @@
+//go:generate mockgen -source=service.go -mock_names=Service=MockAuthService -destination=../../mock/mock_auth_service.go -package=mock
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"go-fiber-api/internal/core/config"
+	"go-fiber-api/internal/core/guard"
+	"go-fiber-api/internal/core/model"
+	"go-fiber-api/internal/core/repo"
+	"go-fiber-api/internal/core/storage/db"
+	"go-fiber-api/internal/wrapper/jwtx"
+	"go-fiber-api/internal/wrapper/logx"
+	"go-fiber-api/toolkit/apperror"
+	"strconv"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// TokenUseAccess is the token_use claim of access tokens, telling them apart
+// from the other tokens of the keyring such as API keys.
+const TokenUseAccess = "access"
+
+var (
+	ErrUsernameTaken      = apperror.Conflict("USERNAME_TAKEN", "username is already taken")
+	ErrInvalidCredentials = apperror.Unauthorized("INVALID_CREDENTIALS", "invalid username or password")
+	ErrInvalidToken       = apperror.Unauthorized("INVALID_TOKEN", "invalid or expired token")
+	ErrUserLocked         = apperror.Forbidden("USER_LOCKED", "user is locked")
+	ErrUserBlocked        = apperror.Forbidden("USER_BLOCKED", "user is blocked")
+)
+
+// AccessClaims are the claims of an access token, Subject being the user ID
+// and SessionID the family of the refresh token it was issued with.
+type AccessClaims struct {
+	jwt.RegisteredClaims
+	SessionID string `json:"sid"`
+	TokenUse  string `json:"token_use"`
+}
+
+type Service interface {
+	Register(ctx context.Context, dto *model.RegisterDTO) (model.UserDTO, error)
+	// Login checks the password of the user, refusing the attempts of a
+	// username or of clientIP for a while after too many failures with a
+	// *guard.WaitError. A user failing too often is locked for a while.
+	Login(ctx context.Context, dto *model.LoginDTO, clientIP string) (model.TokenDTO, error)
+	// Refresh rotates refreshToken, which can't be used again.
+	Refresh(ctx context.Context, refreshToken string) (model.TokenDTO, error)
+	// Logout revokes the session of refreshToken, its access tokens included.
+	Logout(ctx context.Context, refreshToken string) error
+	// Authenticate returns the user of accessToken.
+	Authenticate(ctx context.Context, accessToken string) (model.UserDTO, error)
+	// StartSession starts a session of user, authenticated by other means
+	// than their password, e.g. an identity provider.
+	StartSession(ctx context.Context, user model.UserDTO) (model.TokenDTO, error)
+}
+
+type serviceImpl struct {
+	cfg        config.AuthConfig
+	bruteForce config.BruteForceConfig
+	keyring    *jwtx.Keyring
+	db         db.Client
+	users      repo.Repo[model.User, model.UserDTO]
+	tokens     Repository
+	guard      guard.Guard
+
+	// Checked against when the username is unknown, so that the response
+	// time doesn't tell which usernames exist.
+	dummyHash string
+}
+
+func ProvideService(cfg *config.Configuration, keyring *jwtx.Keyring, client db.Client, users repo.Repo[model.User, model.UserDTO], tokens Repository, g guard.Guard) (Service, error) {
+	dummyHash, err := HashPassword(cfg.Auth.PasswordHash, uuid.NewString())
+	if err != nil {
+		return nil, err
+	}
+
+	return &serviceImpl{
+		cfg:        cfg.Auth,
+		bruteForce: cfg.BruteForce,
+		keyring:    keyring,
+		db:         client,
+		users:      users,
+		tokens:     tokens,
+		guard:      g,
+		dummyHash:  dummyHash,
+	}, nil
+}
+
+func (s *serviceImpl) Register(ctx context.Context, dto *model.RegisterDTO) (model.UserDTO, error) {
+	count, err := s.users.Count(ctx, repo.Equal("username", dto.Username))
+	if err != nil {
+		return model.UserDTO{}, err
+	}
+	if count > 0 {
+		return model.UserDTO{}, ErrUsernameTaken
+	}
+
+	hash, err := HashPassword(s.cfg.PasswordHash, dto.Password)
+	if err != nil {
+		return model.UserDTO{}, err
+	}
+
+	user := model.UserDTO{
+		Username:     dto.Username,
+		PasswordHash: hash,
+		FirstName:    dto.FirstName,
+		LastName:     dto.LastName,
+		Status:       model.UserStatusNormal,
+	}
+	err = s.users.Insert(ctx, &user)
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		// Registered concurrently since the count.
+		return model.UserDTO{}, ErrUsernameTaken
+	}
+	if err != nil {
+		logx.FromContext(ctx).Errorf("auth service register: %v", err)
+		return model.UserDTO{}, err
+	}
+
+	return user, nil
+}
+
+func (s *serviceImpl) Login(ctx context.Context, dto *model.LoginDTO, clientIP string) (model.TokenDTO, error) {
+	userKey, ipKey := "login:user:"+dto.Username, "login:ip:"+clientIP
+	if err := s.guard.Check(ctx, userKey, ipKey); err != nil {
+		return model.TokenDTO{}, err
+	}
+
+	users, err := s.users.Find(ctx, repo.Equal("username", dto.Username))
+	if err != nil {
+		return model.TokenDTO{}, err
+	}
+
+	if len(users) == 0 {
+		_, _ = CheckPassword(s.dummyHash, dto.Password)
+		s.fail(ctx, nil, userKey, ipKey)
+		return model.TokenDTO{}, ErrInvalidCredentials
+	}
+	user := users[0]
+
+	if lockedFor(user) > 0 {
+		// Refused whatever the password, which guesses can't tell right
+		// until the user is unlocked.
+		_, _ = CheckPassword(s.dummyHash, dto.Password)
+		return model.TokenDTO{}, ErrUserLocked
+	}
+
+	ok, err := CheckPassword(user.PasswordHash, dto.Password)
+	if err != nil {
+		// Users without password, or hashed by an unknown algorithm.
+		logx.FromContext(ctx).Warnf("auth service login of user %d: %v", user.ID, err)
+	}
+	if !ok {
+		s.fail(ctx, &user, userKey, ipKey)
+		return model.TokenDTO{}, ErrInvalidCredentials
+	}
+
+	if err := s.checkStatus(ctx, &user); err != nil {
+		return model.TokenDTO{}, err
+	}
+	// The failures of the client IP are kept: a login to one account must
+	// not let the guessing of others go on.
+	s.guard.Reset(ctx, userKey)
+
+	if hashAlgorithm(user.PasswordHash) != s.cfg.PasswordHash {
+		s.rehash(ctx, user, dto.Password)
+	}
+
+	return s.issue(ctx, user.ID, uuid.NewString())
+}
+
+// fail records a failed login of user, nil when the username is unknown,
+// locking them once their failures reach the lock threshold.
+func (s *serviceImpl) fail(ctx context.Context, user *model.UserDTO, userKey, ipKey string) {
+	s.guard.Fail(ctx, ipKey)
+	failures := s.guard.Fail(ctx, userKey)
+
+	threshold := int64(s.bruteForce.LockThreshold)
+	if user == nil || user.Status != model.UserStatusNormal || threshold == 0 || failures < threshold {
+		return
+	}
+
+	until := time.Now().Add(time.Duration(s.bruteForce.LockDuration) * time.Second)
+	if err := s.users.UpdateColumns(ctx, user.ID, map[string]any{
+		"status":       model.UserStatusLocked,
+		"locked_until": until,
+	}); err != nil {
+		logx.FromContext(ctx).Errorf("auth service lock user %d: %v", user.ID, err)
+		return
+	}
+	// Counted from zero again once unlocked.
+	s.guard.Reset(ctx, userKey)
+
+	logx.FromContext(ctx).Warnf("auth service locked user %d until %s after %d failed logins", user.ID, until.Format(time.RFC3339), failures)
+}
+
+// rehash hashes the password of user with AUTH_PASSWORD_HASH, the login
+// still succeeding when it fails.
+func (s *serviceImpl) rehash(ctx context.Context, user model.UserDTO, password string) {
+	hash, err := HashPassword(s.cfg.PasswordHash, password)
+	if err == nil {
+		err = s.users.UpdateColumns(ctx, user.ID, map[string]any{"password_hash": hash})
+	}
+	if err != nil {
+		logx.FromContext(ctx).Warnf("auth service rehash password of user %d: %v", user.ID, err)
+	}
+}
+
+func (s *serviceImpl) Refresh(ctx context.Context, refreshToken string) (model.TokenDTO, error) {
+	token, err := s.tokens.FindByHash(ctx, hashToken(refreshToken))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return model.TokenDTO{}, ErrInvalidToken
+	}
+	if err != nil {
+		return model.TokenDTO{}, err
+	}
+
+	if !time.Now().Before(token.ExpiresAt) {
+		return model.TokenDTO{}, ErrInvalidToken
+	}
+
+	// The token is only rotated along with the session it is exchanged for,
+	// so a refresh refused or failing midway leaves it usable.
+	var (
+		session model.TokenDTO
+		reused  bool
+	)
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		s := s.in(tx)
+
+		rotated := false
+		if token.RevokedAt == nil {
+			var err error
+			if rotated, err = s.tokens.Revoke(ctx, token.ID); err != nil {
+				return err
+			}
+		}
+		if !rotated {
+			reused = true
+			return nil
+		}
+
+		user, err := s.findUser(ctx, token.UserID)
+		if err != nil {
+			return err
+		}
+		if err := s.checkStatus(ctx, &user); err != nil {
+			return err
+		}
+
+		session, err = s.issue(ctx, user.ID, token.FamilyID)
+		return err
+	})
+	if err != nil {
+		return model.TokenDTO{}, err
+	}
+
+	if reused {
+		// A rotated token used again was stolen from the user, or the
+		// user from the thief: the session ends for both.
+		logx.FromContext(ctx).Warnf("auth service refresh: token of session %s of user %d reused, revoking the session", token.FamilyID, token.UserID)
+		if err := s.tokens.RevokeFamily(ctx, token.FamilyID); err != nil {
+			return model.TokenDTO{}, err
+		}
+		return model.TokenDTO{}, ErrInvalidToken
+	}
+
+	return session, nil
+}
+
+// in returns a copy of s whose repositories run in the transaction tx.
+func (s *serviceImpl) in(tx db.Client) *serviceImpl {
+	c := *s
+	c.db = tx
+	c.users = repo.NewRepository[model.User, model.UserDTO](tx)
+	c.tokens = ProvideRepository(tx)
+
+	return &c
+}
+
+func (s *serviceImpl) Logout(ctx context.Context, refreshToken string) error {
+	token, err := s.tokens.FindByHash(ctx, hashToken(refreshToken))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		// Nothing left to revoke.
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	return s.tokens.RevokeFamily(ctx, token.FamilyID)
+}
+
+func (s *serviceImpl) Authenticate(ctx context.Context, accessToken string) (model.UserDTO, error) {
+	var claims AccessClaims
+	token, err := s.keyring.Parse(accessToken, &claims, jwt.WithExpirationRequired())
+	if err != nil || !token.Valid || claims.TokenUse != TokenUseAccess {
+		return model.UserDTO{}, ErrInvalidToken
+	}
+
+	id, err := strconv.ParseUint(claims.Subject, 10, 64)
+	if err != nil {
+		return model.UserDTO{}, ErrInvalidToken
+	}
+
+	active, err := s.tokens.IsActive(ctx, claims.SessionID)
+	if err != nil {
+		return model.UserDTO{}, err
+	}
+	if !active {
+		return model.UserDTO{}, ErrInvalidToken
+	}
+
+	user, err := s.findUser(ctx, uint(id))
+	if err != nil {
+		return model.UserDTO{}, err
+	}
+	if err := s.checkStatus(ctx, &user); err != nil {
+		return model.UserDTO{}, err
+	}
+
+	return user, nil
+}
+
+func (s *serviceImpl) findUser(ctx context.Context, id uint) (model.UserDTO, error) {
+	user, err := s.users.FindByID(ctx, id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		// Deleted since the token was issued.
+		return model.UserDTO{}, ErrInvalidToken
+	}
+
+	return user, err
+}
+
+// StartSession checks the status of user like Login does, a locked or
+// blocked user being refused whichever way they signed in.
+func (s *serviceImpl) StartSession(ctx context.Context, user model.UserDTO) (model.TokenDTO, error) {
+	if err := s.checkStatus(ctx, &user); err != nil {
+		return model.TokenDTO{}, err
+	}
+
+	return s.issue(ctx, user.ID, uuid.NewString())
+}
+
+// issue returns a new access token and refresh token of the session
+// familyID.
+func (s *serviceImpl) issue(ctx context.Context, userID uint, familyID string) (model.TokenDTO, error) {
+	now := time.Now()
+
+	accessToken, err := s.keyring.Sign(AccessClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   strconv.FormatUint(uint64(userID), 10),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(time.Duration(s.cfg.AccessTokenTTL) * time.Second)),
+			ID:        uuid.NewString(),
+		},
+		SessionID: familyID,
+		TokenUse:  TokenUseAccess,
+	})
+	if err != nil {
+		return model.TokenDTO{}, err
+	}
+
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return model.TokenDTO{}, err
+	}
+	refreshToken := base64.RawURLEncoding.EncodeToString(b)
+
+	if err := s.tokens.Insert(ctx, &model.RefreshToken{
+		UserID:    userID,
+		TokenHash: hashToken(refreshToken),
+		FamilyID:  familyID,
+		ExpiresAt: now.Add(time.Duration(s.cfg.RefreshTokenTTL) * time.Second),
+	}); err != nil {
+		return model.TokenDTO{}, err
+	}
+
+	return model.TokenDTO{
+		AccessToken:  accessToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    s.cfg.AccessTokenTTL,
+		RefreshToken: refreshToken,
+	}, nil
+}
+
+// checkStatus refuses locked and blocked users, unlocking user first when
+// their temporary lock is over.
+func (s *serviceImpl) checkStatus(ctx context.Context, user *model.UserDTO) error {
+	if user.Status == model.UserStatusLocked && user.LockedUntil != nil && lockedFor(*user) <= 0 {
+		if err := s.users.UpdateColumns(ctx, user.ID, map[string]any{
+			"status":       model.UserStatusNormal,
+			"locked_until": nil,
+		}); err != nil {
+			return err
+		}
+		user.Status, user.LockedUntil = model.UserStatusNormal, nil
+		logx.FromContext(ctx).Infof("auth service unlocked user %d", user.ID)
+	}
+
+	switch user.Status {
+	case model.UserStatusLocked:
+		return ErrUserLocked
+	case model.UserStatusBlocked:
+		return ErrUserBlocked
+	default:
+		return nil
+	}
+}
+
+// lockedFor is the time left of the temporary lock of user, 0 when none.
+func lockedFor(user model.UserDTO) time.Duration {
+	if user.Status != model.UserStatusLocked || user.LockedUntil == nil {
+		return 0
+	}
+
+	return max(time.Until(*user.LockedUntil), 0)
+}
+
+// hashToken returns the SHA-256 of a refresh token, the form it is stored
+// in. Unlike passwords, refresh tokens are random enough not to need a salt.
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
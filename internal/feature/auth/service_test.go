@@ -0,0 +1,537 @@
+package auth_test
+
+import (
+	"context"
+	"fmt"
+	"go-fiber-api/internal/core/config"
+	"go-fiber-api/internal/core/guard"
+	"go-fiber-api/internal/core/lifecycle"
+	"go-fiber-api/internal/core/model"
+	"go-fiber-api/internal/core/storage/db"
+	"go-fiber-api/internal/feature/auth"
+	"go-fiber-api/internal/feature/user"
+	"go-fiber-api/internal/wrapper/jwtx"
+	"go-fiber-api/internal/wrapper/redis"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+type fixture struct {
+	db      *gorm.DB
+	keyring *jwtx.Keyring
+	s       auth.Service
+}
+
+// scopedGuard keeps the failures of a test apart from the ones of other
+// tests and runs, sharing the Redis of the tests.
+type scopedGuard struct {
+	guard.Guard
+	scope string
+}
+
+func (g scopedGuard) Check(ctx context.Context, keys ...string) error {
+	for i := range keys {
+		keys[i] = g.scope + keys[i]
+	}
+	return g.Guard.Check(ctx, keys...)
+}
+
+func (g scopedGuard) Fail(ctx context.Context, key string) int64 {
+	return g.Guard.Fail(ctx, g.scope+key)
+}
+
+func (g scopedGuard) Ban(ctx context.Context, key string, ttl time.Duration) {
+	g.Guard.Ban(ctx, g.scope+key, ttl)
+}
+
+func (g scopedGuard) Reset(ctx context.Context, key string) {
+	g.Guard.Reset(ctx, g.scope+key)
+}
+
+func newFixture(t *testing.T, passwordHash string) fixture {
+	client, err := db.GetDbTestMode()
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	cfg := &config.Configuration{
+		SecretKey: "TEST_SECRET_KEY",
+		JWT:       config.JWTConfig{LegacyEnabled: true},
+		Auth: config.AuthConfig{
+			AccessTokenTTL:  60,
+			RefreshTokenTTL: 3600,
+			PasswordHash:    passwordHash,
+		},
+		BruteForce: config.BruteForceConfig{
+			Window:        60,
+			FreeAttempts:  5,
+			BaseDelay:     30,
+			MaxDelay:      60,
+			LockThreshold: 3,
+			LockDuration:  60,
+		},
+		Redis: config.RedisConfig{
+			Host: "localhost",
+			Port: "6379",
+			DB:   10,
+		},
+	}
+	keyring, err := jwtx.Provide(cfg)
+	assert.NoError(t, err)
+
+	rc, err := redis.ProvideClient(cfg, nil, nil, lifecycle.New())
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	t.Cleanup(func() { _ = rc.Close() })
+	g := scopedGuard{Guard: guard.Provide(cfg, rc), scope: uuid.NewString() + ":"}
+
+	s, err := auth.ProvideService(cfg, keyring, client, user.ProvideRepository(client), auth.ProvideRepository(client), g)
+	assert.NoError(t, err)
+
+	return fixture{db: client, keyring: keyring, s: s}
+}
+
+// register creates a user with status, returning it.
+func (f fixture) register(t *testing.T, username string, status model.UserStatus) model.UserDTO {
+	u, err := f.s.Register(context.Background(), &model.RegisterDTO{Username: username, Password: "password"})
+	assert.NoError(t, err)
+	if status != model.UserStatusNormal {
+		assert.NoError(t, f.db.Model(&model.User{}).Where("id = ?", u.ID).Update("status", status).Error)
+	}
+
+	return u
+}
+
+func (f fixture) login(t *testing.T, username string) model.TokenDTO {
+	token, err := f.s.Login(context.Background(), &model.LoginDTO{Username: username, Password: "password"}, "10.0.0.1")
+	assert.NoError(t, err)
+	return token
+}
+
+func Test_Auth_serviceImpl_Register(t *testing.T) {
+	f := newFixture(t, auth.HashArgon2id)
+	f.register(t, "taken", model.UserStatusNormal)
+
+	tests := []struct {
+		name          string
+		dto           *model.RegisterDTO
+		expectedError error
+	}{
+		{
+			name: "when_username_is_new_should_create_user_with_hashed_password",
+			dto:  &model.RegisterDTO{Username: "alice", Password: "password", FirstName: "Alice"},
+		},
+		{
+			name:          "when_username_is_taken_should_get_error",
+			dto:           &model.RegisterDTO{Username: "taken", Password: "password"},
+			expectedError: auth.ErrUsernameTaken,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// test logic
+			u, err := f.s.Register(context.Background(), tt.dto)
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.NotZero(t, u.ID)
+			assert.Equal(t, model.UserStatusNormal, u.Status)
+			assert.Equal(t, "Alice", u.FirstName)
+			assert.NotContains(t, u.PasswordHash, tt.dto.Password)
+
+			ok, err := auth.CheckPassword(u.PasswordHash, tt.dto.Password)
+			assert.NoError(t, err)
+			assert.True(t, ok)
+		})
+	}
+}
+
+func Test_Auth_serviceImpl_Login(t *testing.T) {
+	f := newFixture(t, auth.HashArgon2id)
+	f.register(t, "normal", model.UserStatusNormal)
+	f.register(t, "locked", model.UserStatusLocked)
+	f.register(t, "blocked", model.UserStatusBlocked)
+
+	tests := []struct {
+		name          string
+		dto           *model.LoginDTO
+		expectedError error
+	}{
+		{
+			name: "when_password_matches_should_get_tokens",
+			dto:  &model.LoginDTO{Username: "normal", Password: "password"},
+		},
+		{
+			name:          "when_password_is_wrong_should_get_error",
+			dto:           &model.LoginDTO{Username: "normal", Password: "wrong-password"},
+			expectedError: auth.ErrInvalidCredentials,
+		},
+		{
+			name:          "when_username_is_unknown_should_get_same_error",
+			dto:           &model.LoginDTO{Username: "unknown", Password: "password"},
+			expectedError: auth.ErrInvalidCredentials,
+		},
+		{
+			name:          "when_user_is_locked_should_get_error",
+			dto:           &model.LoginDTO{Username: "locked", Password: "password"},
+			expectedError: auth.ErrUserLocked,
+		},
+		{
+			name:          "when_user_is_blocked_should_get_error",
+			dto:           &model.LoginDTO{Username: "blocked", Password: "password"},
+			expectedError: auth.ErrUserBlocked,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// test logic
+			token, err := f.s.Login(context.Background(), tt.dto, "10.0.0.1")
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+				assert.Zero(t, token)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, "Bearer", token.TokenType)
+			assert.Equal(t, 60, token.ExpiresIn)
+			assert.NotEmpty(t, token.RefreshToken)
+
+			u, err := f.s.Authenticate(context.Background(), token.AccessToken)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.dto.Username, u.Username)
+		})
+	}
+
+	t.Run("when_password_hash_is_outdated_should_rehash_it", func(t *testing.T) {
+		bcryptHash, err := auth.HashPassword(auth.HashBcrypt, "password")
+		assert.NoError(t, err)
+		assert.NoError(t, f.db.Create(&model.User{Username: "legacy", PasswordHash: bcryptHash, Status: model.UserStatusNormal}).Error)
+
+		// test logic
+		f.login(t, "legacy")
+
+		var u model.User
+		assert.NoError(t, f.db.Where("username = ?", "legacy").First(&u).Error)
+		assert.Regexp(t, `^\$argon2id\$`, u.PasswordHash)
+		ok, err := auth.CheckPassword(u.PasswordHash, "password")
+		assert.NoError(t, err)
+		assert.True(t, ok)
+	})
+}
+
+func Test_Auth_serviceImpl_Login_BruteForce(t *testing.T) {
+	ctx := context.Background()
+	f := newFixture(t, auth.HashBcrypt)
+	alice := f.register(t, "alice", model.UserStatusNormal)
+
+	login := func(username, password, clientIP string) error {
+		_, err := f.s.Login(ctx, &model.LoginDTO{Username: username, Password: password}, clientIP)
+		return err
+	}
+
+	t.Run("when_client_ip_fails_too_often_should_delay_it", func(t *testing.T) {
+		for i := range 6 {
+			assert.ErrorIs(t, login(fmt.Sprintf("unknown-%d", i), "password", "10.0.0.2"), auth.ErrInvalidCredentials)
+		}
+
+		// test logic
+		err := login("alice", "password", "10.0.0.2")
+		var waitErr *guard.WaitError
+		assert.ErrorAs(t, err, &waitErr)
+		assert.InDelta(t, 30*time.Second, waitErr.RetryAfter, float64(time.Second))
+
+		assert.NoError(t, login("alice", "password", "10.0.0.3"), "other client IPs go on")
+	})
+
+	t.Run("when_username_fails_too_often_should_delay_it", func(t *testing.T) {
+		for i := range 6 {
+			assert.ErrorIs(t, login("unknown", "password", fmt.Sprintf("10.0.1.%d", i)), auth.ErrInvalidCredentials)
+		}
+
+		// test logic
+		err := login("unknown", "password", "10.0.1.100")
+		assert.ErrorIs(t, err, guard.ErrTooManyAttempts)
+	})
+
+	t.Run("when_user_fails_too_often_should_lock_them_for_a_while", func(t *testing.T) {
+		for i := range 3 {
+			assert.ErrorIs(t, login("alice", "wrong-password", fmt.Sprintf("10.0.2.%d", i)), auth.ErrInvalidCredentials)
+		}
+
+		// test logic
+		assert.ErrorIs(t, login("alice", "password", "10.0.2.100"), auth.ErrUserLocked)
+
+		var u model.User
+		assert.NoError(t, f.db.First(&u, alice.ID).Error)
+		assert.Equal(t, model.UserStatusLocked, u.Status)
+		assert.Equal(t, alice.PasswordHash, u.PasswordHash, "only the lock is written")
+		if assert.NotNil(t, u.LockedUntil) {
+			assert.WithinDuration(t, time.Now().Add(time.Minute), *u.LockedUntil, 5*time.Second)
+		}
+	})
+
+	t.Run("when_lock_is_over_should_unlock_user", func(t *testing.T) {
+		assert.NoError(t, f.db.Model(&model.User{}).Where("id = ?", alice.ID).Update("locked_until", time.Now().Add(-time.Second)).Error)
+
+		// test logic
+		assert.NoError(t, login("alice", "password", "10.0.3.1"))
+
+		var u model.User
+		assert.NoError(t, f.db.First(&u, alice.ID).Error)
+		assert.Equal(t, model.UserStatusNormal, u.Status)
+		assert.Nil(t, u.LockedUntil)
+	})
+
+	t.Run("when_user_is_locked_by_hand_should_stay_locked", func(t *testing.T) {
+		f.register(t, "mallory", model.UserStatusLocked)
+
+		// test logic
+		for i := range 4 {
+			assert.Error(t, login("mallory", "wrong-password", fmt.Sprintf("10.0.4.%d", i)))
+		}
+		assert.ErrorIs(t, login("mallory", "password", "10.0.4.100"), auth.ErrUserLocked)
+
+		var u model.User
+		assert.NoError(t, f.db.Where("username = ?", "mallory").First(&u).Error)
+		assert.Nil(t, u.LockedUntil)
+	})
+}
+
+func Test_Auth_serviceImpl_Refresh(t *testing.T) {
+	ctx := context.Background()
+	f := newFixture(t, auth.HashBcrypt)
+	f.register(t, "alice", model.UserStatusNormal)
+
+	t.Run("when_token_is_valid_should_rotate_it", func(t *testing.T) {
+		first := f.login(t, "alice")
+
+		// test logic
+		second, err := f.s.Refresh(ctx, first.RefreshToken)
+		assert.NoError(t, err)
+		assert.NotEqual(t, first.RefreshToken, second.RefreshToken)
+
+		third, err := f.s.Refresh(ctx, second.RefreshToken)
+		assert.NoError(t, err)
+		_, err = f.s.Authenticate(ctx, third.AccessToken)
+		assert.NoError(t, err)
+	})
+
+	t.Run("when_rotated_token_is_reused_should_revoke_the_session", func(t *testing.T) {
+		first := f.login(t, "alice")
+		second, err := f.s.Refresh(ctx, first.RefreshToken)
+		assert.NoError(t, err)
+
+		// test logic
+		_, err = f.s.Refresh(ctx, first.RefreshToken)
+		assert.ErrorIs(t, err, auth.ErrInvalidToken)
+
+		_, err = f.s.Refresh(ctx, second.RefreshToken)
+		assert.ErrorIs(t, err, auth.ErrInvalidToken, "the latest token of the session is revoked too")
+		_, err = f.s.Authenticate(ctx, second.AccessToken)
+		assert.ErrorIs(t, err, auth.ErrInvalidToken)
+	})
+
+	t.Run("when_token_is_expired_should_get_error", func(t *testing.T) {
+		token := f.login(t, "alice")
+		assert.NoError(t, f.db.Model(&model.RefreshToken{}).Where("1 = 1").Update("expires_at", time.Now().Add(-time.Second)).Error)
+
+		// test logic
+		_, err := f.s.Refresh(ctx, token.RefreshToken)
+		assert.ErrorIs(t, err, auth.ErrInvalidToken)
+	})
+
+	t.Run("when_token_is_unknown_should_get_error", func(t *testing.T) {
+		// test logic
+		_, err := f.s.Refresh(ctx, "unknown")
+		assert.ErrorIs(t, err, auth.ErrInvalidToken)
+	})
+
+	t.Run("when_user_was_locked_should_get_error", func(t *testing.T) {
+		f.register(t, "bob", model.UserStatusNormal)
+		token := f.login(t, "bob")
+		assert.NoError(t, f.db.Model(&model.User{}).Where("username = ?", "bob").Update("status", model.UserStatusLocked).Error)
+
+		// test logic
+		_, err := f.s.Refresh(ctx, token.RefreshToken)
+		assert.ErrorIs(t, err, auth.ErrUserLocked)
+
+		// The refused refresh didn't use up the token.
+		assert.NoError(t, f.db.Model(&model.User{}).Where("username = ?", "bob").Update("status", model.UserStatusNormal).Error)
+		_, err = f.s.Refresh(ctx, token.RefreshToken)
+		assert.NoError(t, err)
+	})
+}
+
+func Test_Auth_serviceImpl_Logout(t *testing.T) {
+	ctx := context.Background()
+	f := newFixture(t, auth.HashBcrypt)
+	f.register(t, "alice", model.UserStatusNormal)
+
+	session := f.login(t, "alice")
+	other := f.login(t, "alice")
+
+	// test logic
+	assert.NoError(t, f.s.Logout(ctx, session.RefreshToken))
+
+	_, err := f.s.Refresh(ctx, session.RefreshToken)
+	assert.ErrorIs(t, err, auth.ErrInvalidToken)
+	_, err = f.s.Authenticate(ctx, session.AccessToken)
+	assert.ErrorIs(t, err, auth.ErrInvalidToken)
+
+	_, err = f.s.Authenticate(ctx, other.AccessToken)
+	assert.NoError(t, err, "other sessions are kept")
+
+	assert.NoError(t, f.s.Logout(ctx, session.RefreshToken), "logout is idempotent")
+	assert.NoError(t, f.s.Logout(ctx, "unknown"))
+}
+
+func Test_Auth_serviceImpl_Authenticate(t *testing.T) {
+	ctx := context.Background()
+	f := newFixture(t, auth.HashBcrypt)
+	alice := f.register(t, "alice", model.UserStatusNormal)
+	session := f.login(t, "alice")
+
+	sid := func(token string) string {
+		var claims auth.AccessClaims
+		_, err := f.keyring.Parse(token, &claims)
+		assert.NoError(t, err)
+		return claims.SessionID
+	}(session.AccessToken)
+
+	sign := func(claims jwt.Claims) string {
+		s, err := f.keyring.Sign(claims)
+		assert.NoError(t, err)
+		return s
+	}
+	claims := func(exp time.Time, tokenUse string) auth.AccessClaims {
+		return auth.AccessClaims{
+			RegisteredClaims: jwt.RegisteredClaims{
+				Subject:   strconv.FormatUint(uint64(alice.ID), 10),
+				ExpiresAt: jwt.NewNumericDate(exp),
+			},
+			SessionID: sid,
+			TokenUse:  tokenUse,
+		}
+	}
+
+	tests := []struct {
+		name          string
+		token         string
+		expectedError error
+	}{
+		{
+			name:  "when_token_is_valid_should_get_user",
+			token: session.AccessToken,
+		},
+		{
+			name:          "when_token_is_expired_should_get_error",
+			token:         sign(claims(time.Now().Add(-time.Minute), auth.TokenUseAccess)),
+			expectedError: auth.ErrInvalidToken,
+		},
+		{
+			name:          "when_token_has_no_expiry_should_get_error",
+			token:         sign(claims(time.Time{}, auth.TokenUseAccess)),
+			expectedError: auth.ErrInvalidToken,
+		},
+		{
+			name:          "when_token_is_an_api_key_should_get_error",
+			token:         sign(jwt.MapClaims{"name": "apikey", "exp": time.Now().Add(time.Hour).Unix()}),
+			expectedError: auth.ErrInvalidToken,
+		},
+		{
+			name:          "when_token_is_not_an_access_token_should_get_error",
+			token:         sign(claims(time.Now().Add(time.Minute), "refresh")),
+			expectedError: auth.ErrInvalidToken,
+		},
+		{
+			name:          "when_token_is_malformed_should_get_error",
+			token:         "not-a-token",
+			expectedError: auth.ErrInvalidToken,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// test logic
+			u, err := f.s.Authenticate(ctx, tt.token)
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, alice.ID, u.ID)
+			assert.Equal(t, "alice", u.Username)
+		})
+	}
+
+	t.Run("when_user_is_deleted_should_get_error", func(t *testing.T) {
+		assert.NoError(t, f.db.Delete(&model.User{}, alice.ID).Error)
+
+		// test logic
+		_, err := f.s.Authenticate(ctx, session.AccessToken)
+		assert.ErrorIs(t, err, auth.ErrInvalidToken)
+	})
+}
+
+func Test_Auth_serviceImpl_StartSession(t *testing.T) {
+	ctx := context.Background()
+	f := newFixture(t, auth.HashArgon2id)
+
+	tests := []struct {
+		name          string
+		status        model.UserStatus
+		expectedError error
+	}{
+		{
+			name:   "when_user_is_normal_should_issue_session",
+			status: model.UserStatusNormal,
+		},
+		{
+			name:          "when_user_is_locked_should_get_error",
+			status:        model.UserStatusLocked,
+			expectedError: auth.ErrUserLocked,
+		},
+		{
+			name:          "when_user_is_blocked_should_get_error",
+			status:        model.UserStatusBlocked,
+			expectedError: auth.ErrUserBlocked,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := f.register(t, strings.ToLower(string(tt.status)), tt.status)
+			u.Status = tt.status
+
+			// test logic
+			token, err := f.s.StartSession(ctx, u)
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+				return
+			}
+
+			assert.NoError(t, err)
+			authenticated, err := f.s.Authenticate(ctx, token.AccessToken)
+			assert.NoError(t, err)
+			assert.Equal(t, u.ID, authenticated.ID)
+
+			_, err = f.s.Refresh(ctx, token.RefreshToken)
+			assert.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,32 @@
+//go:build wireinject
+// +build wireinject
+
+//go:generate wire
+package auth
+
+import (
+	"go-fiber-api/internal/core/config"
+	"go-fiber-api/internal/core/guard"
+	"go-fiber-api/internal/core/model"
+	"go-fiber-api/internal/core/repo"
+	"go-fiber-api/internal/core/storage/db"
+	"go-fiber-api/internal/wrapper/jwtx"
+
+	"github.com/google/wire"
+)
+
+var ProviderSet = wire.NewSet(
+	ProvideRepository,
+
+	ProvideService,
+
+	ProvideHandler,
+
+	ProvideCleanup,
+)
+
+func Wire(cfg *config.Configuration, keyring *jwtx.Keyring, client db.Client, users repo.Repo[model.User, model.UserDTO], g guard.Guard) (Handler, error) {
+	wire.Build(ProviderSet)
+
+	return &handlerImpl{}, nil
+}
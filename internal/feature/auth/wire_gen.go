@@ -0,0 +1,41 @@
+// Code generated by Wire. DO NOT EDIT.
+
+//go:generate go run -mod=mod github.com/google/wire/cmd/wire
+//go:build !wireinject
+// +build !wireinject
+
+package auth
+
+import (
+	"github.com/google/wire"
+	"go-fiber-api/internal/core/config"
+	"go-fiber-api/internal/core/guard"
+	"go-fiber-api/internal/core/model"
+	"go-fiber-api/internal/core/repo"
+	"go-fiber-api/internal/core/storage/db"
+	"go-fiber-api/internal/wrapper/jwtx"
+)
+
+// Injectors from wire.go:
+
+func Wire(cfg *config.Configuration, keyring *jwtx.Keyring, client db.Client, users repo.Repo[model.User, model.UserDTO], g guard.Guard) (Handler, error) {
+	repository := ProvideRepository(client)
+	service, err := ProvideService(cfg, keyring, client, users, repository, g)
+	if err != nil {
+		return nil, err
+	}
+	handler := ProvideHandler(service)
+	return handler, nil
+}
+
+// wire.go:
+
+var ProviderSet = wire.NewSet(
+	ProvideRepository,
+
+	ProvideService,
+
+	ProvideHandler,
+
+	ProvideCleanup,
+)
@@ -0,0 +1,102 @@
+package loglevel
+
+import (
+	"go-fiber-api/internal/core/config"
+	"go-fiber-api/internal/core/model"
+	"go-fiber-api/internal/core/response"
+	"go-fiber-api/internal/wrapper/logx"
+	"go-fiber-api/toolkit/apperror"
+	"go-fiber-api/toolkit/validate"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+// Handler exposes the runtime log levels of logx.
+type Handler interface {
+	Get(c *fiber.Ctx) error
+	Update(c *fiber.Ctx) error
+	Reset(c *fiber.Ctx) error
+}
+
+type handlerImpl struct {
+	log *logx.LogX
+}
+
+// ProvideHandler also makes LOG_LEVEL of cfg, when set, the default level,
+// following its changes through w.
+func ProvideHandler(log *logx.LogX, cfg *config.Configuration, w *config.Watcher) Handler {
+	setDefault := func(name string) {
+		if name == "" {
+			return
+		}
+
+		level, err := logrus.ParseLevel(name)
+		if err != nil {
+			// Validated with the configuration.
+			return
+		}
+		log.Levels().SetDefault(level)
+	}
+
+	setDefault(cfg.LogLevel)
+	w.Subscribe(func(change config.Change) {
+		setDefault(change.New.LogLevel)
+	}, "LOG_LEVEL")
+
+	return &handlerImpl{
+		log: log,
+	}
+}
+
+func (h *handlerImpl) Get(ctx *fiber.Ctx) error {
+	return ctx.JSON(&response.ResponseDTO{
+		Message: "success",
+		Data:    h.log.Levels().State(),
+	})
+}
+
+func (h *handlerImpl) Update(ctx *fiber.Ctx) error {
+	var dto model.LogLevelDTO
+	if err := ctx.BodyParser(&dto); err != nil {
+		return apperror.Validation(apperror.CodeValidation, "invalid request body").Wrap(err)
+	}
+
+	if err := validate.Struct(dto); err != nil {
+		return err
+	}
+
+	level, err := logrus.ParseLevel(dto.Level)
+	if err != nil {
+		return apperror.Validation(apperror.CodeValidation, "invalid level: "+dto.Level).Wrap(err)
+	}
+
+	var d time.Duration
+	if dto.Duration != "" {
+		d, err = time.ParseDuration(dto.Duration)
+		if err != nil || d < 0 {
+			return apperror.Validation(apperror.CodeValidation, "invalid duration: "+dto.Duration)
+		}
+	}
+
+	if dto.Component != "" {
+		h.log.Levels().SetComponentLevel(dto.Component, level, d)
+	} else {
+		h.log.Levels().SetLevel(level, d)
+	}
+
+	logx.FromContext(ctx.UserContext()).
+		WithFields(logrus.Fields{"component": dto.Component, "duration": d.String()}).
+		Warnf("log level changed to %s", level)
+
+	return h.Get(ctx)
+}
+
+func (h *handlerImpl) Reset(ctx *fiber.Ctx) error {
+	h.log.Levels().Reset()
+
+	logx.FromContext(ctx.UserContext()).Warnf("log level reset to %s", h.log.Levels().State().Default)
+
+	return h.Get(ctx)
+}
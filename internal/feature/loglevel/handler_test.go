@@ -0,0 +1,107 @@
+package loglevel_test
+
+import (
+	"go-fiber-api/internal/core/config"
+	"go-fiber-api/internal/core/lifecycle"
+	"go-fiber-api/internal/feature/loglevel"
+	"go-fiber-api/internal/wrapper/logx"
+	"go-fiber-api/toolkit/errorhandler"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLogLevel_Handler(t *testing.T) {
+	tests := []struct {
+		name           string
+		method         string
+		body           string
+		expectedStatus int
+		expectedBody   []string
+	}{
+		{
+			name:           "when_get_should_return_state",
+			method:         fiber.MethodGet,
+			expectedStatus: fiber.StatusOK,
+			expectedBody:   []string{`"default":"debug"`, `"level":"debug"`},
+		},
+		{
+			name:           "when_body_is_invalid_should_return_400",
+			method:         fiber.MethodPut,
+			body:           `{"level":}`,
+			expectedStatus: fiber.StatusBadRequest,
+		},
+		{
+			name:           "when_level_is_missing_should_return_400",
+			method:         fiber.MethodPut,
+			body:           `{"component":"db"}`,
+			expectedStatus: fiber.StatusBadRequest,
+			expectedBody:   []string{`"tag":"required"`},
+		},
+		{
+			name:           "when_level_is_unknown_should_return_400",
+			method:         fiber.MethodPut,
+			body:           `{"level":"loud"}`,
+			expectedStatus: fiber.StatusBadRequest,
+			expectedBody:   []string{`"code":"VALIDATION_FAILED"`, `invalid level: loud`},
+		},
+		{
+			name:           "when_duration_is_invalid_should_return_400",
+			method:         fiber.MethodPut,
+			body:           `{"level":"info","duration":"soon"}`,
+			expectedStatus: fiber.StatusBadRequest,
+			expectedBody:   []string{`invalid duration: soon`},
+		},
+		{
+			name:           "when_level_is_valid_should_change_it",
+			method:         fiber.MethodPut,
+			body:           `{"level":"warn","duration":"1m"}`,
+			expectedStatus: fiber.StatusOK,
+			expectedBody:   []string{`"level":"warning"`, `"revertAt"`},
+		},
+		{
+			name:           "when_component_is_set_should_change_its_level",
+			method:         fiber.MethodPut,
+			body:           `{"level":"trace","component":"db"}`,
+			expectedStatus: fiber.StatusOK,
+			expectedBody:   []string{`"level":"debug"`, `"components":{"db":"trace"}`},
+		},
+		{
+			name:           "when_delete_should_reset_levels",
+			method:         fiber.MethodDelete,
+			expectedStatus: fiber.StatusOK,
+			expectedBody:   []string{`"level":"debug"`, `"components":{}`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			log := logx.Provide(lifecycle.New())
+			defer log.Levels().Reset()
+
+			h := loglevel.ProvideHandler(log, &config.Configuration{}, nil)
+
+			app := fiber.New(fiber.Config{ErrorHandler: errorhandler.Handler()})
+			app.Get("/admin/log-level", h.Get)
+			app.Put("/admin/log-level", h.Update)
+			app.Delete("/admin/log-level", h.Reset)
+
+			req := httptest.NewRequest(tt.method, "/admin/log-level", strings.NewReader(tt.body))
+			req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+
+			resp, err := app.Test(req)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+
+			body, err := io.ReadAll(resp.Body)
+			assert.NoError(t, err)
+			for _, expected := range tt.expectedBody {
+				assert.Contains(t, string(body), expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,22 @@
+//go:build wireinject
+// +build wireinject
+
+//go:generate wire
+package loglevel
+
+import (
+	"go-fiber-api/internal/core/config"
+	"go-fiber-api/internal/wrapper/logx"
+
+	"github.com/google/wire"
+)
+
+var ProviderSet = wire.NewSet(
+	ProvideHandler,
+)
+
+func Wire(log *logx.LogX, cfg *config.Configuration, w *config.Watcher) (Handler, error) {
+	wire.Build(ProviderSet)
+
+	return &handlerImpl{}, nil
+}
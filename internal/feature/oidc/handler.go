@@ -0,0 +1,102 @@
+package oidc
+
+import (
+	"crypto/subtle"
+	"go-fiber-api/internal/core/config"
+	"go-fiber-api/internal/core/response"
+	"go-fiber-api/internal/wrapper/logx"
+	"net/url"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// stateCookie binds a login to the browser starting it: its callback is
+// refused from any other one, so neither a leaked callback URL nor a login
+// started by someone else can be completed with it.
+const stateCookie = "oidc_state"
+
+// Handler is the login with OpenID Connect providers.
+type Handler interface {
+	// Login redirects to the authorization endpoint of the provider.
+	Login(c *fiber.Ctx) error
+	// Callback is where the provider redirects back to, responding with a
+	// session like the password login.
+	Callback(c *fiber.Ctx) error
+}
+
+type handlerImpl struct {
+	s          Service
+	cookiePath string
+	stateTTL   int // seconds
+}
+
+func ProvideHandler(cfg *config.Configuration, s Service) Handler {
+	// Callbacks are under the public URL of /auth/oidc, which may be proxied
+	// under another path.
+	cookiePath := "/"
+	if u, err := url.Parse(cfg.OIDC.RedirectURL); err == nil && u.Path != "" {
+		cookiePath = u.Path
+	}
+
+	return &handlerImpl{
+		s:          s,
+		cookiePath: cookiePath,
+		stateTTL:   cfg.OIDC.StateTTL,
+	}
+}
+
+func (h *handlerImpl) Login(ctx *fiber.Ctx) error {
+	url, state, err := h.s.AuthCodeURL(ctx.UserContext(), ctx.Params("provider"))
+	if err != nil {
+		return err
+	}
+
+	h.setStateCookie(ctx, state, h.stateTTL)
+
+	return ctx.Redirect(url, fiber.StatusFound)
+}
+
+func (h *handlerImpl) Callback(ctx *fiber.Ctx) error {
+	cookie := ctx.Cookies(stateCookie)
+	h.setStateCookie(ctx, "", -1)
+
+	// The user denied the login, or the provider failed it. Its error is
+	// only logged, never echoed back.
+	if reason := ctx.Query("error"); reason != "" {
+		logx.FromContext(ctx.UserContext()).Warnf("oidc handler callback of %s: provider error %q", ctx.Params("provider"), reason)
+		if reason == "access_denied" {
+			return ErrLoginDenied
+		}
+		return ErrLoginFailed
+	}
+
+	state := ctx.Query("state")
+	if cookie == "" || subtle.ConstantTimeCompare([]byte(cookie), []byte(state)) != 1 {
+		return ErrInvalidState
+	}
+
+	token, err := h.s.Exchange(ctx.UserContext(), ctx.Params("provider"), state, ctx.Query("code"))
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(&response.ResponseDTO{
+		Message: "success",
+		Data:    token,
+	})
+}
+
+// setStateCookie sets the state cookie for maxAge seconds, deleting it when
+// negative.
+func (h *handlerImpl) setStateCookie(ctx *fiber.Ctx, state string, maxAge int) {
+	ctx.Cookie(&fiber.Cookie{
+		Name:     stateCookie,
+		Value:    state,
+		Path:     h.cookiePath,
+		MaxAge:   maxAge,
+		Secure:   true,
+		HTTPOnly: true,
+		// Sent along the top level redirect back from the provider.
+		SameSite: fiber.CookieSameSiteLaxMode,
+	})
+}
@@ -0,0 +1,151 @@
+package oidc_test
+
+import (
+	"go-fiber-api/internal/core/config"
+	"go-fiber-api/internal/core/model"
+	"go-fiber-api/internal/feature/auth"
+	"go-fiber-api/internal/feature/oidc"
+	"go-fiber-api/internal/mock"
+	"go-fiber-api/toolkit/errorhandler"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestOIDC_Handler(t *testing.T) {
+	cfg := &config.Configuration{
+		OIDC: config.OIDCConfig{RedirectURL: "https://api.example.com/auth/oidc", StateTTL: 60},
+	}
+	mockToken := model.TokenDTO{AccessToken: "access", TokenType: "Bearer", ExpiresIn: 900, RefreshToken: "refresh"}
+
+	tests := []struct {
+		name             string
+		path             string
+		stateCookie      string
+		s                func(m *mock.MockOIDCService)
+		expectedStatus   int
+		expectedBody     string
+		expectedLocation string
+		expectedCookie   string
+	}{
+		{
+			name: "when_login_starts_should_redirect_to_provider",
+			path: "/auth/oidc/corp",
+			s: func(m *mock.MockOIDCService) {
+				m.EXPECT().AuthCodeURL(gomock.Any(), "corp").Return("https://sso.example.com/authorize?state=s", "s", nil)
+			},
+			expectedStatus:   fiber.StatusFound,
+			expectedLocation: "https://sso.example.com/authorize?state=s",
+			expectedCookie:   "oidc_state=s; max-age=60; path=/auth/oidc; HttpOnly; secure; SameSite=Lax",
+		},
+		{
+			name: "when_provider_is_unknown_should_return_404",
+			path: "/auth/oidc/nope",
+			s: func(m *mock.MockOIDCService) {
+				m.EXPECT().AuthCodeURL(gomock.Any(), "nope").Return("", "", oidc.ErrUnknownProvider)
+			},
+			expectedStatus: fiber.StatusNotFound,
+			expectedBody:   `{"code":"UNKNOWN_PROVIDER","message":"unknown identity provider","ok":false}`,
+		},
+		{
+			name:        "when_login_completes_should_return_tokens",
+			path:        "/auth/oidc/corp/callback?state=s&code=c",
+			stateCookie: "s",
+			s: func(m *mock.MockOIDCService) {
+				m.EXPECT().Exchange(gomock.Any(), "corp", "s", "c").Return(mockToken, nil)
+			},
+			expectedStatus: fiber.StatusOK,
+			expectedBody:   `{"message":"success","data":{"accessToken":"access","tokenType":"Bearer","expiresIn":900,"refreshToken":"refresh"}}`,
+			expectedCookie: "oidc_state=; max-age=0; path=/auth/oidc; HttpOnly; secure; SameSite=Lax",
+		},
+		{
+			name:           "when_state_cookie_is_missing_should_return_400",
+			path:           "/auth/oidc/corp/callback?state=s&code=c",
+			s:              func(m *mock.MockOIDCService) {},
+			expectedStatus: fiber.StatusBadRequest,
+			expectedBody:   `{"code":"INVALID_LOGIN_STATE","message":"invalid or expired login state","ok":false}`,
+		},
+		{
+			name:           "when_state_cookie_is_of_another_login_should_return_400",
+			path:           "/auth/oidc/corp/callback?state=s&code=c",
+			stateCookie:    "other",
+			s:              func(m *mock.MockOIDCService) {},
+			expectedStatus: fiber.StatusBadRequest,
+			expectedBody:   `{"code":"INVALID_LOGIN_STATE","message":"invalid or expired login state","ok":false}`,
+			expectedCookie: "oidc_state=; max-age=0; path=/auth/oidc; HttpOnly; secure; SameSite=Lax",
+		},
+		{
+			name:           "when_provider_denies_login_should_return_401",
+			path:           "/auth/oidc/corp/callback?state=s&error=access_denied",
+			s:              func(m *mock.MockOIDCService) {},
+			expectedStatus: fiber.StatusUnauthorized,
+			expectedBody:   `{"code":"LOGIN_DENIED","message":"identity provider login denied","ok":false}`,
+		},
+		{
+			name:           "when_provider_fails_login_should_return_401_without_its_error",
+			path:           "/auth/oidc/corp/callback?state=s&error=%3Cscript%3Ealert(1)%3C%2Fscript%3E",
+			s:              func(m *mock.MockOIDCService) {},
+			expectedStatus: fiber.StatusUnauthorized,
+			expectedBody:   `{"code":"LOGIN_FAILED","message":"identity provider login failed","ok":false}`,
+		},
+		{
+			name:        "when_state_is_invalid_should_return_400",
+			path:        "/auth/oidc/corp/callback?state=s&code=c",
+			stateCookie: "s",
+			s: func(m *mock.MockOIDCService) {
+				m.EXPECT().Exchange(gomock.Any(), "corp", "s", "c").Return(model.TokenDTO{}, oidc.ErrInvalidState)
+			},
+			expectedStatus: fiber.StatusBadRequest,
+			expectedBody:   `{"code":"INVALID_LOGIN_STATE","message":"invalid or expired login state","ok":false}`,
+		},
+		{
+			name:        "when_user_is_blocked_should_return_403",
+			path:        "/auth/oidc/corp/callback?state=s&code=c",
+			stateCookie: "s",
+			s: func(m *mock.MockOIDCService) {
+				m.EXPECT().Exchange(gomock.Any(), "corp", "s", "c").Return(model.TokenDTO{}, auth.ErrUserBlocked)
+			},
+			expectedStatus: fiber.StatusForbidden,
+			expectedBody:   `{"code":"USER_BLOCKED","message":"user is blocked","ok":false}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			s := mock.NewMockOIDCService(ctrl)
+			tt.s(s)
+			h := oidc.ProvideHandler(cfg, s)
+
+			app := fiber.New(fiber.Config{ErrorHandler: errorhandler.Handler()})
+			app.Get("/auth/oidc/:provider", h.Login)
+			app.Get("/auth/oidc/:provider/callback", h.Callback)
+
+			req := httptest.NewRequest(fiber.MethodGet, tt.path, nil)
+			if tt.stateCookie != "" {
+				req.AddCookie(&http.Cookie{Name: "oidc_state", Value: tt.stateCookie})
+			}
+
+			// test logic
+			resp, err := app.Test(req)
+			assert.NoError(t, err)
+			body, _ := io.ReadAll(resp.Body)
+
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+			assert.Equal(t, tt.expectedLocation, resp.Header.Get(fiber.HeaderLocation))
+			if tt.expectedCookie != "" {
+				assert.Equal(t, tt.expectedCookie, resp.Header.Get(fiber.HeaderSetCookie))
+			}
+			if tt.expectedBody != "" {
+				assert.Equal(t, tt.expectedBody, string(body))
+			}
+		})
+	}
+}
@@ -0,0 +1,122 @@
+package oidc
+
+import (
+	"context"
+	"fmt"
+	"go-fiber-api/internal/core/config"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+
+	go_oidc "github.com/coreos/go-oidc/v3/oidc"
+	"golang.org/x/oauth2"
+)
+
+// providerName is also a path segment of the routes of the provider.
+var providerName = regexp.MustCompile(`^[a-z0-9_-]+$`)
+
+// provider is an OpenID Connect provider of the config. It is discovered at
+// its first login rather than at startup, which doesn't depend on it.
+type provider struct {
+	name   string
+	issuer string
+	oauth2 oauth2.Config // without endpoint until discovered
+
+	mu       sync.Mutex
+	verifier *go_oidc.IDTokenVerifier
+}
+
+// parseProviders reads the providers of cfg, keyed by name.
+func parseProviders(cfg config.OIDCConfig) (map[string]*provider, error) {
+	issuers, err := parsePairs("OIDC_PROVIDERS", cfg.Providers)
+	if err != nil {
+		return nil, err
+	}
+	clientIDs, err := parsePairs("OIDC_CLIENT_IDS", cfg.ClientIDs)
+	if err != nil {
+		return nil, err
+	}
+	clientSecrets, err := parsePairs("OIDC_CLIENT_SECRETS", cfg.ClientSecrets)
+	if err != nil {
+		return nil, err
+	}
+
+	for key, pairs := range map[string]map[string]string{"OIDC_CLIENT_IDS": clientIDs, "OIDC_CLIENT_SECRETS": clientSecrets} {
+		for name := range pairs {
+			if _, ok := issuers[name]; !ok {
+				return nil, fmt.Errorf("%s names unknown provider %q", key, name)
+			}
+		}
+	}
+
+	scopes := strings.Fields(cfg.Scopes)
+	if !slices.Contains(scopes, go_oidc.ScopeOpenID) {
+		scopes = append([]string{go_oidc.ScopeOpenID}, scopes...)
+	}
+
+	providers := make(map[string]*provider, len(issuers))
+	for name, issuer := range issuers {
+		if !providerName.MatchString(name) {
+			return nil, fmt.Errorf("OIDC_PROVIDERS name %q must be lowercase letters, digits, - or _", name)
+		}
+		if u, err := url.Parse(issuer); err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+			return nil, fmt.Errorf("OIDC_PROVIDERS issuer of provider %q must be a URL, got %q", name, issuer)
+		}
+		if clientIDs[name] == "" {
+			return nil, fmt.Errorf("OIDC_CLIENT_IDS has no client ID of provider %q", name)
+		}
+
+		providers[name] = &provider{
+			name:   name,
+			issuer: issuer,
+			oauth2: oauth2.Config{
+				ClientID:     clientIDs[name],
+				ClientSecret: clientSecrets[name],
+				RedirectURL:  strings.TrimSuffix(cfg.RedirectURL, "/") + "/" + name + "/callback",
+				Scopes:       scopes,
+			},
+		}
+	}
+
+	return providers, nil
+}
+
+// parsePairs reads the comma separated name=value of key.
+func parsePairs(key, s string) (map[string]string, error) {
+	pairs := map[string]string{}
+	for _, pair := range strings.Split(s, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+
+		name, value, ok := strings.Cut(pair, "=")
+		if !ok || name == "" || value == "" {
+			return nil, fmt.Errorf("%s must be comma separated name=value, got %q", key, pair)
+		}
+		pairs[strings.TrimSpace(name)] = strings.TrimSpace(value)
+	}
+
+	return pairs, nil
+}
+
+// discover fetches the endpoints and keys of p once, ctx carrying the HTTP
+// client. A failed discovery is tried again at the next login.
+func (p *provider) discover(ctx context.Context) (oauth2.Config, *go_oidc.IDTokenVerifier, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.verifier == nil {
+		discovered, err := go_oidc.NewProvider(ctx, p.issuer)
+		if err != nil {
+			return oauth2.Config{}, nil, fmt.Errorf("discover provider %s: %w", p.name, err)
+		}
+
+		p.oauth2.Endpoint = discovered.Endpoint()
+		p.verifier = discovered.Verifier(&go_oidc.Config{ClientID: p.oauth2.ClientID})
+	}
+
+	return p.oauth2, p.verifier, nil
+}
@@ -0,0 +1,44 @@
+package oidc
+
+import (
+	"context"
+	"go-fiber-api/internal/core/model"
+	"go-fiber-api/internal/core/storage/db"
+
+	"gorm.io/gorm"
+)
+
+// Repository stores the identities linking users to their providers.
+type Repository interface {
+	FindIdentity(ctx context.Context, provider, subject string) (model.Identity, error)
+	// Provision creates user along with identity, linked to it.
+	Provision(ctx context.Context, user *model.User, identity *model.Identity) error
+}
+
+type repoImpl struct {
+	db db.Client
+}
+
+func ProvideRepository(db db.Client) Repository {
+	return &repoImpl{
+		db: db,
+	}
+}
+
+func (r *repoImpl) FindIdentity(ctx context.Context, provider, subject string) (model.Identity, error) {
+	var identity model.Identity
+	err := r.db.WithContext(ctx).Where("provider = ? AND subject = ?", provider, subject).First(&identity).Error
+
+	return identity, err
+}
+
+func (r *repoImpl) Provision(ctx context.Context, user *model.User, identity *model.Identity) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(user).Error; err != nil {
+			return err
+		}
+
+		identity.UserID = user.ID
+		return tx.Create(identity).Error
+	})
+}
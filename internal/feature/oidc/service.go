@@ -0,0 +1,275 @@
+//go:generate mockgen -source=service.go -mock_names=Service=MockOIDCService -destination=../../mock/mock_oidc_service.go -package=mock
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"go-fiber-api/internal/core/config"
+	"go-fiber-api/internal/core/model"
+	"go-fiber-api/internal/core/repo"
+	cache_storage "go-fiber-api/internal/core/storage/cache"
+	"go-fiber-api/internal/feature/auth"
+	"go-fiber-api/internal/wrapper/logx"
+	"go-fiber-api/toolkit/apperror"
+	"net/http"
+	"slices"
+
+	go_oidc "github.com/coreos/go-oidc/v3/oidc"
+	"github.com/go-resty/resty/v2"
+	"golang.org/x/oauth2"
+	"gorm.io/gorm"
+)
+
+const stateKey = "oidc:state:%s"
+
+var (
+	ErrUnknownProvider = apperror.NotFound("UNKNOWN_PROVIDER", "unknown identity provider")
+	ErrInvalidState    = apperror.Validation("INVALID_LOGIN_STATE", "invalid or expired login state")
+	ErrLoginFailed     = apperror.Unauthorized("LOGIN_FAILED", "identity provider login failed")
+	ErrLoginDenied     = apperror.Unauthorized("LOGIN_DENIED", "identity provider login denied")
+)
+
+type Service interface {
+	// AuthCodeURL starts a login with the provider of name, returning the
+	// URL of its authorization endpoint to redirect the user to and the state
+	// of the login, which only the browser starting it must complete.
+	AuthCodeURL(ctx context.Context, name string) (authURL, state string, err error)
+	// Exchange completes the login of state with the authorization code of
+	// the provider of name, starting a session of the user linked to the
+	// subject at the provider, created at its first login.
+	Exchange(ctx context.Context, name, state, code string) (model.TokenDTO, error)
+}
+
+// loginState is what a login needs to be completed, kept until the callback
+// of the provider.
+type loginState struct {
+	Provider string `json:"provider"`
+	Verifier string `json:"verifier"` // PKCE
+	Nonce    string `json:"nonce"`
+}
+
+// claims are the claims of ID tokens used to provision users.
+type claims struct {
+	Email             string `json:"email"`
+	EmailVerified     bool   `json:"email_verified"`
+	PreferredUsername string `json:"preferred_username"`
+	GivenName         string `json:"given_name"`
+	FamilyName        string `json:"family_name"`
+}
+
+type serviceImpl struct {
+	providers map[string]*provider
+	client    *http.Client
+	cache     cache_storage.Cache
+	stateTTL  int // seconds
+	repo      Repository
+	users     repo.Repo[model.User, model.UserDTO]
+	auth      auth.Service
+}
+
+func ProvideService(
+	cfg *config.Configuration,
+	client *resty.Client,
+	cache cache_storage.Cache,
+	r Repository,
+	users repo.Repo[model.User, model.UserDTO],
+	authSvc auth.Service,
+) (Service, error) {
+	providers, err := parseProviders(cfg.OIDC)
+	if err != nil {
+		return nil, err
+	}
+
+	return &serviceImpl{
+		providers: providers,
+		// Calls to providers are traced like the other outbound calls.
+		client:   client.GetClient(),
+		cache:    cache,
+		stateTTL: cfg.OIDC.StateTTL,
+		repo:     r,
+		users:    users,
+		auth:     authSvc,
+	}, nil
+}
+
+func (s *serviceImpl) AuthCodeURL(ctx context.Context, name string) (string, string, error) {
+	p, ok := s.providers[name]
+	if !ok {
+		return "", "", ErrUnknownProvider
+	}
+
+	oauth2Cfg, _, err := p.discover(go_oidc.ClientContext(ctx, s.client))
+	if err != nil {
+		return "", "", err
+	}
+
+	state, err := randomString()
+	if err != nil {
+		return "", "", err
+	}
+	nonce, err := randomString()
+	if err != nil {
+		return "", "", err
+	}
+	login := loginState{Provider: name, Verifier: oauth2.GenerateVerifier(), Nonce: nonce}
+
+	if err := s.cache.Set(ctx, fmt.Sprintf(stateKey, state), login, s.stateTTL); err != nil {
+		return "", "", err
+	}
+
+	return oauth2Cfg.AuthCodeURL(state, oauth2.S256ChallengeOption(login.Verifier), go_oidc.Nonce(nonce)), state, nil
+}
+
+func (s *serviceImpl) Exchange(ctx context.Context, name, state, code string) (model.TokenDTO, error) {
+	p, ok := s.providers[name]
+	if !ok {
+		return model.TokenDTO{}, ErrUnknownProvider
+	}
+
+	login, err := s.takeState(ctx, state)
+	if err != nil {
+		return model.TokenDTO{}, err
+	}
+	if login.Provider != name {
+		return model.TokenDTO{}, ErrInvalidState
+	}
+
+	ctx = go_oidc.ClientContext(ctx, s.client)
+	oauth2Cfg, verifier, err := p.discover(ctx)
+	if err != nil {
+		return model.TokenDTO{}, err
+	}
+
+	token, err := oauth2Cfg.Exchange(ctx, code, oauth2.VerifierOption(login.Verifier))
+	if err != nil {
+		logx.FromContext(ctx).Warnf("oidc service exchange code of %s: %v", name, err)
+		return model.TokenDTO{}, ErrLoginFailed
+	}
+
+	rawIDToken, _ := token.Extra("id_token").(string)
+	idToken, err := verifier.Verify(ctx, rawIDToken)
+	if err != nil {
+		logx.FromContext(ctx).Warnf("oidc service verify ID token of %s: %v", name, err)
+		return model.TokenDTO{}, ErrLoginFailed
+	}
+	if idToken.Nonce != login.Nonce {
+		logx.FromContext(ctx).Warnf("oidc service verify ID token of %s: nonce mismatch", name)
+		return model.TokenDTO{}, ErrLoginFailed
+	}
+
+	var c claims
+	if err := idToken.Claims(&c); err != nil {
+		return model.TokenDTO{}, err
+	}
+
+	user, err := s.link(ctx, name, idToken.Subject, c)
+	if err != nil {
+		return model.TokenDTO{}, err
+	}
+
+	return s.auth.StartSession(ctx, user)
+}
+
+// takeState returns the login of state, which can't be completed again, not
+// even by concurrent callbacks.
+func (s *serviceImpl) takeState(ctx context.Context, state string) (loginState, error) {
+	if state == "" {
+		return loginState{}, ErrInvalidState
+	}
+
+	var login loginState
+	err := s.cache.Take(ctx, fmt.Sprintf(stateKey, state), &login)
+	if errors.Is(err, cache_storage.ErrMiss) {
+		return loginState{}, ErrInvalidState
+	}
+	if err != nil {
+		return loginState{}, err
+	}
+
+	return login, nil
+}
+
+// link returns the user of subject at the provider of name, creating it at
+// the first login of subject. Users are never linked by email: a provider
+// can't claim an account of another one, or a local one.
+func (s *serviceImpl) link(ctx context.Context, name, subject string, c claims) (model.UserDTO, error) {
+	identity, err := s.repo.FindIdentity(ctx, name, subject)
+	if err == nil {
+		user, err := s.users.FindByID(ctx, identity.UserID)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			// Deleted users are not provisioned again.
+			return model.UserDTO{}, ErrLoginFailed
+		}
+		return user, err
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return model.UserDTO{}, err
+	}
+
+	username, err := s.username(ctx, name, subject, c)
+	if err != nil {
+		return model.UserDTO{}, err
+	}
+
+	user := model.User{
+		Username:  username,
+		FirstName: c.GivenName,
+		LastName:  c.FamilyName,
+		Status:    model.UserStatusNormal,
+	}
+	identity = model.Identity{Provider: name, Subject: subject, Email: c.Email}
+	if err := s.repo.Provision(ctx, &user, &identity); err != nil {
+		// Provisioned by a concurrent first login meanwhile.
+		if identity, findErr := s.repo.FindIdentity(ctx, name, subject); findErr == nil {
+			return s.users.FindByID(ctx, identity.UserID)
+		}
+
+		logx.FromContext(ctx).Errorf("oidc service provision user of %s: %v", name, err)
+		return model.UserDTO{}, err
+	}
+	logx.FromContext(ctx).Infof("oidc service provisioned user %d of %s", user.ID, name)
+
+	return user.ToDTO(), nil
+}
+
+// username picks the first free of the preferred username, the email and
+// the subject at the provider of name. An email the provider didn't verify
+// is skipped, anyone could have claimed it.
+func (s *serviceImpl) username(ctx context.Context, name, subject string, c claims) (string, error) {
+	candidates := []string{c.PreferredUsername, name + "-" + subject}
+	if c.EmailVerified {
+		candidates = slices.Insert(candidates, 1, c.Email)
+	}
+	candidates = slices.DeleteFunc(candidates, func(username string) bool {
+		return len(username) < 3 || len(username) > 64
+	})
+
+	for _, username := range candidates {
+		count, err := s.users.Count(ctx, repo.Equal("username", username))
+		if err != nil {
+			return "", err
+		}
+		if count == 0 {
+			return username, nil
+		}
+	}
+
+	// Subjects are up to 255 characters, kept unique by a random suffix.
+	suffix, err := randomString()
+	if err != nil {
+		return "", err
+	}
+	return name + "-" + suffix[:16], nil
+}
+
+func randomString() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
@@ -0,0 +1,322 @@
+package oidc_test
+
+import (
+	"context"
+	"go-fiber-api/internal/core/config"
+	"go-fiber-api/internal/core/model"
+	cache_storage "go-fiber-api/internal/core/storage/cache"
+	"go-fiber-api/internal/core/storage/db"
+	"go-fiber-api/internal/feature/auth"
+	"go-fiber-api/internal/feature/oidc"
+	"go-fiber-api/internal/feature/oidc/oidctest"
+	"go-fiber-api/internal/feature/user"
+	"go-fiber-api/internal/mock"
+	"go-fiber-api/internal/wrapper/jwtx"
+	"net/url"
+	"sync"
+	"testing"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+)
+
+type fixture struct {
+	db       *gorm.DB
+	provider *oidctest.Provider
+	auth     auth.Service
+	s        oidc.Service
+}
+
+func newConfig(oidcCfg config.OIDCConfig) *config.Configuration {
+	return &config.Configuration{
+		SecretKey: "TEST_SECRET_KEY",
+		JWT:       config.JWTConfig{LegacyEnabled: true},
+		Auth: config.AuthConfig{
+			AccessTokenTTL:  60,
+			RefreshTokenTTL: 3600,
+			PasswordHash:    auth.HashBcrypt,
+		},
+		OIDC: oidcCfg,
+	}
+}
+
+func newFixture(t *testing.T) fixture {
+	client, err := db.GetDbTestMode()
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	cache, err := cache_storage.NewMemory(100)
+	assert.NoError(t, err)
+
+	provider := oidctest.NewProvider(t, "api", "secret")
+	cfg := newConfig(config.OIDCConfig{
+		Providers:     "corp=" + provider.Issuer() + ",other=" + provider.Issuer(),
+		ClientIDs:     "corp=api,other=api",
+		ClientSecrets: "corp=secret,other=secret",
+		RedirectURL:   "https://api.example.com/auth/oidc",
+		Scopes:        "profile email",
+		StateTTL:      60,
+	})
+
+	keyring, err := jwtx.Provide(cfg)
+	assert.NoError(t, err)
+	users := user.ProvideRepository(client)
+	authSvc, err := auth.ProvideService(cfg, keyring, client, users, auth.ProvideRepository(client), mock.NewMockGuard(gomock.NewController(t)))
+	assert.NoError(t, err)
+
+	s, err := oidc.ProvideService(cfg, resty.New(), cache, oidc.ProvideRepository(client), users, authSvc)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	return fixture{db: client, provider: provider, auth: authSvc, s: s}
+}
+
+// authorize logs in at the provider of name, returning the query of its
+// redirect back.
+func (f fixture) authorize(t *testing.T, name string) url.Values {
+	authURL, _, err := f.s.AuthCodeURL(context.Background(), name)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	callback, err := f.provider.Authorize(authURL)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	assert.Equal(t, "/auth/oidc/"+name+"/callback", callback.Path)
+
+	return callback.Query()
+}
+
+func (f fixture) login(t *testing.T, name string) (model.UserDTO, error) {
+	q := f.authorize(t, name)
+
+	token, err := f.s.Exchange(context.Background(), name, q.Get("state"), q.Get("code"))
+	if err != nil {
+		return model.UserDTO{}, err
+	}
+
+	return f.auth.Authenticate(context.Background(), token.AccessToken)
+}
+
+func Test_OIDC_serviceImpl_AuthCodeURL(t *testing.T) {
+	f := newFixture(t)
+
+	t.Run("when_provider_is_known_should_request_code_with_pkce", func(t *testing.T) {
+		// test logic
+		authURL, state, err := f.s.AuthCodeURL(context.Background(), "corp")
+		assert.NoError(t, err)
+
+		u, err := url.Parse(authURL)
+		assert.NoError(t, err)
+		assert.Equal(t, f.provider.Issuer()+"/authorize", u.Scheme+"://"+u.Host+u.Path)
+
+		q := u.Query()
+		assert.Equal(t, "code", q.Get("response_type"))
+		assert.Equal(t, "api", q.Get("client_id"))
+		assert.Equal(t, "https://api.example.com/auth/oidc/corp/callback", q.Get("redirect_uri"))
+		assert.Equal(t, "openid profile email", q.Get("scope"))
+		assert.Equal(t, "S256", q.Get("code_challenge_method"))
+		assert.NotEmpty(t, q.Get("code_challenge"))
+		assert.NotEmpty(t, state)
+		assert.Equal(t, state, q.Get("state"))
+		assert.NotEmpty(t, q.Get("nonce"))
+	})
+
+	t.Run("when_provider_is_unknown_should_get_error", func(t *testing.T) {
+		// test logic
+		_, _, err := f.s.AuthCodeURL(context.Background(), "nope")
+		assert.ErrorIs(t, err, oidc.ErrUnknownProvider)
+	})
+}
+
+func Test_OIDC_serviceImpl_Exchange(t *testing.T) {
+	ctx := context.Background()
+	f := newFixture(t)
+
+	t.Run("when_subject_is_new_should_provision_user", func(t *testing.T) {
+		f.provider.SetUser(map[string]any{"sub": "1001", "preferred_username": "alice", "email": "alice@example.com", "given_name": "Alice", "family_name": "Smith"})
+
+		// test logic
+		u, err := f.login(t, "corp")
+		assert.NoError(t, err)
+		assert.Equal(t, "alice", u.Username)
+		assert.Equal(t, "Alice", u.FirstName)
+		assert.Equal(t, "Smith", u.LastName)
+		assert.Empty(t, u.PasswordHash)
+
+		var identity model.Identity
+		assert.NoError(t, f.db.Where("user_id = ?", u.ID).First(&identity).Error)
+		assert.Equal(t, "corp", identity.Provider)
+		assert.Equal(t, "1001", identity.Subject)
+		assert.Equal(t, "alice@example.com", identity.Email)
+	})
+
+	t.Run("when_subject_is_linked_should_login_its_user", func(t *testing.T) {
+		f.provider.SetUser(map[string]any{"sub": "1001", "preferred_username": "renamed"})
+
+		// test logic
+		u, err := f.login(t, "corp")
+		assert.NoError(t, err)
+		assert.Equal(t, "alice", u.Username)
+
+		var count int64
+		assert.NoError(t, f.db.Model(&model.User{}).Count(&count).Error)
+		assert.Equal(t, int64(1), count)
+	})
+
+	t.Run("when_username_is_taken_should_never_link_by_it", func(t *testing.T) {
+		// The same subject at another provider is another user.
+		f.provider.SetUser(map[string]any{"sub": "1001", "preferred_username": "alice", "email": "alice@example.com", "email_verified": true})
+
+		// test logic
+		u, err := f.login(t, "other")
+		assert.NoError(t, err)
+		assert.Equal(t, "alice@example.com", u.Username)
+
+		u, err = f.login(t, "other")
+		assert.NoError(t, err)
+		assert.Equal(t, "alice@example.com", u.Username)
+
+		f.provider.SetUser(map[string]any{"sub": "1002", "preferred_username": "alice", "email": "alice@example.com", "email_verified": true})
+		u, err = f.login(t, "other")
+		assert.NoError(t, err)
+		assert.Equal(t, "other-1002", u.Username)
+	})
+
+	t.Run("when_email_is_not_verified_should_never_name_user_after_it", func(t *testing.T) {
+		f.provider.SetUser(map[string]any{"sub": "1003", "preferred_username": "alice", "email": "bob@example.com"})
+
+		// test logic
+		u, err := f.login(t, "other")
+		assert.NoError(t, err)
+		assert.Equal(t, "other-1003", u.Username)
+	})
+
+	t.Run("when_user_is_locked_should_get_error", func(t *testing.T) {
+		f.provider.SetUser(map[string]any{"sub": "1001"})
+		assert.NoError(t, f.db.Model(&model.User{}).Where("username = ?", "alice").Update("status", model.UserStatusLocked).Error)
+
+		// test logic
+		_, err := f.login(t, "corp")
+		assert.ErrorIs(t, err, auth.ErrUserLocked)
+	})
+
+	t.Run("when_state_is_used_again_should_get_error", func(t *testing.T) {
+		f.provider.SetUser(map[string]any{"sub": "2001"})
+		q := f.authorize(t, "corp")
+		_, err := f.s.Exchange(ctx, "corp", q.Get("state"), q.Get("code"))
+		assert.NoError(t, err)
+
+		// test logic
+		_, err = f.s.Exchange(ctx, "corp", q.Get("state"), q.Get("code"))
+		assert.ErrorIs(t, err, oidc.ErrInvalidState)
+	})
+
+	t.Run("when_state_is_used_concurrently_should_complete_once", func(t *testing.T) {
+		f.provider.SetUser(map[string]any{"sub": "2002"})
+		q := f.authorize(t, "corp")
+
+		// test logic
+		var wg sync.WaitGroup
+		errs := make([]error, 4)
+		for i := range errs {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, errs[i] = f.s.Exchange(ctx, "corp", q.Get("state"), q.Get("code"))
+			}()
+		}
+		wg.Wait()
+
+		completed := 0
+		for _, err := range errs {
+			if err == nil {
+				completed++
+				continue
+			}
+			assert.ErrorIs(t, err, oidc.ErrInvalidState)
+		}
+		assert.Equal(t, 1, completed)
+	})
+
+	t.Run("when_state_is_of_another_provider_should_get_error", func(t *testing.T) {
+		q := f.authorize(t, "corp")
+
+		// test logic
+		_, err := f.s.Exchange(ctx, "other", q.Get("state"), q.Get("code"))
+		assert.ErrorIs(t, err, oidc.ErrInvalidState)
+	})
+
+	t.Run("when_state_is_unknown_should_get_error", func(t *testing.T) {
+		q := f.authorize(t, "corp")
+
+		// test logic
+		_, err := f.s.Exchange(ctx, "corp", "forged", q.Get("code"))
+		assert.ErrorIs(t, err, oidc.ErrInvalidState)
+	})
+
+	t.Run("when_code_is_invalid_should_get_error", func(t *testing.T) {
+		q := f.authorize(t, "corp")
+
+		// test logic
+		_, err := f.s.Exchange(ctx, "corp", q.Get("state"), "forged")
+		assert.ErrorIs(t, err, oidc.ErrLoginFailed)
+	})
+
+	t.Run("when_provider_is_unknown_should_get_error", func(t *testing.T) {
+		// test logic
+		_, err := f.s.Exchange(ctx, "nope", "state", "code")
+		assert.ErrorIs(t, err, oidc.ErrUnknownProvider)
+	})
+}
+
+func TestProvideService_Invalid(t *testing.T) {
+	tests := []struct {
+		name          string
+		cfg           config.OIDCConfig
+		expectedError string
+	}{
+		{
+			name:          "when_pair_is_malformed_should_get_error",
+			cfg:           config.OIDCConfig{Providers: "https://sso.example.com"},
+			expectedError: `OIDC_PROVIDERS must be comma separated name=value, got "https://sso.example.com"`,
+		},
+		{
+			name:          "when_client_id_is_of_unknown_provider_should_get_error",
+			cfg:           config.OIDCConfig{Providers: "corp=https://sso.example.com", ClientIDs: "other=api"},
+			expectedError: `OIDC_CLIENT_IDS names unknown provider "other"`,
+		},
+		{
+			name:          "when_provider_has_no_client_id_should_get_error",
+			cfg:           config.OIDCConfig{Providers: "corp=https://sso.example.com,other=https://other.example.com", ClientIDs: "corp=api"},
+			expectedError: `OIDC_CLIENT_IDS has no client ID of provider "other"`,
+		},
+		{
+			name:          "when_secret_is_of_unknown_provider_should_get_error",
+			cfg:           config.OIDCConfig{Providers: "corp=https://sso.example.com", ClientIDs: "corp=api", ClientSecrets: "other=secret"},
+			expectedError: `OIDC_CLIENT_SECRETS names unknown provider "other"`,
+		},
+		{
+			name:          "when_name_is_not_a_path_segment_should_get_error",
+			cfg:           config.OIDCConfig{Providers: "Corp SSO=https://sso.example.com", ClientIDs: "Corp SSO=api"},
+			expectedError: `OIDC_PROVIDERS name "Corp SSO" must be lowercase letters, digits, - or _`,
+		},
+		{
+			name:          "when_issuer_is_not_a_url_should_get_error",
+			cfg:           config.OIDCConfig{Providers: "corp=sso.example.com", ClientIDs: "corp=api"},
+			expectedError: `OIDC_PROVIDERS issuer of provider "corp" must be a URL, got "sso.example.com"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// test logic
+			_, err := oidc.ProvideService(newConfig(tt.cfg), resty.New(), nil, nil, nil, nil)
+			assert.EqualError(t, err, tt.expectedError)
+		})
+	}
+}
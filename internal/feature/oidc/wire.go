@@ -0,0 +1,31 @@
+//go:build wireinject
+// +build wireinject
+
+//go:generate wire
+package oidc
+
+import (
+	"go-fiber-api/internal/core/config"
+	"go-fiber-api/internal/core/model"
+	"go-fiber-api/internal/core/repo"
+	cache_storage "go-fiber-api/internal/core/storage/cache"
+	"go-fiber-api/internal/core/storage/db"
+	"go-fiber-api/internal/feature/auth"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/google/wire"
+)
+
+var ProviderSet = wire.NewSet(
+	ProvideRepository,
+
+	ProvideService,
+
+	ProvideHandler,
+)
+
+func Wire(cfg *config.Configuration, client *resty.Client, cache cache_storage.Cache, dbClient db.Client, users repo.Repo[model.User, model.UserDTO], authSvc auth.Service) (Handler, error) {
+	wire.Build(ProviderSet)
+
+	return &handlerImpl{}, nil
+}
@@ -0,0 +1,143 @@
+package rbac
+
+import (
+	"go-fiber-api/internal/core/model"
+	"go-fiber-api/internal/core/response"
+	"go-fiber-api/toolkit/apperror"
+	"go-fiber-api/toolkit/validate"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Handler is the role management API.
+type Handler interface {
+	FindRoles(c *fiber.Ctx) error
+	CreateRole(c *fiber.Ctx) error
+	UpdateRole(c *fiber.Ctx) error
+	DeleteRole(c *fiber.Ctx) error
+	FindUserRoles(c *fiber.Ctx) error
+	SetUserRoles(c *fiber.Ctx) error
+}
+
+type handlerImpl struct {
+	s Service
+}
+
+func ProvideHandler(s Service) Handler {
+	return &handlerImpl{
+		s: s,
+	}
+}
+
+func (h *handlerImpl) FindRoles(ctx *fiber.Ctx) error {
+	roles, err := h.s.FindRoles(ctx.UserContext())
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(&response.ResponseDTO{
+		Message: "success",
+		Data:    roles,
+	})
+}
+
+func (h *handlerImpl) CreateRole(ctx *fiber.Ctx) error {
+	var dto model.RoleDTO
+	if err := parse(ctx, &dto); err != nil {
+		return err
+	}
+
+	if err := h.s.CreateRole(ctx.UserContext(), &dto); err != nil {
+		return err
+	}
+
+	return ctx.Status(fiber.StatusCreated).JSON(&response.ResponseDTO{
+		Message: "success",
+		Data:    dto,
+	})
+}
+
+func (h *handlerImpl) UpdateRole(ctx *fiber.Ctx) error {
+	id, err := ctx.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return apperror.Validation(apperror.CodeValidation, "invalid role id")
+	}
+
+	var dto model.RoleDTO
+	if err := parse(ctx, &dto); err != nil {
+		return err
+	}
+	dto.ID = uint(id)
+
+	if err := h.s.UpdateRole(ctx.UserContext(), &dto); err != nil {
+		return err
+	}
+
+	return ctx.JSON(&response.ResponseDTO{
+		Message: "success",
+		Data:    dto,
+	})
+}
+
+func (h *handlerImpl) DeleteRole(ctx *fiber.Ctx) error {
+	id, err := ctx.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return apperror.Validation(apperror.CodeValidation, "invalid role id")
+	}
+
+	if err := h.s.DeleteRole(ctx.UserContext(), uint(id)); err != nil {
+		return err
+	}
+
+	return ctx.JSON(&response.ResponseDTO{
+		Message: "success",
+	})
+}
+
+func (h *handlerImpl) FindUserRoles(ctx *fiber.Ctx) error {
+	id, err := ctx.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return apperror.Validation(apperror.CodeValidation, "invalid user id")
+	}
+
+	roles, err := h.s.FindUserRoles(ctx.UserContext(), uint(id))
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(&response.ResponseDTO{
+		Message: "success",
+		Data:    model.UserRolesDTO{Roles: roles},
+	})
+}
+
+func (h *handlerImpl) SetUserRoles(ctx *fiber.Ctx) error {
+	id, err := ctx.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return apperror.Validation(apperror.CodeValidation, "invalid user id")
+	}
+
+	var dto model.UserRolesDTO
+	if err := parse(ctx, &dto); err != nil {
+		return err
+	}
+
+	if err := h.s.SetUserRoles(ctx.UserContext(), uint(id), dto.Roles); err != nil {
+		return err
+	}
+
+	return ctx.JSON(&response.ResponseDTO{
+		Message: "success",
+		Data:    dto,
+	})
+}
+
+// parse reads the body into dto, returning an apperror.Validation error when
+// it is invalid.
+func parse(ctx *fiber.Ctx, dto any) error {
+	if err := ctx.BodyParser(dto); err != nil {
+		return apperror.Validation(apperror.CodeValidation, "invalid request body").Wrap(err)
+	}
+
+	return validate.Struct(dto)
+}
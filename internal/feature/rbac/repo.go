@@ -0,0 +1,158 @@
+package rbac
+
+import (
+	"context"
+	"go-fiber-api/internal/core/model"
+	"go-fiber-api/internal/core/storage/db"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Repository stores the roles, their permissions and their assignments to
+// users.
+type Repository interface {
+	FindRoles(ctx context.Context) ([]model.Role, error)
+	FindRole(ctx context.Context, id uint) (model.Role, error)
+	FindRolesByName(ctx context.Context, names []string) ([]model.Role, error)
+	// SaveRole creates or updates role, replacing its permissions by
+	// permissions, created when missing.
+	SaveRole(ctx context.Context, role *model.Role, permissions []string) error
+	// DeleteRole deletes the role of id, unassigning it from its users.
+	DeleteRole(ctx context.Context, id uint) error
+
+	FindUser(ctx context.Context, userID uint) (model.User, error)
+	// SetUserRoles replaces the roles of user by roles.
+	SetUserRoles(ctx context.Context, user *model.User, roles []model.Role) error
+	FindUserIDsByRole(ctx context.Context, roleID uint) ([]uint, error)
+	// FindPermissions returns the names of the permissions granted to the
+	// user of userID by all of their roles.
+	FindPermissions(ctx context.Context, userID uint) ([]string, error)
+	// IsGranted reports whether a user has one of permissions.
+	IsGranted(ctx context.Context, permissions []string) (bool, error)
+}
+
+type repoImpl struct {
+	db db.Client
+}
+
+func ProvideRepository(db db.Client) Repository {
+	return &repoImpl{
+		db: db,
+	}
+}
+
+func (r *repoImpl) FindRoles(ctx context.Context) ([]model.Role, error) {
+	var roles []model.Role
+	err := r.db.WithContext(ctx).Preload("Permissions").Order("name").Find(&roles).Error
+
+	return roles, err
+}
+
+func (r *repoImpl) FindRole(ctx context.Context, id uint) (model.Role, error) {
+	var role model.Role
+	err := r.db.WithContext(ctx).Preload("Permissions").First(&role, id).Error
+
+	return role, err
+}
+
+func (r *repoImpl) FindRolesByName(ctx context.Context, names []string) ([]model.Role, error) {
+	var roles []model.Role
+	err := r.db.WithContext(ctx).Where("name IN ?", names).Find(&roles).Error
+
+	return roles, err
+}
+
+func (r *repoImpl) SaveRole(ctx context.Context, role *model.Role, permissions []string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		perms := make([]model.Permission, 0, len(permissions))
+		for _, name := range permissions {
+			perms = append(perms, model.Permission{Name: name})
+		}
+		if len(perms) > 0 {
+			if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&perms).Error; err != nil {
+				return err
+			}
+			// Existing permissions were skipped without getting their ID.
+			if err := tx.Where("name IN ?", permissions).Find(&perms).Error; err != nil {
+				return err
+			}
+		}
+
+		role.Permissions = nil
+		if err := tx.Omit(clause.Associations).Save(role).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(role).Association("Permissions").Replace(perms); err != nil {
+			return err
+		}
+		role.Permissions = perms
+
+		return nil
+	})
+}
+
+func (r *repoImpl) DeleteRole(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		role := model.Role{Base: model.Base{ID: id}}
+		if err := tx.Model(&role).Association("Permissions").Clear(); err != nil {
+			return err
+		}
+		if err := tx.Table("user_roles").Where("role_id = ?", id).Delete(nil).Error; err != nil {
+			return err
+		}
+
+		// Deleted for good, so that its name can be used again.
+		tx = tx.Unscoped().Delete(&role)
+		if tx.Error == nil && tx.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return tx.Error
+	})
+}
+
+func (r *repoImpl) FindUser(ctx context.Context, userID uint) (model.User, error) {
+	var user model.User
+	err := r.db.WithContext(ctx).Preload("Roles", func(tx *gorm.DB) *gorm.DB {
+		return tx.Order("name")
+	}).First(&user, userID).Error
+
+	return user, err
+}
+
+func (r *repoImpl) SetUserRoles(ctx context.Context, user *model.User, roles []model.Role) error {
+	return r.db.WithContext(ctx).Model(user).Association("Roles").Replace(roles)
+}
+
+func (r *repoImpl) FindUserIDsByRole(ctx context.Context, roleID uint) ([]uint, error) {
+	var ids []uint
+	err := r.db.WithContext(ctx).Table("user_roles").Where("role_id = ?", roleID).Pluck("user_id", &ids).Error
+
+	return ids, err
+}
+
+func (r *repoImpl) FindPermissions(ctx context.Context, userID uint) ([]string, error) {
+	var names []string
+	err := r.db.WithContext(ctx).Model(&model.Permission{}).
+		Distinct("permissions.name").
+		Joins("JOIN role_permissions ON role_permissions.permission_id = permissions.id").
+		Joins("JOIN user_roles ON user_roles.role_id = role_permissions.role_id").
+		Joins("JOIN roles ON roles.id = user_roles.role_id AND roles.deleted_at IS NULL").
+		Where("user_roles.user_id = ?", userID).
+		Order("permissions.name").
+		Pluck("permissions.name", &names).Error
+
+	return names, err
+}
+
+func (r *repoImpl) IsGranted(ctx context.Context, permissions []string) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Table("user_roles").
+		Joins("JOIN roles ON roles.id = user_roles.role_id AND roles.deleted_at IS NULL").
+		Joins("JOIN role_permissions ON role_permissions.role_id = user_roles.role_id").
+		Joins("JOIN permissions ON permissions.id = role_permissions.permission_id").
+		Where("permissions.name IN ?", permissions).
+		Count(&count).Error
+
+	return count > 0, err
+}
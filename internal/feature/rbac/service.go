@@ -0,0 +1,315 @@
+//go:generate mockgen -source=service.go -mock_names=Service=MockRBACService -destination=../../mock/mock_rbac_service.go -package=mock
+package rbac
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-fiber-api/internal/core/config"
+	"go-fiber-api/internal/core/model"
+	cache_storage "go-fiber-api/internal/core/storage/cache"
+	"go-fiber-api/internal/wrapper/logx"
+	"go-fiber-api/toolkit/apperror"
+	"slices"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	// PermissionAll grants every permission.
+	PermissionAll = "*"
+	// PermissionRolesManage grants the role management API.
+	PermissionRolesManage = "roles:manage"
+	// PermissionAdmin grants the administration API under /admin.
+	PermissionAdmin = "admin"
+)
+
+const (
+	// policyKey is the cached policy of a user at a generation of
+	// generationKey, replaced whenever their policy changes: a policy read
+	// from the database before a change is cached under a generation that
+	// is no longer read.
+	policyKey     = "rbac:policy:%d:%s"
+	generationKey = "rbac:policy:%d"
+)
+
+var (
+	ErrRoleNotFound = apperror.NotFound("ROLE_NOT_FOUND", "role not found")
+	ErrRoleExists   = apperror.Conflict("ROLE_EXISTS", "role already exists")
+	ErrUserNotFound = apperror.NotFound("USER_NOT_FOUND", "user not found")
+	ErrUnknownRole  = apperror.Validation("UNKNOWN_ROLE", "unknown role")
+)
+
+type Service interface {
+	FindRoles(ctx context.Context) ([]model.RoleDTO, error)
+	CreateRole(ctx context.Context, dto *model.RoleDTO) error
+	UpdateRole(ctx context.Context, dto *model.RoleDTO) error
+	DeleteRole(ctx context.Context, id uint) error
+
+	FindUserRoles(ctx context.Context, userID uint) ([]string, error)
+	SetUserRoles(ctx context.Context, userID uint, roles []string) error
+
+	// Permissions returns the permissions of the user of userID, cached
+	// until their roles, or the permissions of these, change.
+	Permissions(ctx context.Context, userID uint) ([]string, error)
+	// HasPermissions reports whether the user of userID has every one of
+	// permissions.
+	HasPermissions(ctx context.Context, userID uint, permissions ...string) (bool, error)
+	// IsGranted reports whether any user has permission, or every
+	// permission. Unlike the policies, it is never cached.
+	IsGranted(ctx context.Context, permission string) (bool, error)
+}
+
+type serviceImpl struct {
+	repo  Repository
+	cache cache_storage.Cache
+	ttl   int // seconds
+}
+
+func ProvideService(cfg *config.Configuration, repo Repository, cache cache_storage.Cache) Service {
+	return &serviceImpl{
+		repo:  repo,
+		cache: cache,
+		ttl:   cfg.RBACCacheTTL,
+	}
+}
+
+func (s *serviceImpl) FindRoles(ctx context.Context) ([]model.RoleDTO, error) {
+	roles, err := s.repo.FindRoles(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]model.RoleDTO, 0, len(roles))
+	for _, role := range roles {
+		res = append(res, role.ToDTO())
+	}
+
+	return res, nil
+}
+
+func (s *serviceImpl) CreateRole(ctx context.Context, dto *model.RoleDTO) error {
+	if err := s.checkName(ctx, dto.Name, 0); err != nil {
+		return err
+	}
+
+	role := model.Role{Name: dto.Name, Description: dto.Description}
+	if err := s.repo.SaveRole(ctx, &role, dto.Permissions); err != nil {
+		logx.FromContext(ctx).Errorf("rbac service create role: %v", err)
+		return err
+	}
+
+	*dto = role.ToDTO()
+	return nil
+}
+
+func (s *serviceImpl) UpdateRole(ctx context.Context, dto *model.RoleDTO) error {
+	role, err := s.repo.FindRole(ctx, dto.ID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrRoleNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := s.checkName(ctx, dto.Name, role.ID); err != nil {
+		return err
+	}
+
+	role.Name, role.Description = dto.Name, dto.Description
+	if err := s.repo.SaveRole(ctx, &role, dto.Permissions); err != nil {
+		logx.FromContext(ctx).Errorf("rbac service update role: %v", err)
+		return err
+	}
+	s.invalidateRole(ctx, role.ID)
+
+	*dto = role.ToDTO()
+	return nil
+}
+
+func (s *serviceImpl) DeleteRole(ctx context.Context, id uint) error {
+	// Read first, the assignments are gone with the role.
+	userIDs, err := s.repo.FindUserIDsByRole(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	err = s.repo.DeleteRole(ctx, id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrRoleNotFound
+	}
+	if err != nil {
+		return err
+	}
+	s.invalidate(ctx, userIDs...)
+
+	return nil
+}
+
+func (s *serviceImpl) FindUserRoles(ctx context.Context, userID uint) ([]string, error) {
+	user, err := s.repo.FindUser(ctx, userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrUserNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(user.Roles))
+	for _, role := range user.Roles {
+		names = append(names, role.Name)
+	}
+
+	return names, nil
+}
+
+func (s *serviceImpl) SetUserRoles(ctx context.Context, userID uint, names []string) error {
+	user, err := s.repo.FindUser(ctx, userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrUserNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	roles, err := s.repo.FindRolesByName(ctx, names)
+	if err != nil {
+		return err
+	}
+	for _, name := range names {
+		if !slices.ContainsFunc(roles, func(role model.Role) bool { return role.Name == name }) {
+			return apperror.Validation(ErrUnknownRole.Code, fmt.Sprintf("%s %q", ErrUnknownRole.Message, name))
+		}
+	}
+
+	if err := s.repo.SetUserRoles(ctx, &user, roles); err != nil {
+		logx.FromContext(ctx).Errorf("rbac service set roles of user %d: %v", userID, err)
+		return err
+	}
+	s.invalidate(ctx, userID)
+
+	return nil
+}
+
+func (s *serviceImpl) Permissions(ctx context.Context, userID uint) ([]string, error) {
+	// Read before the database, so a change committed since then has
+	// replaced it.
+	generation, err := s.generation(ctx, userID)
+	if err != nil {
+		// Served from the database until the cache is back.
+		logx.FromContext(ctx).Warnf("rbac service get policy generation of user %d: %v", userID, err)
+		return s.findPermissions(ctx, userID)
+	}
+	key := fmt.Sprintf(policyKey, userID, generation)
+
+	var permissions []string
+	err = s.cache.Get(ctx, key, &permissions)
+	switch {
+	case err == nil:
+		return permissions, nil
+	case !errors.Is(err, cache_storage.ErrMiss):
+		logx.FromContext(ctx).Warnf("rbac service get policy of user %d: %v", userID, err)
+	}
+
+	permissions, err = s.findPermissions(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.cache.Set(ctx, key, permissions, s.ttl); err != nil {
+		logx.FromContext(ctx).Warnf("rbac service set policy of user %d: %v", userID, err)
+	}
+
+	return permissions, nil
+}
+
+func (s *serviceImpl) findPermissions(ctx context.Context, userID uint) ([]string, error) {
+	permissions, err := s.repo.FindPermissions(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if permissions == nil {
+		// Cached as [] rather than null, which would not be told from a miss.
+		permissions = []string{}
+	}
+
+	return permissions, nil
+}
+
+// generation returns the policy generation of the user of userID, starting
+// a new one when there is none.
+func (s *serviceImpl) generation(ctx context.Context, userID uint) (string, error) {
+	key := fmt.Sprintf(generationKey, userID)
+
+	var generation string
+	err := s.cache.Get(ctx, key, &generation)
+	if !errors.Is(err, cache_storage.ErrMiss) {
+		return generation, err
+	}
+
+	generation = uuid.NewString()
+	if err := s.cache.Set(ctx, key, generation, s.ttl); err != nil {
+		return "", err
+	}
+
+	return generation, nil
+}
+
+func (s *serviceImpl) HasPermissions(ctx context.Context, userID uint, permissions ...string) (bool, error) {
+	granted, err := s.Permissions(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+
+	if slices.Contains(granted, PermissionAll) {
+		return true, nil
+	}
+	for _, permission := range permissions {
+		if !slices.Contains(granted, permission) {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+func (s *serviceImpl) IsGranted(ctx context.Context, permission string) (bool, error) {
+	return s.repo.IsGranted(ctx, []string{permission, PermissionAll})
+}
+
+// checkName returns ErrRoleExists when name is the one of another role than
+// the one of id.
+func (s *serviceImpl) checkName(ctx context.Context, name string, id uint) error {
+	roles, err := s.repo.FindRolesByName(ctx, []string{name})
+	if err != nil {
+		return err
+	}
+	if len(roles) > 0 && roles[0].ID != id {
+		return ErrRoleExists
+	}
+
+	return nil
+}
+
+func (s *serviceImpl) invalidateRole(ctx context.Context, roleID uint) {
+	userIDs, err := s.repo.FindUserIDsByRole(ctx, roleID)
+	if err != nil {
+		logx.FromContext(ctx).Errorf("rbac service find users of role %d: %v", roleID, err)
+		return
+	}
+
+	s.invalidate(ctx, userIDs...)
+}
+
+// invalidate starts a new policy generation of the users of userIDs, once
+// their change is committed. A policy that can't be invalidated expires after
+// RBAC_CACHE_TTL.
+func (s *serviceImpl) invalidate(ctx context.Context, userIDs ...uint) {
+	for _, userID := range userIDs {
+		key := fmt.Sprintf(generationKey, userID)
+		if err := s.cache.Set(ctx, key, uuid.NewString(), s.ttl); err != nil {
+			logx.FromContext(ctx).Errorf("rbac service invalidate policy of user %d: %v", userID, err)
+		}
+	}
+}
@@ -0,0 +1,341 @@
+package rbac_test
+
+import (
+	"context"
+	"errors"
+	"go-fiber-api/internal/core/config"
+	"go-fiber-api/internal/core/model"
+	cache_storage "go-fiber-api/internal/core/storage/cache"
+	"go-fiber-api/internal/core/storage/db"
+	"go-fiber-api/internal/feature/rbac"
+	"go-fiber-api/internal/mock"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+)
+
+type fixture struct {
+	db    *gorm.DB
+	cache cache_storage.Cache
+	s     rbac.Service
+}
+
+func newFixture(t *testing.T, cache cache_storage.Cache) fixture {
+	client, err := db.GetDbTestMode()
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	if cache == nil {
+		cache, err = cache_storage.NewMemory(100)
+		assert.NoError(t, err)
+	}
+
+	s := rbac.ProvideService(&config.Configuration{RBACCacheTTL: 60}, rbac.ProvideRepository(client), cache)
+	return fixture{db: client, cache: cache, s: s}
+}
+
+func (f fixture) user(t *testing.T, username string) uint {
+	u := model.User{Username: username, Status: model.UserStatusNormal}
+	assert.NoError(t, f.db.Create(&u).Error)
+	return u.ID
+}
+
+func (f fixture) role(t *testing.T, name string, permissions ...string) model.RoleDTO {
+	dto := model.RoleDTO{Name: name, Permissions: permissions}
+	assert.NoError(t, f.s.CreateRole(context.Background(), &dto))
+	return dto
+}
+
+func Test_RBAC_serviceImpl_Roles(t *testing.T) {
+	ctx := context.Background()
+	f := newFixture(t, nil)
+	editor := f.role(t, "editor", "posts:write", "posts:read")
+	f.role(t, "viewer", "posts:read")
+
+	t.Run("when_role_is_created_should_share_permissions", func(t *testing.T) {
+		roles, err := f.s.FindRoles(ctx)
+		assert.NoError(t, err)
+		assert.Len(t, roles, 2)
+		assert.Equal(t, "editor", roles[0].Name)
+		assert.ElementsMatch(t, []string{"posts:write", "posts:read"}, roles[0].Permissions)
+
+		var count int64
+		assert.NoError(t, f.db.Model(&model.Permission{}).Count(&count).Error)
+		assert.Equal(t, int64(2), count)
+	})
+
+	t.Run("when_name_is_taken_should_get_error", func(t *testing.T) {
+		// test logic
+		err := f.s.CreateRole(ctx, &model.RoleDTO{Name: "viewer"})
+		assert.ErrorIs(t, err, rbac.ErrRoleExists)
+
+		err = f.s.UpdateRole(ctx, &model.RoleDTO{ID: editor.ID, Name: "viewer"})
+		assert.ErrorIs(t, err, rbac.ErrRoleExists)
+	})
+
+	t.Run("when_role_is_updated_should_replace_permissions", func(t *testing.T) {
+		dto := model.RoleDTO{ID: editor.ID, Name: "writer", Description: "writes", Permissions: []string{"posts:write"}}
+
+		// test logic
+		assert.NoError(t, f.s.UpdateRole(ctx, &dto))
+		assert.Equal(t, model.RoleDTO{ID: editor.ID, Name: "writer", Description: "writes", Permissions: []string{"posts:write"}}, dto)
+	})
+
+	t.Run("when_role_is_unknown_should_get_error", func(t *testing.T) {
+		// test logic
+		assert.ErrorIs(t, f.s.UpdateRole(ctx, &model.RoleDTO{ID: 999, Name: "x"}), rbac.ErrRoleNotFound)
+		assert.ErrorIs(t, f.s.DeleteRole(ctx, 999), rbac.ErrRoleNotFound)
+	})
+
+	t.Run("when_role_is_deleted_should_free_its_name", func(t *testing.T) {
+		// test logic
+		assert.NoError(t, f.s.DeleteRole(ctx, editor.ID))
+		f.role(t, "writer")
+	})
+}
+
+func Test_RBAC_serviceImpl_UserRoles(t *testing.T) {
+	ctx := context.Background()
+	f := newFixture(t, nil)
+	alice := f.user(t, "alice")
+	f.role(t, "editor", "posts:write")
+	f.role(t, "viewer", "posts:read")
+
+	tests := []struct {
+		name          string
+		userID        uint
+		roles         []string
+		expectedRoles []string
+		expectedError error
+	}{
+		{
+			name:          "when_roles_exist_should_assign_them",
+			userID:        alice,
+			roles:         []string{"viewer", "editor"},
+			expectedRoles: []string{"editor", "viewer"},
+		},
+		{
+			name:          "when_roles_are_replaced_should_unassign_others",
+			userID:        alice,
+			roles:         []string{"viewer"},
+			expectedRoles: []string{"viewer"},
+		},
+		{
+			name:          "when_role_is_unknown_should_get_error",
+			userID:        alice,
+			roles:         []string{"viewer", "owner"},
+			expectedRoles: []string{"viewer"},
+			expectedError: rbac.ErrUnknownRole,
+		},
+		{
+			name:          "when_user_is_unknown_should_get_error",
+			userID:        999,
+			roles:         []string{"viewer"},
+			expectedError: rbac.ErrUserNotFound,
+		},
+		{
+			name:          "when_roles_are_empty_should_unassign_all",
+			userID:        alice,
+			roles:         []string{},
+			expectedRoles: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// test logic
+			err := f.s.SetUserRoles(ctx, tt.userID, tt.roles)
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			if tt.expectedRoles != nil {
+				roles, err := f.s.FindUserRoles(ctx, tt.userID)
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedRoles, roles)
+			}
+		})
+	}
+}
+
+func Test_RBAC_serviceImpl_HasPermissions(t *testing.T) {
+	ctx := context.Background()
+	f := newFixture(t, nil)
+	alice := f.user(t, "alice")
+	bob := f.user(t, "bob")
+	editor := f.role(t, "editor", "posts:write", "posts:read")
+	f.role(t, "admin", rbac.PermissionAll)
+
+	has := func(userID uint, permissions ...string) bool {
+		ok, err := f.s.HasPermissions(ctx, userID, permissions...)
+		assert.NoError(t, err)
+		return ok
+	}
+
+	assert.False(t, has(alice, "posts:read"), "no role, no permission")
+	assert.True(t, has(alice), "nothing required")
+
+	t.Run("when_roles_are_assigned_should_invalidate_policy", func(t *testing.T) {
+		// test logic
+		assert.NoError(t, f.s.SetUserRoles(ctx, alice, []string{"editor"}))
+		assert.True(t, has(alice, "posts:read", "posts:write"))
+		assert.False(t, has(alice, "posts:read", "users:delete"))
+	})
+
+	t.Run("when_role_permissions_change_should_invalidate_policy_of_its_users", func(t *testing.T) {
+		// test logic
+		assert.NoError(t, f.s.UpdateRole(ctx, &model.RoleDTO{ID: editor.ID, Name: "editor", Permissions: []string{"posts:read"}}))
+		assert.True(t, has(alice, "posts:read"))
+		assert.False(t, has(alice, "posts:write"))
+	})
+
+	t.Run("when_role_is_deleted_should_invalidate_policy_of_its_users", func(t *testing.T) {
+		// test logic
+		assert.NoError(t, f.s.DeleteRole(ctx, editor.ID))
+		assert.False(t, has(alice, "posts:read"))
+	})
+
+	t.Run("when_role_grants_all_should_have_any_permission", func(t *testing.T) {
+		// test logic
+		assert.NoError(t, f.s.SetUserRoles(ctx, bob, []string{"admin"}))
+		assert.True(t, has(bob, "posts:read", rbac.PermissionRolesManage))
+	})
+
+	t.Run("when_policy_is_cached_should_not_query_database", func(t *testing.T) {
+		assert.True(t, has(bob, "posts:read"))
+		assert.NoError(t, f.db.Exec("DELETE FROM user_roles").Error)
+
+		// test logic
+		assert.True(t, has(bob, "posts:read"), "served from the cache until invalidated")
+	})
+}
+
+func Test_RBAC_serviceImpl_IsGranted(t *testing.T) {
+	ctx := context.Background()
+	f := newFixture(t, nil)
+	alice := f.user(t, "alice")
+	bob := f.user(t, "bob")
+	f.role(t, "editor", "posts:write")
+	f.role(t, "root", rbac.PermissionAll)
+	admin := f.role(t, "admin", rbac.PermissionAdmin)
+
+	granted := func() bool {
+		ok, err := f.s.IsGranted(ctx, rbac.PermissionAdmin)
+		assert.NoError(t, err)
+		return ok
+	}
+
+	assert.False(t, granted(), "a role nobody has grants nothing")
+
+	tests := []struct {
+		name     string
+		userID   uint
+		roles    []string
+		expected bool
+	}{
+		{
+			name:     "when_no_user_has_permission_should_get_false",
+			userID:   alice,
+			roles:    []string{"editor"},
+			expected: false,
+		},
+		{
+			name:     "when_a_user_has_permission_should_get_true",
+			userID:   alice,
+			roles:    []string{"admin"},
+			expected: true,
+		},
+		{
+			name:     "when_a_user_has_every_permission_should_get_true",
+			userID:   bob,
+			roles:    []string{"root"},
+			expected: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.NoError(t, f.db.Exec("DELETE FROM user_roles").Error)
+			assert.NoError(t, f.s.SetUserRoles(ctx, tt.userID, tt.roles))
+
+			// test logic
+			assert.Equal(t, tt.expected, granted())
+		})
+	}
+
+	t.Run("when_role_is_deleted_should_get_false", func(t *testing.T) {
+		assert.NoError(t, f.db.Exec("DELETE FROM user_roles").Error)
+		assert.NoError(t, f.s.SetUserRoles(ctx, alice, []string{"admin"}))
+
+		// test logic
+		assert.NoError(t, f.s.DeleteRole(ctx, admin.ID))
+		assert.False(t, granted())
+	})
+}
+
+// racingCache runs beforeFill once, before the first policy is cached.
+type racingCache struct {
+	cache_storage.Cache
+	beforeFill func()
+}
+
+func (c *racingCache) Set(ctx context.Context, key string, value any, ttl ...int) error {
+	if _, ok := value.([]string); ok && strings.HasPrefix(key, "rbac:policy:") && c.beforeFill != nil {
+		fill := c.beforeFill
+		c.beforeFill = nil
+		fill()
+	}
+
+	return c.Cache.Set(ctx, key, value, ttl...)
+}
+
+func Test_RBAC_serviceImpl_Permissions_Race(t *testing.T) {
+	ctx := context.Background()
+	memory, err := cache_storage.NewMemory(100)
+	assert.NoError(t, err)
+	cache := &racingCache{Cache: memory}
+
+	f := newFixture(t, cache)
+	alice := f.user(t, "alice")
+	f.role(t, "editor", "posts:write")
+	assert.NoError(t, f.s.SetUserRoles(ctx, alice, []string{"editor"}))
+
+	// The role is revoked between the read of the policy and its caching.
+	cache.beforeFill = func() {
+		assert.NoError(t, f.s.SetUserRoles(ctx, alice, nil))
+	}
+	permissions, err := f.s.Permissions(ctx, alice)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"posts:write"}, permissions)
+
+	// test logic
+	permissions, err = f.s.Permissions(ctx, alice)
+	assert.NoError(t, err)
+	assert.Empty(t, permissions, "the policy read before the revocation is not served")
+}
+
+func Test_RBAC_serviceImpl_Permissions_CacheDown(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cache := mock.NewMockCache(ctrl)
+	cache.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.Any()).Return(errors.New("dial tcp: connection refused")).AnyTimes()
+	cache.EXPECT().Set(gomock.Any(), gomock.Any(), gomock.Any(), 60).Return(errors.New("dial tcp: connection refused")).AnyTimes()
+	cache.EXPECT().Del(gomock.Any(), gomock.Any()).Return(errors.New("dial tcp: connection refused")).AnyTimes()
+
+	f := newFixture(t, cache)
+	alice := f.user(t, "alice")
+	f.role(t, "viewer", "posts:read")
+	assert.NoError(t, f.s.SetUserRoles(context.Background(), alice, []string{"viewer"}))
+
+	// test logic
+	permissions, err := f.s.Permissions(context.Background(), alice)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"posts:read"}, permissions)
+}
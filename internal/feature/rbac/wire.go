@@ -0,0 +1,27 @@
+//go:build wireinject
+// +build wireinject
+
+//go:generate wire
+package rbac
+
+import (
+	"go-fiber-api/internal/core/config"
+	cache_storage "go-fiber-api/internal/core/storage/cache"
+	"go-fiber-api/internal/core/storage/db"
+
+	"github.com/google/wire"
+)
+
+var ProviderSet = wire.NewSet(
+	ProvideRepository,
+
+	ProvideService,
+
+	ProvideHandler,
+)
+
+func Wire(cfg *config.Configuration, client db.Client, cache cache_storage.Cache) (Handler, error) {
+	wire.Build(ProviderSet)
+
+	return &handlerImpl{}, nil
+}
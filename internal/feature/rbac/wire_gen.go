@@ -0,0 +1,33 @@
+// Code generated by Wire. DO NOT EDIT.
+
+//go:generate go run -mod=mod github.com/google/wire/cmd/wire
+//go:build !wireinject
+// +build !wireinject
+
+package rbac
+
+import (
+	"github.com/google/wire"
+	"go-fiber-api/internal/core/config"
+	"go-fiber-api/internal/core/storage/cache"
+	"go-fiber-api/internal/core/storage/db"
+)
+
+// Injectors from wire.go:
+
+func Wire(cfg *config.Configuration, client db.Client, cache2 cache.Cache) (Handler, error) {
+	repository := ProvideRepository(client)
+	service := ProvideService(cfg, repository, cache2)
+	handler := ProvideHandler(service)
+	return handler, nil
+}
+
+// wire.go:
+
+var ProviderSet = wire.NewSet(
+	ProvideRepository,
+
+	ProvideService,
+
+	ProvideHandler,
+)
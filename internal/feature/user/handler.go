@@ -1,15 +1,14 @@
 package user
 
 import (
-	"sync"
-)
+	auth_middleware "go-fiber-api/internal/core/middleware/auth"
+	"go-fiber-api/internal/core/response"
 
-var (
-	h     *handlerImpl
-	hOnce sync.Once
+	"github.com/gofiber/fiber/v2"
 )
 
 type Handler interface {
+	Me(c *fiber.Ctx) error
 	// Get(c *fiber.Ctx) error
 	// GetByID(c *fiber.Ctx) error
 	// Create(c *fiber.Ctx) error
@@ -22,17 +21,22 @@ type handlerImpl struct {
 }
 
 func ProvideHandler(s Service) Handler {
-	hOnce.Do(func() {
-		h = &handlerImpl{
-			s: s,
-		}
-	})
-
-	return h
+	return &handlerImpl{
+		s: s,
+	}
 }
 
-func ResetProvideHandler() {
-	hOnce = sync.Once{}
+// Me returns the user authenticated by the auth middleware.
+func (c *handlerImpl) Me(ctx *fiber.Ctx) error {
+	user, ok := auth_middleware.User(ctx)
+	if !ok {
+		return fiber.ErrUnauthorized
+	}
+
+	return ctx.JSON(&response.ResponseDTO{
+		Message: "success",
+		Data:    user,
+	})
 }
 
 // func (c *handlerImpl) Create(ctx *fiber.Ctx) error {
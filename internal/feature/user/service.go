@@ -7,14 +7,7 @@ import (
 	"errors"
 	"go-fiber-api/internal/core/model"
 	"go-fiber-api/internal/core/repo"
-	"sync"
-
-	"github.com/sirupsen/logrus"
-)
-
-var (
-	sOnce sync.Once
-	s     *serviceImpl
+	"go-fiber-api/internal/wrapper/logx"
 )
 
 type Service interface {
@@ -30,16 +23,9 @@ type serviceImpl struct {
 }
 
 func ProvideService(repo repo.Repo[model.User, model.UserDTO]) Service {
-	sOnce.Do(func() {
-		s = &serviceImpl{
-			repo: repo,
-		}
-	})
-	return s
-}
-
-func ResetProvideService() {
-	sOnce = sync.Once{}
+	return &serviceImpl{
+		repo: repo,
+	}
 }
 
 func (s *serviceImpl) Create(ctx context.Context, dto *model.UserDTO) error {
@@ -49,7 +35,7 @@ func (s *serviceImpl) Create(ctx context.Context, dto *model.UserDTO) error {
 
 	err := s.repo.Insert(ctx, dto)
 	if err != nil {
-		logrus.Errorf("game service create: %v", err)
+		logx.FromContext(ctx).Errorf("game service create: %v", err)
 		return err
 	}
 
@@ -60,7 +46,7 @@ func (s *serviceImpl) FindAll(ctx context.Context) ([]model.UserDTO, error) {
 	// Define the scan input
 	data, err := s.repo.FindAll(ctx)
 	if err != nil {
-		logrus.Errorf("game service get: %v", err)
+		logx.FromContext(ctx).Errorf("game service get: %v", err)
 		return nil, err
 	}
 
@@ -70,7 +56,7 @@ func (s *serviceImpl) FindAll(ctx context.Context) ([]model.UserDTO, error) {
 func (s *serviceImpl) FindByID(ctx context.Context, id string) (model.UserDTO, error) {
 	res, err := s.repo.FindByID(ctx, id)
 	if err != nil {
-		logrus.Errorf("game service get by: %v", err)
+		logx.FromContext(ctx).Errorf("game service get by: %v", err)
 		return model.UserDTO{}, err
 	}
 
@@ -89,7 +75,7 @@ func (s *serviceImpl) Update(ctx context.Context, dto *model.UserDTO) error {
 func (s *serviceImpl) DeleteByID(ctx context.Context, id string) error {
 	err := s.repo.DeleteById(ctx, id)
 	if err != nil {
-		logrus.Errorf("game service delete: %v", err)
+		logx.FromContext(ctx).Errorf("game service delete: %v", err)
 		return err
 	}
 
@@ -0,0 +1,99 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: guard.go
+//
+// Generated by this command:
+//
+//	mockgen -source=guard.go -mock_names=Guard=MockGuard -destination=../../mock/mock_guard.go -package=mock
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockGuard is a mock of Guard interface.
+type MockGuard struct {
+	ctrl     *gomock.Controller
+	recorder *MockGuardMockRecorder
+	isgomock struct{}
+}
+
+// MockGuardMockRecorder is the mock recorder for MockGuard.
+type MockGuardMockRecorder struct {
+	mock *MockGuard
+}
+
+// NewMockGuard creates a new mock instance.
+func NewMockGuard(ctrl *gomock.Controller) *MockGuard {
+	mock := &MockGuard{ctrl: ctrl}
+	mock.recorder = &MockGuardMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockGuard) EXPECT() *MockGuardMockRecorder {
+	return m.recorder
+}
+
+// Ban mocks base method.
+func (m *MockGuard) Ban(ctx context.Context, key string, ttl time.Duration) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Ban", ctx, key, ttl)
+}
+
+// Ban indicates an expected call of Ban.
+func (mr *MockGuardMockRecorder) Ban(ctx, key, ttl any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Ban", reflect.TypeOf((*MockGuard)(nil).Ban), ctx, key, ttl)
+}
+
+// Check mocks base method.
+func (m *MockGuard) Check(ctx context.Context, keys ...string) error {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx}
+	for _, a := range keys {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Check", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Check indicates an expected call of Check.
+func (mr *MockGuardMockRecorder) Check(ctx any, keys ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx}, keys...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Check", reflect.TypeOf((*MockGuard)(nil).Check), varargs...)
+}
+
+// Fail mocks base method.
+func (m *MockGuard) Fail(ctx context.Context, key string) int64 {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Fail", ctx, key)
+	ret0, _ := ret[0].(int64)
+	return ret0
+}
+
+// Fail indicates an expected call of Fail.
+func (mr *MockGuardMockRecorder) Fail(ctx, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Fail", reflect.TypeOf((*MockGuard)(nil).Fail), ctx, key)
+}
+
+// Reset mocks base method.
+func (m *MockGuard) Reset(ctx context.Context, key string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Reset", ctx, key)
+}
+
+// Reset indicates an expected call of Reset.
+func (mr *MockGuardMockRecorder) Reset(ctx, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reset", reflect.TypeOf((*MockGuard)(nil).Reset), ctx, key)
+}
@@ -0,0 +1,178 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: service.go
+//
+// Generated by this command:
+//
+//	mockgen -source=service.go -mock_names=Service=MockRBACService -destination=../../mock/mock_rbac_service.go -package=mock
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	model "go-fiber-api/internal/core/model"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockRBACService is a mock of Service interface.
+type MockRBACService struct {
+	ctrl     *gomock.Controller
+	recorder *MockRBACServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockRBACServiceMockRecorder is the mock recorder for MockRBACService.
+type MockRBACServiceMockRecorder struct {
+	mock *MockRBACService
+}
+
+// NewMockRBACService creates a new mock instance.
+func NewMockRBACService(ctrl *gomock.Controller) *MockRBACService {
+	mock := &MockRBACService{ctrl: ctrl}
+	mock.recorder = &MockRBACServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRBACService) EXPECT() *MockRBACServiceMockRecorder {
+	return m.recorder
+}
+
+// CreateRole mocks base method.
+func (m *MockRBACService) CreateRole(ctx context.Context, dto *model.RoleDTO) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateRole", ctx, dto)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateRole indicates an expected call of CreateRole.
+func (mr *MockRBACServiceMockRecorder) CreateRole(ctx, dto any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRole", reflect.TypeOf((*MockRBACService)(nil).CreateRole), ctx, dto)
+}
+
+// DeleteRole mocks base method.
+func (m *MockRBACService) DeleteRole(ctx context.Context, id uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteRole", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteRole indicates an expected call of DeleteRole.
+func (mr *MockRBACServiceMockRecorder) DeleteRole(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteRole", reflect.TypeOf((*MockRBACService)(nil).DeleteRole), ctx, id)
+}
+
+// FindRoles mocks base method.
+func (m *MockRBACService) FindRoles(ctx context.Context) ([]model.RoleDTO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindRoles", ctx)
+	ret0, _ := ret[0].([]model.RoleDTO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindRoles indicates an expected call of FindRoles.
+func (mr *MockRBACServiceMockRecorder) FindRoles(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindRoles", reflect.TypeOf((*MockRBACService)(nil).FindRoles), ctx)
+}
+
+// FindUserRoles mocks base method.
+func (m *MockRBACService) FindUserRoles(ctx context.Context, userID uint) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindUserRoles", ctx, userID)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindUserRoles indicates an expected call of FindUserRoles.
+func (mr *MockRBACServiceMockRecorder) FindUserRoles(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindUserRoles", reflect.TypeOf((*MockRBACService)(nil).FindUserRoles), ctx, userID)
+}
+
+// HasPermissions mocks base method.
+func (m *MockRBACService) HasPermissions(ctx context.Context, userID uint, permissions ...string) (bool, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, userID}
+	for _, a := range permissions {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "HasPermissions", varargs...)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HasPermissions indicates an expected call of HasPermissions.
+func (mr *MockRBACServiceMockRecorder) HasPermissions(ctx, userID any, permissions ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, userID}, permissions...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HasPermissions", reflect.TypeOf((*MockRBACService)(nil).HasPermissions), varargs...)
+}
+
+// IsGranted mocks base method.
+func (m *MockRBACService) IsGranted(ctx context.Context, permission string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsGranted", ctx, permission)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsGranted indicates an expected call of IsGranted.
+func (mr *MockRBACServiceMockRecorder) IsGranted(ctx, permission any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsGranted", reflect.TypeOf((*MockRBACService)(nil).IsGranted), ctx, permission)
+}
+
+// Permissions mocks base method.
+func (m *MockRBACService) Permissions(ctx context.Context, userID uint) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Permissions", ctx, userID)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Permissions indicates an expected call of Permissions.
+func (mr *MockRBACServiceMockRecorder) Permissions(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Permissions", reflect.TypeOf((*MockRBACService)(nil).Permissions), ctx, userID)
+}
+
+// SetUserRoles mocks base method.
+func (m *MockRBACService) SetUserRoles(ctx context.Context, userID uint, roles []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetUserRoles", ctx, userID, roles)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetUserRoles indicates an expected call of SetUserRoles.
+func (mr *MockRBACServiceMockRecorder) SetUserRoles(ctx, userID, roles any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetUserRoles", reflect.TypeOf((*MockRBACService)(nil).SetUserRoles), ctx, userID, roles)
+}
+
+// UpdateRole mocks base method.
+func (m *MockRBACService) UpdateRole(ctx context.Context, dto *model.RoleDTO) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateRole", ctx, dto)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateRole indicates an expected call of UpdateRole.
+func (mr *MockRBACServiceMockRecorder) UpdateRole(ctx, dto any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateRole", reflect.TypeOf((*MockRBACService)(nil).UpdateRole), ctx, dto)
+}
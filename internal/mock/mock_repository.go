@@ -259,3 +259,17 @@ func (mr *MockRepositoryMockRecorder[E, D]) Update(ctx, dto any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockRepository[E, D])(nil).Update), ctx, dto)
 }
+
+// UpdateColumns mocks base method.
+func (m *MockRepository[E, D]) UpdateColumns(ctx context.Context, id any, values map[string]any) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateColumns", ctx, id, values)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateColumns indicates an expected call of UpdateColumns.
+func (mr *MockRepositoryMockRecorder[E, D]) UpdateColumns(ctx, id, values any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateColumns", reflect.TypeOf((*MockRepository[E, D])(nil).UpdateColumns), ctx, id, values)
+}
@@ -0,0 +1,63 @@
+package jwtx_test
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// keys are the files of an HS256 secret, an RSA key pair and an Ed25519 key
+// pair.
+type keys struct {
+	hs, rsa, rsaPublic, ed, edPublic string
+
+	rsaKey *rsa.PrivateKey
+	edKey  ed25519.PrivateKey
+}
+
+func newKeys(t *testing.T) keys {
+	dir := t.TempDir()
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	edPublic, edKey, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+
+	return keys{
+		hs:        write(t, dir, "hs.key", []byte("hs-secret\n")),
+		rsa:       write(t, dir, "rsa.pem", encodePEM(t, "PRIVATE KEY", rsaKey)),
+		rsaPublic: write(t, dir, "rsa.pub.pem", encodePEM(t, "PUBLIC KEY", &rsaKey.PublicKey)),
+		ed:        write(t, dir, "ed.pem", encodePEM(t, "PRIVATE KEY", edKey)),
+		edPublic:  write(t, dir, "ed.pub.pem", encodePEM(t, "PUBLIC KEY", edPublic)),
+		rsaKey:    rsaKey,
+		edKey:     edKey,
+	}
+}
+
+func encodePEM(t *testing.T, typ string, key any) []byte {
+	var (
+		der []byte
+		err error
+	)
+	if typ == "PUBLIC KEY" {
+		der, err = x509.MarshalPKIXPublicKey(key)
+	} else {
+		der, err = x509.MarshalPKCS8PrivateKey(key)
+	}
+	assert.NoError(t, err)
+
+	return pem.EncodeToMemory(&pem.Block{Type: typ, Bytes: der})
+}
+
+func write(t *testing.T, dir, name string, data []byte) string {
+	path := filepath.Join(dir, name)
+	assert.NoError(t, os.WriteFile(path, data, 0o600))
+	return path
+}
@@ -0,0 +1,81 @@
+package jwtx_test
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"go-fiber-api/internal/wrapper/jwtx"
+	"math/big"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKeyring_JWKS(t *testing.T) {
+	k := newKeys(t)
+
+	tests := []struct {
+		name         string
+		keys         string
+		signingKID   string
+		expectedKIDs []string
+	}{
+		{
+			name:         "when_keyring_is_legacy_only_should_publish_nothing",
+			expectedKIDs: []string{},
+		},
+		{
+			name:         "when_keyring_has_asymmetric_keys_should_publish_their_public_keys",
+			keys:         "c:HS256:" + k.hs + ",b:EdDSA:" + k.ed + ",a:RS256:" + k.rsaPublic,
+			signingKID:   "b",
+			expectedKIDs: []string{"a", "b"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keyring, err := jwtx.New(tt.keys, tt.signingKID, "legacy-secret")
+			assert.NoError(t, err)
+
+			app := fiber.New()
+			app.Get("/.well-known/jwks.json", keyring.Handler())
+
+			// test logic
+			resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/.well-known/jwks.json", nil))
+			assert.NoError(t, err)
+			assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+			assert.Equal(t, "public, max-age=300", resp.Header.Get(fiber.HeaderCacheControl))
+
+			var jwks jwtx.JWKS
+			assert.NoError(t, json.NewDecoder(resp.Body).Decode(&jwks))
+
+			kids := []string{}
+			for _, jwk := range jwks.Keys {
+				kids = append(kids, jwk.ID)
+				assert.Equal(t, "sig", jwk.Use)
+
+				switch jwk.KeyType {
+				case "RSA":
+					assert.Equal(t, jwtx.AlgRS256, jwk.Algorithm)
+					assert.Equal(t, k.rsaKey.N, new(big.Int).SetBytes(decode(t, jwk.N)))
+					assert.Equal(t, int64(k.rsaKey.E), new(big.Int).SetBytes(decode(t, jwk.E)).Int64())
+				case "OKP":
+					assert.Equal(t, jwtx.AlgEdDSA, jwk.Algorithm)
+					assert.Equal(t, "Ed25519", jwk.Curve)
+					assert.Equal(t, []byte(k.edKey.Public().(ed25519.PublicKey)), decode(t, jwk.X))
+				default:
+					t.Errorf("unexpected key type %s", jwk.KeyType)
+				}
+			}
+			assert.Equal(t, tt.expectedKIDs, kids)
+		})
+	}
+}
+
+func decode(t *testing.T, s string) []byte {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	assert.NoError(t, err)
+	return b
+}
@@ -0,0 +1,211 @@
+package jwtx
+
+import (
+	"crypto"
+	"errors"
+	"fmt"
+	"go-fiber-api/internal/core/config"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Algorithms of the keys of JWT_KEYS.
+const (
+	AlgHS256 = "HS256"
+	AlgRS256 = "RS256"
+	AlgEdDSA = "EdDSA"
+)
+
+// Key checks, and signs when its private part is known, the tokens whose
+// kid header is ID.
+type Key struct {
+	ID        string
+	Algorithm string
+
+	method jwt.SigningMethod
+	sign   any // nil for a public key
+	verify any
+}
+
+// Keyring signs tokens with its signing key, stating it in the kid header,
+// and checks them with the key of their kid. Tokens without kid are checked
+// with SECRET_KEY, which signed every token before the keyring, unless the
+// legacy key is disabled.
+type Keyring struct {
+	keys    map[string]*Key
+	signing *Key
+	legacy  *Key
+	methods []string
+}
+
+func Provide(cfg *config.Configuration) (*Keyring, error) {
+	legacySecret := cfg.SecretKey
+	if !cfg.JWT.LegacyEnabled {
+		legacySecret = ""
+	}
+
+	return New(cfg.JWT.Keys, cfg.JWT.SigningKID, legacySecret)
+}
+
+// New builds a Keyring from keys, comma separated kid:algorithm:file entries
+// such as 2025-01:RS256:file:///run/secrets/jwt.pem, signing with the key of
+// signingKID. Without keys the legacy HS256 secret signs and checks tokens.
+// An empty legacySecret checks no token without kid.
+//
+// The file of an HS256 key holds the secret, the one of an RS256 or EdDSA key
+// a PEM private key, or a public key for a key that only checks tokens.
+func New(keys, signingKID, legacySecret string) (*Keyring, error) {
+	k := &Keyring{
+		keys: make(map[string]*Key),
+	}
+
+	if legacySecret != "" {
+		k.legacy = &Key{
+			Algorithm: AlgHS256,
+			method:    jwt.SigningMethodHS256,
+			sign:      []byte(legacySecret),
+			verify:    []byte(legacySecret),
+		}
+		k.methods = append(k.methods, AlgHS256)
+	}
+
+	for _, entry := range strings.Split(keys, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		key, err := parseKey(entry)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := k.keys[key.ID]; ok {
+			return nil, fmt.Errorf("jwt key %s is defined twice", key.ID)
+		}
+
+		k.keys[key.ID] = key
+		if !slices.Contains(k.methods, key.Algorithm) {
+			k.methods = append(k.methods, key.Algorithm)
+		}
+	}
+
+	switch {
+	case len(k.keys) == 0:
+		k.signing = k.legacy
+	case signingKID == "":
+		return nil, errors.New("jwt signing kid is required with jwt keys")
+	default:
+		k.signing = k.keys[signingKID]
+		if k.signing == nil {
+			return nil, fmt.Errorf("jwt signing kid %s is not a jwt key", signingKID)
+		}
+	}
+
+	if k.signing == nil {
+		return nil, errors.New("jwt signing key is required")
+	}
+	if k.signing.sign == nil {
+		return nil, fmt.Errorf("jwt signing key %s has no private key", k.signing.ID)
+	}
+
+	return k, nil
+}
+
+func parseKey(entry string) (*Key, error) {
+	parts := strings.SplitN(entry, ":", 3)
+	if len(parts) != 3 || parts[0] == "" {
+		return nil, fmt.Errorf("jwt key %q is not kid:algorithm:file", entry)
+	}
+	kid, alg, path := parts[0], parts[1], strings.TrimPrefix(parts[2], "file://")
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read jwt key %s failed: %v", kid, err)
+	}
+
+	key := &Key{ID: kid, Algorithm: alg}
+	switch alg {
+	case AlgHS256:
+		secret := []byte(strings.TrimRight(string(data), "\r\n"))
+		if len(secret) == 0 {
+			return nil, fmt.Errorf("jwt key %s is empty", kid)
+		}
+		key.method, key.sign, key.verify = jwt.SigningMethodHS256, secret, secret
+	case AlgRS256:
+		key.method = jwt.SigningMethodRS256
+		if private, err := jwt.ParseRSAPrivateKeyFromPEM(data); err == nil {
+			key.sign, key.verify = private, &private.PublicKey
+		} else if public, err := jwt.ParseRSAPublicKeyFromPEM(data); err == nil {
+			key.verify = public
+		} else {
+			return nil, fmt.Errorf("jwt key %s is not an RSA PEM key: %v", kid, err)
+		}
+	case AlgEdDSA:
+		key.method = jwt.SigningMethodEdDSA
+		if private, err := jwt.ParseEdPrivateKeyFromPEM(data); err == nil {
+			key.sign, key.verify = private, private.(crypto.Signer).Public()
+		} else if public, err := jwt.ParseEdPublicKeyFromPEM(data); err == nil {
+			key.verify = public
+		} else {
+			return nil, fmt.Errorf("jwt key %s is not an Ed25519 PEM key: %v", kid, err)
+		}
+	default:
+		return nil, fmt.Errorf("jwt key %s has unsupported algorithm %q, want one of %s, %s, %s", kid, alg, AlgHS256, AlgRS256, AlgEdDSA)
+	}
+
+	return key, nil
+}
+
+// Sign returns a token of claims signed with the signing key.
+func (k *Keyring) Sign(claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(k.signing.method, claims)
+	if k.signing.ID != "" {
+		token.Header["kid"] = k.signing.ID
+	}
+
+	return token.SignedString(k.signing.sign)
+}
+
+// Parse checks tokenString with the key of its kid, decoding its claims
+// into claims. The algorithm of the token must be the one of the key, an
+// RS256 public key is never used as an HS256 secret.
+func (k *Keyring) Parse(tokenString string, claims jwt.Claims, opts ...jwt.ParserOption) (*jwt.Token, error) {
+	opts = append(opts, jwt.WithValidMethods(k.methods))
+
+	return jwt.ParseWithClaims(tokenString, claims, k.keyFunc, opts...)
+}
+
+func (k *Keyring) keyFunc(token *jwt.Token) (any, error) {
+	key := k.legacy
+	if kid, _ := token.Header["kid"].(string); kid != "" {
+		key = k.keys[kid]
+		if key == nil {
+			return nil, fmt.Errorf("unknown kid %q", kid)
+		}
+	}
+	if key == nil {
+		return nil, errors.New("kid is required")
+	}
+
+	if token.Method.Alg() != key.Algorithm {
+		return nil, fmt.Errorf("algorithm %s is not the one of kid %q", token.Method.Alg(), key.ID)
+	}
+
+	return key.verify, nil
+}
+
+// Keys returns the keys of the keyring by kid, the legacy key aside.
+func (k *Keyring) Keys() []*Key {
+	keys := make([]*Key, 0, len(k.keys))
+	for _, key := range k.keys {
+		keys = append(keys, key)
+	}
+	slices.SortFunc(keys, func(a, b *Key) int {
+		return strings.Compare(a.ID, b.ID)
+	})
+
+	return keys
+}
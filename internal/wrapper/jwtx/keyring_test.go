@@ -0,0 +1,231 @@
+package jwtx_test
+
+import (
+	"go-fiber-api/internal/core/config"
+	"go-fiber-api/internal/wrapper/jwtx"
+	"os"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNew_Invalid(t *testing.T) {
+	k := newKeys(t)
+
+	tests := []struct {
+		name          string
+		keys          string
+		signingKID    string
+		legacySecret  string
+		expectedError string
+	}{
+		{
+			name:          "when_no_key_is_set_should_get_error",
+			expectedError: "jwt signing key is required",
+		},
+		{
+			name:          "when_signing_kid_is_missing_should_get_error",
+			keys:          "a:HS256:" + k.hs,
+			expectedError: "jwt signing kid is required with jwt keys",
+		},
+		{
+			name:          "when_signing_kid_is_unknown_should_get_error",
+			keys:          "a:HS256:" + k.hs,
+			signingKID:    "b",
+			expectedError: "jwt signing kid b is not a jwt key",
+		},
+		{
+			name:          "when_signing_key_is_public_should_get_error",
+			keys:          "a:RS256:" + k.rsaPublic,
+			signingKID:    "a",
+			expectedError: "jwt signing key a has no private key",
+		},
+		{
+			name:          "when_entry_is_malformed_should_get_error",
+			keys:          "a-HS256",
+			signingKID:    "a",
+			expectedError: `jwt key "a-HS256" is not kid:algorithm:file`,
+		},
+		{
+			name:          "when_algorithm_is_unsupported_should_get_error",
+			keys:          "a:none:" + k.hs,
+			signingKID:    "a",
+			expectedError: `jwt key a has unsupported algorithm "none"`,
+		},
+		{
+			name:          "when_kid_is_defined_twice_should_get_error",
+			keys:          "a:HS256:" + k.hs + ",a:RS256:" + k.rsa,
+			signingKID:    "a",
+			expectedError: "jwt key a is defined twice",
+		},
+		{
+			name:          "when_key_does_not_match_algorithm_should_get_error",
+			keys:          "a:EdDSA:" + k.rsa,
+			signingKID:    "a",
+			expectedError: "jwt key a is not an Ed25519 PEM key",
+		},
+		{
+			name:          "when_key_file_is_missing_should_get_error",
+			keys:          "a:HS256:file:///not/exist/key",
+			signingKID:    "a",
+			expectedError: "read jwt key a failed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keyring, err := jwtx.New(tt.keys, tt.signingKID, tt.legacySecret)
+			assert.Nil(t, keyring)
+			assert.ErrorContains(t, err, tt.expectedError)
+		})
+	}
+}
+
+func TestKeyring_Parse(t *testing.T) {
+	k := newKeys(t)
+	rsaPublicPEM, err := os.ReadFile(k.rsaPublic)
+	assert.NoError(t, err)
+
+	keyring, err := jwtx.New("old:HS256:"+k.hs+", current:RS256:file://"+k.rsa+", partner:EdDSA:"+k.edPublic, "current", "legacy-secret")
+	assert.NoError(t, err)
+
+	sign := func(method jwt.SigningMethod, kid string, key any) string {
+		token := jwt.NewWithClaims(method, jwt.MapClaims{"name": "test"})
+		if kid != "" {
+			token.Header["kid"] = kid
+		}
+		s, err := token.SignedString(key)
+		assert.NoError(t, err)
+		return s
+	}
+
+	signed, err := keyring.Sign(jwt.MapClaims{"name": "test"})
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name          string
+		token         string
+		expectedKID   any
+		expectedError string
+	}{
+		{
+			name:        "when_signed_by_keyring_should_use_signing_kid",
+			token:       signed,
+			expectedKID: "current",
+		},
+		{
+			name:  "when_token_has_no_kid_should_check_it_with_legacy_secret",
+			token: sign(jwt.SigningMethodHS256, "", []byte("legacy-secret")),
+		},
+		{
+			name:        "when_token_has_rotated_kid_should_check_it_with_its_key",
+			token:       sign(jwt.SigningMethodHS256, "old", []byte("hs-secret")),
+			expectedKID: "old",
+		},
+		{
+			name:        "when_key_is_public_only_should_check_tokens",
+			token:       sign(jwt.SigningMethodEdDSA, "partner", k.edKey),
+			expectedKID: "partner",
+		},
+		{
+			name:          "when_kid_is_unknown_should_get_error",
+			token:         sign(jwt.SigningMethodHS256, "gone", []byte("hs-secret")),
+			expectedError: `unknown kid "gone"`,
+		},
+		{
+			name:          "when_signed_by_another_key_should_get_error",
+			token:         sign(jwt.SigningMethodHS256, "old", []byte("other-secret")),
+			expectedError: "signature is invalid",
+		},
+		{
+			name:          "when_public_key_is_used_as_hmac_secret_should_get_error",
+			token:         sign(jwt.SigningMethodHS256, "current", rsaPublicPEM),
+			expectedError: `algorithm HS256 is not the one of kid "current"`,
+		},
+		{
+			name:          "when_algorithm_is_not_in_keyring_should_get_error",
+			token:         sign(jwt.SigningMethodHS512, "old", []byte("hs-secret")),
+			expectedError: "signing method HS512 is invalid",
+		},
+		{
+			name:          "when_algorithm_is_none_should_get_error",
+			token:         sign(jwt.SigningMethodNone, "", jwt.UnsafeAllowNoneSignatureType),
+			expectedError: "signing method none is invalid",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token, err := keyring.Parse(tt.token, jwt.MapClaims{})
+			if tt.expectedError != "" {
+				assert.ErrorContains(t, err, tt.expectedError)
+				return
+			}
+
+			if assert.NoError(t, err) {
+				assert.True(t, token.Valid)
+				assert.Equal(t, tt.expectedKID, token.Header["kid"])
+				assert.Equal(t, "test", token.Claims.(jwt.MapClaims)["name"])
+			}
+		})
+	}
+}
+
+func TestKeyring_Sign_Legacy(t *testing.T) {
+	keyring, err := jwtx.New("", "", "legacy-secret")
+	assert.NoError(t, err)
+
+	s, err := keyring.Sign(jwt.MapClaims{"name": "test"})
+	assert.NoError(t, err)
+
+	// Same tokens as before the keyring: HS256 without kid.
+	token, err := jwt.Parse(s, func(*jwt.Token) (any, error) {
+		return []byte("legacy-secret"), nil
+	}, jwt.WithValidMethods([]string{jwtx.AlgHS256}))
+	assert.NoError(t, err)
+	assert.NotContains(t, token.Header, "kid")
+}
+
+func TestProvide_LegacyDisabled(t *testing.T) {
+	k := newKeys(t)
+	legacy, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"name": "test"}).SignedString([]byte("legacy-secret"))
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name          string
+		legacyEnabled bool
+		expectedError string
+	}{
+		{
+			name:          "when_legacy_is_enabled_should_check_tokens_without_kid",
+			legacyEnabled: true,
+		},
+		{
+			name:          "when_legacy_is_disabled_should_refuse_tokens_without_kid",
+			expectedError: "signing method HS256 is invalid",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keyring, err := jwtx.Provide(&config.Configuration{
+				SecretKey: "legacy-secret",
+				JWT: config.JWTConfig{
+					Keys:          "current:RS256:" + k.rsa,
+					SigningKID:    "current",
+					LegacyEnabled: tt.legacyEnabled,
+				},
+			})
+			assert.NoError(t, err)
+
+			// test logic
+			_, err = keyring.Parse(legacy, jwt.MapClaims{})
+			if tt.expectedError != "" {
+				assert.ErrorContains(t, err, tt.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
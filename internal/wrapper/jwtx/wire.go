@@ -0,0 +1,7 @@
+package jwtx
+
+import "github.com/google/wire"
+
+var ProviderSet = wire.NewSet(
+	Provide,
+)
@@ -0,0 +1,50 @@
+package logx
+
+import (
+	"context"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+type contextKey struct{}
+
+// NewContext returns a copy of ctx carrying entry.
+func NewContext(ctx context.Context, entry *logrus.Entry) context.Context {
+	return context.WithValue(ctx, contextKey{}, entry)
+}
+
+// FromContext returns the request scoped logger stored in ctx, or an entry of
+// the global logger when there is none. Both c.Context() and c.UserContext()
+// of a Fiber request carry it once the request logger middleware ran. The
+// entry is bound to ctx so its lines carry the trace ID of the current span.
+func FromContext(ctx context.Context) *logrus.Entry {
+	if ctx == nil {
+		return logrus.NewEntry(logrus.StandardLogger())
+	}
+
+	if entry, ok := ctx.Value(contextKey{}).(*logrus.Entry); ok {
+		return entry.WithContext(ctx)
+	}
+
+	return logrus.NewEntry(logrus.StandardLogger()).WithContext(ctx)
+}
+
+// Inject stores entry as the request scoped logger of c.
+func Inject(c *fiber.Ctx, entry *logrus.Entry) {
+	// Locals are backed by the fasthttp user values that c.Context() exposes
+	// through Value, so services given c.Context() find the entry as well.
+	c.Locals(contextKey{}, entry)
+	c.SetUserContext(NewContext(c.UserContext(), entry))
+}
+
+// AddFields adds fields to the request scoped logger of c, so every later log
+// line of the request, including the completion line, carries them.
+func AddFields(c *fiber.Ctx, fields logrus.Fields) {
+	entry, ok := c.Locals(contextKey{}).(*logrus.Entry)
+	if !ok {
+		return
+	}
+
+	Inject(c, entry.WithFields(fields))
+}
@@ -0,0 +1,219 @@
+package logx
+
+import (
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// componentKeys are the entry fields a component level is matched against,
+// in order.
+var componentKeys = []string{"component", "module"}
+
+// Levels changes the log level of LogX and the global logger at runtime, per
+// component if needed, optionally reverting to the default after a while.
+type Levels struct {
+	mu sync.RWMutex
+
+	loggers      []*logrus.Logger
+	defaultLevel logrus.Level
+	level        logrus.Level
+	components   map[string]logrus.Level
+
+	revertAt         time.Time
+	revert           *time.Timer
+	componentReverts map[string]*time.Timer
+}
+
+// LevelState is a snapshot of the current levels.
+type LevelState struct {
+	Default    string            `json:"default"`
+	Level      string            `json:"level"`
+	Components map[string]string `json:"components"`
+	RevertAt   *time.Time        `json:"revertAt,omitempty"`
+}
+
+func newLevels(level logrus.Level, loggers ...*logrus.Logger) *Levels {
+	l := &Levels{
+		loggers:          loggers,
+		defaultLevel:     level,
+		level:            level,
+		components:       make(map[string]logrus.Level),
+		componentReverts: make(map[string]*time.Timer),
+	}
+	l.apply()
+
+	return l
+}
+
+// SetLevel changes the level of every component without its own level. A
+// positive d reverts it to the default level after d.
+func (l *Levels) SetLevel(level logrus.Level, d time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.level = level
+	if l.revert != nil {
+		l.revert.Stop()
+		l.revert = nil
+	}
+	l.revertAt = time.Time{}
+
+	if d > 0 {
+		l.revertAt = time.Now().Add(d)
+		l.revert = time.AfterFunc(d, func() {
+			l.mu.Lock()
+			defer l.mu.Unlock()
+
+			l.level = l.defaultLevel
+			l.revert = nil
+			l.revertAt = time.Time{}
+			l.apply()
+		})
+	}
+
+	l.apply()
+}
+
+// SetDefault changes the default level, which the level follows unless it
+// was changed with SetLevel.
+func (l *Levels) SetDefault(level logrus.Level) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.level == l.defaultLevel && l.revert == nil {
+		l.level = level
+	}
+	l.defaultLevel = level
+
+	l.apply()
+}
+
+// SetComponentLevel changes the level of entries whose component or module
+// field is component. A positive d removes the override after d.
+func (l *Levels) SetComponentLevel(component string, level logrus.Level, d time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.components[component] = level
+	if t, ok := l.componentReverts[component]; ok {
+		t.Stop()
+		delete(l.componentReverts, component)
+	}
+
+	if d > 0 {
+		l.componentReverts[component] = time.AfterFunc(d, func() {
+			l.mu.Lock()
+			defer l.mu.Unlock()
+
+			delete(l.components, component)
+			delete(l.componentReverts, component)
+			l.apply()
+		})
+	}
+
+	l.apply()
+}
+
+// Reset restores the default level and drops every component level.
+func (l *Levels) Reset() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.revert != nil {
+		l.revert.Stop()
+		l.revert = nil
+	}
+	for component, t := range l.componentReverts {
+		t.Stop()
+		delete(l.componentReverts, component)
+	}
+
+	l.level = l.defaultLevel
+	l.revertAt = time.Time{}
+	l.components = make(map[string]logrus.Level)
+	l.apply()
+}
+
+func (l *Levels) State() LevelState {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	state := LevelState{
+		Default:    l.defaultLevel.String(),
+		Level:      l.level.String(),
+		Components: make(map[string]string, len(l.components)),
+	}
+	for component, level := range l.components {
+		state.Components[component] = level.String()
+	}
+	if !l.revertAt.IsZero() {
+		revertAt := l.revertAt
+		state.RevertAt = &revertAt
+	}
+
+	return state
+}
+
+// apply sets the loggers to the most verbose level in use so that entries of
+// a verbose component reach enabled, which does the actual filtering. The
+// caller must hold mu.
+func (l *Levels) apply() {
+	max := l.level
+	for _, level := range l.components {
+		if level > max {
+			max = level
+		}
+	}
+
+	for _, logger := range l.loggers {
+		logger.SetLevel(max)
+	}
+}
+
+func (l *Levels) enabled(entry *logrus.Entry) bool {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	level := l.level
+	for _, key := range componentKeys {
+		component, ok := entry.Data[key].(string)
+		if !ok {
+			continue
+		}
+
+		if componentLevel, ok := l.components[component]; ok {
+			level = componentLevel
+			break
+		}
+	}
+
+	return entry.Level <= level
+}
+
+// filter decides which entries are written: those enabled by Levels and, when
+// sampling is on, kept by the sampler.
+type filter struct {
+	levels  *Levels
+	sampler *sampler
+}
+
+func (f *filter) keep(entry *logrus.Entry) bool {
+	return f.levels.enabled(entry) && f.sampler.allow(entry)
+}
+
+// filterFormatter drops the entries filter does not keep before they are
+// formatted.
+type filterFormatter struct {
+	logrus.Formatter
+	filter *filter
+}
+
+func (f *filterFormatter) Format(entry *logrus.Entry) ([]byte, error) {
+	if !f.filter.keep(entry) {
+		return nil, nil
+	}
+
+	return f.Formatter.Format(entry)
+}
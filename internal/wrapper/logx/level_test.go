@@ -0,0 +1,119 @@
+package logx_test
+
+import (
+	"bytes"
+	"go-fiber-api/internal/core/lifecycle"
+	"go-fiber-api/internal/wrapper/logx"
+	"os"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLevels(t *testing.T) {
+	tests := []struct {
+		name     string
+		setup    func(levels *logx.Levels)
+		log      func(l *logx.LogX)
+		expected []string
+	}{
+		{
+			name: "when_level_is_raised_should_drop_verbose_entries",
+			setup: func(levels *logx.Levels) {
+				levels.SetLevel(logrus.WarnLevel, 0)
+			},
+			log: func(l *logx.LogX) {
+				l.Info("info entry")
+				l.Warn("warn entry")
+			},
+			expected: []string{"warn entry"},
+		},
+		{
+			name: "when_component_level_is_set_should_only_apply_to_component",
+			setup: func(levels *logx.Levels) {
+				levels.SetLevel(logrus.InfoLevel, 0)
+				levels.SetComponentLevel("db", logrus.DebugLevel, 0)
+			},
+			log: func(l *logx.LogX) {
+				l.WithField("component", "db").Debug("db debug")
+				l.WithField("module", "db").Debug("db module debug")
+				l.WithField("component", "api").Debug("api debug")
+				l.Debug("plain debug")
+			},
+			expected: []string{"db debug", "db module debug"},
+		},
+		{
+			name: "when_component_is_quieter_should_drop_its_entries",
+			setup: func(levels *logx.Levels) {
+				levels.SetComponentLevel("redis", logrus.ErrorLevel, 0)
+			},
+			log: func(l *logx.LogX) {
+				l.WithField("component", "redis").Warn("redis warn")
+				l.WithField("component", "api").Warn("api warn")
+			},
+			expected: []string{"api warn"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			l := logx.Provide(lifecycle.New())
+			l.SetOutput(&buf)
+			defer l.SetOutput(os.Stdout)
+			defer l.Levels().Reset()
+
+			tt.setup(l.Levels())
+			tt.log(l)
+
+			out := buf.String()
+			for _, msg := range []string{"info entry", "warn entry", "db debug", "db module debug", "api debug", "plain debug", "redis warn", "api warn"} {
+				if slices.Contains(tt.expected, msg) {
+					assert.Contains(t, out, msg)
+				} else {
+					assert.NotContains(t, out, msg)
+				}
+			}
+		})
+	}
+}
+
+func TestLevels_Revert(t *testing.T) {
+	levels := logx.Provide(lifecycle.New()).Levels()
+
+	levels.SetLevel(logrus.ErrorLevel, 50*time.Millisecond)
+	levels.SetComponentLevel("db", logrus.TraceLevel, 50*time.Millisecond)
+
+	state := levels.State()
+	assert.Equal(t, "error", state.Level)
+	assert.Equal(t, map[string]string{"db": "trace"}, state.Components)
+	assert.NotNil(t, state.RevertAt)
+
+	assert.Eventually(t, func() bool {
+		state := levels.State()
+		return state.Level == state.Default && len(state.Components) == 0 && state.RevertAt == nil
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestLevels_SetDefault(t *testing.T) {
+	levels := logx.Provide(lifecycle.New()).Levels()
+	defer levels.Reset()
+
+	levels.SetDefault(logrus.WarnLevel)
+	state := levels.State()
+	assert.Equal(t, "warning", state.Default)
+	assert.Equal(t, "warning", state.Level)
+
+	// A level set at runtime is kept, the default applies again on reset.
+	levels.SetLevel(logrus.ErrorLevel, 0)
+	levels.SetDefault(logrus.InfoLevel)
+	state = levels.State()
+	assert.Equal(t, "info", state.Default)
+	assert.Equal(t, "error", state.Level)
+
+	levels.Reset()
+	assert.Equal(t, "info", levels.State().Level)
+}
@@ -1,81 +1,129 @@
 package logx
 
 import (
-	"os"
-	"sync"
+	"context"
+	"go-fiber-api/internal/core/lifecycle"
+	"time"
 
 	"github.com/sirupsen/logrus"
 )
 
-var (
-	log     *LogX
-	logOnce sync.Once
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+
+	defaultDebugDuration = 15 * time.Minute
 )
 
 type LogX struct {
 	*logrus.Logger
-}
-
-func Provide() *LogX {
-	logOnce.Do(func() {
-		// ============== global log ==============
-
-		/// ENV: LOG_LEVEL
-		// "panic"
-		// "fatal"
-		// "error"
-		// "warn", "warning"
-		// "info"
-		// "debug"
-		// "trace"
-		logLevel, ok := os.LookupEnv("LOG_LEVEL")
-
-		// LOG_LEVEL not set, let's default to debug
-		if !ok {
-			logLevel = "debug"
-		}
-
-		logrus.SetLevel(logrus.DebugLevel)
 
-		// parse string, this is built-in feature of logrus
-		logrusLevel, err := logrus.ParseLevel(logLevel)
-		if err != nil {
-			logrusLevel = logrus.DebugLevel
-		}
+	redactor *RedactHook
+	levels   *Levels
+	sinks    *sinks
+	stop     chan struct{}
+}
 
-		// set global log level
-		logrus.SetLevel(logrusLevel)
+// Provide builds a logger watching for log level signals once lc starts and
+// closing its outputs once lc stops.
+//
+// The global logrus logger is shared by every LogX of the process: it is set
+// up like the most recently provided one.
+func Provide(lc *lifecycle.Lifecycle) *LogX {
+	opts := optionsFromEnv()
+
+	redactor := NewRedactHook()
+	logrusLogger := logrus.New()
+
+	levels := newLevels(opts.level, logrus.StandardLogger(), logrusLogger)
+	sinks := newSinks(opts, levels)
+
+	// Both loggers share one sampler so a message is counted once
+	// whichever of them logs it.
+	f := &filter{
+		levels:  levels,
+		sampler: newSampler(opts.samplingInitial, opts.samplingThereafter, opts.samplingTick),
+	}
+
+	// The global logger is still used by packages logging through logrus
+	// directly, so it gets the same setup as LogX.
+	configure(logrus.StandardLogger(), opts.format, redactor, f, sinks)
+	configure(logrusLogger, opts.format, redactor, f, sinks)
+
+	log := &LogX{
+		Logger:   logrusLogger,
+		redactor: redactor,
+		levels:   levels,
+		sinks:    sinks,
+		stop:     make(chan struct{}),
+	}
+
+	lc.Append(lifecycle.Hook{
+		Name: "logx",
+		OnStart: func(context.Context) error {
+			watchSignals(levels, opts.debugFor, log.stop)
+			return nil
+		},
+		OnStop: func(context.Context) error {
+			return log.Close()
+		},
+	})
 
-		// TimestampFormat: "2006-01-02 150405"
-		logrus.SetFormatter(&logrus.TextFormatter{
-			FullTimestamp: true,
-		})
+	return log
+}
 
-		logrus.SetOutput(os.Stdout)
+func configure(logger *logrus.Logger, format string, redactor *RedactHook, f *filter, sinks *sinks) {
+	logger.SetFormatter(&filterFormatter{Formatter: formatter(format), filter: f})
+	logger.SetOutput(sinks.out)
+	logger.ReplaceHooks(logrus.LevelHooks{})
+	logger.AddHook(TraceHook{})
+	// Redact first so that sink hooks only see masked entries.
+	logger.AddHook(redactor)
+	for _, hook := range sinks.hooks {
+		logger.AddHook(hook)
+	}
+}
 
-		// ============== old log ==============
+// Levels controls the log levels at runtime.
+func (l *LogX) Levels() *Levels {
+	return l.levels
+}
 
-		logrusLogger := logrus.New()
-		logrusLogger.SetLevel(logrus.DebugLevel)
+// Close stops watching for log level signals and closes the file and syslog
+// outputs.
+func (l *LogX) Close() error {
+	if l.stop == nil {
+		return nil
+	}
+
+	select {
+	case <-l.stop:
+		return nil
+	default:
+		close(l.stop)
+	}
+
+	return l.sinks.Close()
+}
 
-		// parse string, this is built-in feature of logrus
-		// logrusLevel, err := logrus.ParseLevel(logLevel)
-		// if err != nil {
-		// 	logrusLevel = logrus.DebugLevel
-		// }
+// RegisterSecrets masks values in every later log line of both LogX and the
+// global logger, in place of the values registered before.
+func (l *LogX) RegisterSecrets(values ...string) {
+	if l.redactor == nil {
+		return
+	}
 
-		// set global log level
-		logrusLogger.SetLevel(logrusLevel)
+	l.redactor.SetSecrets(values...)
+}
 
+func formatter(format string) logrus.Formatter {
+	switch format {
+	case FormatJSON:
+		return &logrus.JSONFormatter{}
+	default:
 		// TimestampFormat: "2006-01-02 150405"
-		logrusLogger.SetFormatter(&logrus.TextFormatter{
+		return &logrus.TextFormatter{
 			FullTimestamp: true,
-		})
-
-		logrusLogger.SetOutput(os.Stdout)
-
-		log = &LogX{logrusLogger}
-	})
-
-	return log
+		}
+	}
 }
@@ -0,0 +1,145 @@
+package logx
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	OutputStdout = "stdout"
+	OutputFile   = "file"
+	OutputSyslog = "syslog"
+)
+
+// options holds the LOG_* environment variables. logx is set up before the
+// configuration is loaded, so it reads them directly.
+type options struct {
+	level    logrus.Level
+	format   string
+	debugFor time.Duration
+	outputs  []string
+
+	filePath           string
+	fileMaxSize        int
+	fileMaxAge         int
+	fileMaxBackups     int
+	fileCompress       bool
+	fileRotateInterval time.Duration
+
+	syslogTag string
+
+	samplingInitial    uint64
+	samplingThereafter uint64
+	samplingTick       time.Duration
+}
+
+func optionsFromEnv() options {
+	/// ENV: LOG_LEVEL
+	// "panic"
+	// "fatal"
+	// "error"
+	// "warn", "warning"
+	// "info"
+	// "debug"
+	// "trace"
+	logLevel, ok := os.LookupEnv("LOG_LEVEL")
+
+	// LOG_LEVEL not set, let's default to debug
+	if !ok {
+		logLevel = "debug"
+	}
+
+	// parse string, this is built-in feature of logrus
+	logrusLevel, err := logrus.ParseLevel(logLevel)
+	if err != nil {
+		logrusLevel = logrus.DebugLevel
+	}
+
+	return options{
+		level: logrusLevel,
+
+		/// ENV: LOG_FORMAT
+		// "text" (default)
+		// "json"
+		format: strings.ToLower(os.Getenv("LOG_FORMAT")),
+
+		/// ENV: LOG_DEBUG_DURATION
+		// how long debug stays on after SIGUSR1, default "15m"
+		debugFor: envDuration("LOG_DEBUG_DURATION", defaultDebugDuration),
+
+		/// ENV: LOG_OUTPUTS
+		// comma separated list of "stdout" (default), "file" and "syslog"
+		outputs: envList("LOG_OUTPUTS", OutputStdout),
+
+		/// ENV: LOG_FILE_*
+		// rotated when the file reaches LOG_FILE_MAX_SIZE megabytes or every
+		// LOG_FILE_ROTATE_INTERVAL, whichever comes first
+		filePath:           envString("LOG_FILE_PATH", "logs/app.log"),
+		fileMaxSize:        envInt("LOG_FILE_MAX_SIZE", 100),
+		fileMaxAge:         envInt("LOG_FILE_MAX_AGE", 7),
+		fileMaxBackups:     envInt("LOG_FILE_MAX_BACKUPS", 10),
+		fileCompress:       envBool("LOG_FILE_COMPRESS", true),
+		fileRotateInterval: envDuration("LOG_FILE_ROTATE_INTERVAL", 0),
+
+		/// ENV: LOG_SYSLOG_TAG
+		syslogTag: envString("LOG_SYSLOG_TAG", "go-fiber-api"),
+
+		/// ENV: LOG_SAMPLING_*
+		// per LOG_SAMPLING_TICK, the first LOG_SAMPLING_INITIAL entries with
+		// the same level and message are logged, then every
+		// LOG_SAMPLING_THEREAFTER-th; 0 initial disables sampling
+		samplingInitial:    uint64(envInt("LOG_SAMPLING_INITIAL", 0)),
+		samplingThereafter: uint64(envInt("LOG_SAMPLING_THEREAFTER", 100)),
+		samplingTick:       envDuration("LOG_SAMPLING_TICK", time.Second),
+	}
+}
+
+func envString(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+
+	return fallback
+}
+
+func envList(key, fallback string) []string {
+	var values []string
+	for _, v := range strings.Split(envString(key, fallback), ",") {
+		if v = strings.ToLower(strings.TrimSpace(v)); v != "" {
+			values = append(values, v)
+		}
+	}
+
+	return values
+}
+
+func envInt(key string, fallback int) int {
+	v, err := strconv.Atoi(os.Getenv(key))
+	if err != nil || v < 0 {
+		return fallback
+	}
+
+	return v
+}
+
+func envBool(key string, fallback bool) bool {
+	v, err := strconv.ParseBool(os.Getenv(key))
+	if err != nil {
+		return fallback
+	}
+
+	return v
+}
+
+func envDuration(key string, fallback time.Duration) time.Duration {
+	v, err := time.ParseDuration(os.Getenv(key))
+	if err != nil || v <= 0 {
+		return fallback
+	}
+
+	return v
+}
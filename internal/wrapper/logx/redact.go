@@ -0,0 +1,213 @@
+package logx
+
+import (
+	"net/http"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Mask replaces every redacted value.
+const Mask = "******"
+
+// minSecretLength keeps values too short to be secrets, such as a DB_PASS of
+// "a" in a local run, from masking every occurrence of them.
+const minSecretLength = 6
+
+// sensitiveKeys are field and header names whose value is always masked,
+// compared case-insensitively with "-" and "_" treated alike.
+var sensitiveKeys = map[string]struct{}{
+	"authorization":       {},
+	"proxy_authorization": {},
+	"x_api_key":           {},
+	"cookie":              {},
+	"set_cookie":          {},
+	"password":            {},
+	"secret":              {},
+	"secret_key":          {},
+	"token":               {},
+	"access_token":        {},
+	"refresh_token":       {},
+}
+
+func isSensitiveKey(key string) bool {
+	key = strings.ReplaceAll(strings.ToLower(key), "-", "_")
+	_, ok := sensitiveKeys[key]
+	return ok
+}
+
+// RedactHook masks sensitive fields and any registered secret value before an
+// entry is formatted, whatever code path produced it.
+type RedactHook struct {
+	mu      sync.RWMutex
+	secrets []string
+}
+
+func NewRedactHook() *RedactHook {
+	return &RedactHook{}
+}
+
+// SetSecrets replaces the values that must never appear in a log line,
+// ignoring values shorter than minSecretLength.
+func (h *RedactHook) SetSecrets(values ...string) {
+	secrets := make([]string, 0, len(values))
+	for _, v := range values {
+		if len(v) >= minSecretLength && !slices.Contains(secrets, v) {
+			secrets = append(secrets, v)
+		}
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.secrets = secrets
+}
+
+func (h *RedactHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (h *RedactHook) Fire(entry *logrus.Entry) error {
+	// entry.Data is a copy owned by this log call, but nested values are
+	// shared with the caller and are replaced rather than modified.
+	for key, value := range entry.Data {
+		if isSensitiveKey(key) {
+			entry.Data[key] = Mask
+			continue
+		}
+		entry.Data[key] = h.redactValue(value)
+	}
+	entry.Message = h.redactString(entry.Message)
+
+	return nil
+}
+
+func (h *RedactHook) redactString(s string) string {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	for _, secret := range h.secrets {
+		s = strings.ReplaceAll(s, secret, Mask)
+	}
+
+	return s
+}
+
+func (h *RedactHook) redactValue(value any) any {
+	switch v := value.(type) {
+	case string:
+		return h.redactString(v)
+	case http.Header:
+		masked := make(http.Header, len(v))
+		for key, values := range v {
+			if isSensitiveKey(key) {
+				masked[key] = []string{Mask}
+				continue
+			}
+			masked[key] = values
+		}
+		return masked
+	case map[string]string:
+		masked := make(map[string]string, len(v))
+		for key, s := range v {
+			if isSensitiveKey(key) {
+				masked[key] = Mask
+				continue
+			}
+			masked[key] = h.redactString(s)
+		}
+		return masked
+	case map[string]any:
+		masked := make(map[string]any, len(v))
+		for key, nested := range v {
+			if isSensitiveKey(key) {
+				masked[key] = Mask
+				continue
+			}
+			masked[key] = h.redactValue(nested)
+		}
+		return masked
+	default:
+		return value
+	}
+}
+
+// Redact returns the fields of struct v as a map, safe to log, with every
+// non-empty field tagged `secret:"true"` masked. Nested structs are redacted
+// too.
+func Redact(v any) map[string]any {
+	rv := reflect.Indirect(reflect.ValueOf(v))
+	if rv.Kind() != reflect.Struct {
+		return nil
+	}
+
+	rt := rv.Type()
+	result := make(map[string]any, rt.NumField())
+	for i := 0; i < rt.NumField(); i++ {
+		f := rt.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+
+		fv := rv.Field(i)
+		switch {
+		case f.Tag.Get("secret") == "true":
+			if fv.IsZero() {
+				result[f.Name] = fv.Interface()
+			} else {
+				result[f.Name] = Mask
+			}
+		case fv.Kind() == reflect.Struct && hasExportedFields(f.Type):
+			result[f.Name] = Redact(fv.Interface())
+		default:
+			result[f.Name] = fv.Interface()
+		}
+	}
+
+	return result
+}
+
+// SecretValues returns the non-empty string values of fields tagged
+// `secret:"true"` in struct v, including nested structs.
+func SecretValues(v any) []string {
+	rv := reflect.Indirect(reflect.ValueOf(v))
+	if rv.Kind() != reflect.Struct {
+		return nil
+	}
+
+	rt := rv.Type()
+	values := make([]string, 0)
+	for i := 0; i < rt.NumField(); i++ {
+		f := rt.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+
+		fv := rv.Field(i)
+		switch {
+		case f.Tag.Get("secret") == "true" && fv.Kind() == reflect.String:
+			if s := fv.String(); len(s) > 0 {
+				values = append(values, s)
+			}
+		case fv.Kind() == reflect.Struct && hasExportedFields(f.Type):
+			values = append(values, SecretValues(fv.Interface())...)
+		}
+	}
+
+	return values
+}
+
+// hasExportedFields keeps types such as time.Time, which only have
+// unexported fields, from being flattened into an empty map.
+func hasExportedFields(t reflect.Type) bool {
+	for i := 0; i < t.NumField(); i++ {
+		if t.Field(i).IsExported() {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,133 @@
+package logx_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"go-fiber-api/internal/wrapper/logx"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+type redisConfig struct {
+	Host     string
+	Password string `secret:"true"`
+}
+
+type testConfig struct {
+	Port      string
+	SecretKey string `secret:"true"`
+	DBPass    string `secret:"true"`
+	StartedAt time.Time
+	Redis     redisConfig
+}
+
+func TestRedact(t *testing.T) {
+	startedAt := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	cfg := testConfig{
+		Port:      "8080",
+		SecretKey: "super-secret",
+		StartedAt: startedAt,
+		Redis:     redisConfig{Host: "localhost", Password: "redis-pass"},
+	}
+
+	expected := map[string]any{
+		"Port":      "8080",
+		"SecretKey": logx.Mask,
+		"DBPass":    "",
+		"StartedAt": startedAt,
+		"Redis": map[string]any{
+			"Host":     "localhost",
+			"Password": logx.Mask,
+		},
+	}
+
+	assert.Equal(t, expected, logx.Redact(cfg))
+	assert.Equal(t, expected, logx.Redact(&cfg))
+	assert.Nil(t, logx.Redact("not a struct"))
+	assert.Equal(t, []string{"super-secret", "redis-pass"}, logx.SecretValues(cfg))
+}
+
+func TestRedactHook(t *testing.T) {
+	tests := []struct {
+		name          string
+		fields        logrus.Fields
+		message       string
+		expectedMsg   string
+		expectedField map[string]any
+	}{
+		{
+			name: "when_field_key_is_sensitive_should_mask_value",
+			fields: logrus.Fields{
+				"Authorization": "Bearer abc",
+				"x-api-key":     "key",
+				"path":          "/service",
+			},
+			message:     "request",
+			expectedMsg: "request",
+			expectedField: map[string]any{
+				"Authorization": logx.Mask,
+				"x-api-key":     logx.Mask,
+				"path":          "/service",
+			},
+		},
+		{
+			name: "when_headers_are_logged_should_mask_sensitive_headers",
+			fields: logrus.Fields{
+				"headers": http.Header{
+					"X-Api-Key":    {"key"},
+					"Content-Type": {"application/json"},
+				},
+			},
+			message:     "request",
+			expectedMsg: "request",
+			expectedField: map[string]any{
+				"headers": map[string]any{
+					"X-Api-Key":    []any{logx.Mask},
+					"Content-Type": []any{"application/json"},
+				},
+			},
+		},
+		{
+			name: "when_secret_value_is_logged_should_mask_it",
+			fields: logrus.Fields{
+				"dsn": "host=localhost password=db-pass",
+			},
+			message:     "connect failed with password db-pass",
+			expectedMsg: "connect failed with password " + logx.Mask,
+			expectedField: map[string]any{
+				"dsn": "host=localhost password=" + logx.Mask,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			hook := logx.NewRedactHook()
+			hook.SetSecrets("db-pass", "")
+
+			l := logrus.New()
+			l.SetOutput(&buf)
+			l.SetFormatter(&logrus.JSONFormatter{})
+			l.AddHook(hook)
+
+			l.WithFields(tt.fields).Info(tt.message)
+
+			var actual map[string]any
+			assert.NoError(t, json.Unmarshal(buf.Bytes(), &actual))
+			assert.Equal(t, tt.expectedMsg, actual["msg"])
+			for key, value := range tt.expectedField {
+				assert.Equal(t, value, actual[key])
+			}
+
+			// The caller's fields are left untouched.
+			for key, value := range tt.fields {
+				assert.NotEqual(t, logx.Mask, value, key)
+			}
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package logx
+
+import (
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+type sampleKey struct {
+	level   logrus.Level
+	message string
+}
+
+// sampler keeps high-volume entries in check: within every tick, the first
+// initial entries with the same level and message are kept, then one out of
+// every thereafter. Warnings and errors are never sampled.
+type sampler struct {
+	initial    uint64
+	thereafter uint64
+	tick       time.Duration
+
+	mu     sync.Mutex
+	window time.Time
+	counts map[sampleKey]uint64
+}
+
+func newSampler(initial, thereafter uint64, tick time.Duration) *sampler {
+	if initial == 0 {
+		return nil
+	}
+
+	return &sampler{
+		initial:    initial,
+		thereafter: thereafter,
+		tick:       tick,
+		counts:     make(map[sampleKey]uint64),
+	}
+}
+
+func (s *sampler) allow(entry *logrus.Entry) bool {
+	if s == nil || entry.Level < logrus.InfoLevel {
+		return true
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if entry.Time.Sub(s.window) >= s.tick {
+		s.window = entry.Time
+		clear(s.counts)
+	}
+
+	key := sampleKey{level: entry.Level, message: entry.Message}
+	n := s.counts[key] + 1
+	s.counts[key] = n
+
+	if n <= s.initial {
+		return true
+	}
+	if s.thereafter == 0 {
+		return false
+	}
+
+	return (n-s.initial)%s.thereafter == 0
+}
@@ -0,0 +1,88 @@
+package logx
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSampler_Allow(t *testing.T) {
+	start := time.Now()
+	entry := func(level logrus.Level, msg string, offset time.Duration) *logrus.Entry {
+		return &logrus.Entry{Level: level, Message: msg, Time: start.Add(offset)}
+	}
+
+	tests := []struct {
+		name     string
+		sampler  *sampler
+		entries  []*logrus.Entry
+		expected []bool
+	}{
+		{
+			name:    "when_sampling_is_disabled_should_keep_everything",
+			sampler: newSampler(0, 100, time.Second),
+			entries: []*logrus.Entry{
+				entry(logrus.InfoLevel, "request", 0),
+				entry(logrus.InfoLevel, "request", 0),
+			},
+			expected: []bool{true, true},
+		},
+		{
+			name:    "when_over_initial_should_keep_every_thereafter",
+			sampler: newSampler(2, 2, time.Second),
+			entries: []*logrus.Entry{
+				entry(logrus.InfoLevel, "request", 0),
+				entry(logrus.InfoLevel, "request", 0),
+				entry(logrus.InfoLevel, "request", 0),
+				entry(logrus.InfoLevel, "request", 0),
+				entry(logrus.InfoLevel, "request", 0),
+				entry(logrus.InfoLevel, "other", 0),
+			},
+			expected: []bool{true, true, false, true, false, true},
+		},
+		{
+			name:    "when_thereafter_is_zero_should_drop_after_initial",
+			sampler: newSampler(1, 0, time.Second),
+			entries: []*logrus.Entry{
+				entry(logrus.DebugLevel, "request", 0),
+				entry(logrus.DebugLevel, "request", 0),
+				entry(logrus.DebugLevel, "request", 0),
+			},
+			expected: []bool{true, false, false},
+		},
+		{
+			name:    "when_tick_elapses_should_start_over",
+			sampler: newSampler(1, 0, time.Second),
+			entries: []*logrus.Entry{
+				entry(logrus.InfoLevel, "request", 0),
+				entry(logrus.InfoLevel, "request", 500*time.Millisecond),
+				entry(logrus.InfoLevel, "request", 1100*time.Millisecond),
+			},
+			expected: []bool{true, false, true},
+		},
+		{
+			name:    "when_warning_or_error_should_never_sample",
+			sampler: newSampler(1, 0, time.Second),
+			entries: []*logrus.Entry{
+				entry(logrus.WarnLevel, "failed", 0),
+				entry(logrus.WarnLevel, "failed", 0),
+				entry(logrus.ErrorLevel, "failed", 0),
+				entry(logrus.ErrorLevel, "failed", 0),
+			},
+			expected: []bool{true, true, true, true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var actual []bool
+			for _, e := range tt.entries {
+				actual = append(actual, tt.sampler.allow(e))
+			}
+
+			assert.Equal(t, tt.expected, actual)
+		})
+	}
+}
@@ -0,0 +1,8 @@
+//go:build !unix
+
+package logx
+
+import "time"
+
+// watchSignals is a no-op where SIGUSR1/SIGUSR2 do not exist.
+func watchSignals(levels *Levels, debugFor time.Duration, stop <-chan struct{}) {}
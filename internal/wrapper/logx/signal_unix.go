@@ -0,0 +1,39 @@
+//go:build unix
+
+package logx
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// watchSignals turns debug logging on for debugFor on SIGUSR1 and restores
+// the default level on SIGUSR2, until stop is closed.
+func watchSignals(levels *Levels, debugFor time.Duration, stop <-chan struct{}) {
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, syscall.SIGUSR1, syscall.SIGUSR2)
+
+	go func() {
+		defer signal.Stop(ch)
+
+		for {
+			select {
+			case <-stop:
+				return
+			case sig := <-ch:
+				switch sig {
+				case syscall.SIGUSR1:
+					levels.SetLevel(logrus.DebugLevel, debugFor)
+					logrus.Warnf("log level set to debug for %s by %s", debugFor, sig)
+				case syscall.SIGUSR2:
+					levels.Reset()
+					logrus.Warnf("log level reset to %s by %s", levels.State().Default, sig)
+				}
+			}
+		}
+	}()
+}
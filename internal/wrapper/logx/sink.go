@@ -0,0 +1,117 @@
+package logx
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+// sinks are the outputs shared by LogX and the global logger. Stdout and the
+// rotated file are plain writers; syslog is a hook since it needs the level
+// of every entry.
+type sinks struct {
+	out     io.Writer
+	hooks   []logrus.Hook
+	closers []io.Closer
+	stop    chan struct{}
+}
+
+func newSinks(opts options, levels *Levels) *sinks {
+	s := &sinks{stop: make(chan struct{})}
+
+	var writers []io.Writer
+	for _, output := range opts.outputs {
+		switch output {
+		case OutputStdout:
+			writers = append(writers, os.Stdout)
+		case OutputFile:
+			file, err := s.file(opts)
+			if err != nil {
+				logrus.Warnf("log file output disabled: %v", err)
+				continue
+			}
+			writers = append(writers, file)
+		case OutputSyslog:
+			hook, err := newSyslogHook(opts, &filter{
+				levels:  levels,
+				sampler: newSampler(opts.samplingInitial, opts.samplingThereafter, opts.samplingTick),
+			})
+			if err != nil {
+				logrus.Warnf("log syslog output disabled: %v", err)
+				continue
+			}
+			s.hooks = append(s.hooks, hook)
+			s.closers = append(s.closers, hook)
+		default:
+			logrus.Warnf("unknown log output %q ignored", output)
+		}
+	}
+
+	switch {
+	case len(writers) == 1:
+		s.out = writers[0]
+	case len(writers) > 1:
+		s.out = io.MultiWriter(writers...)
+	case len(s.hooks) > 0:
+		// syslog only
+		s.out = io.Discard
+	default:
+		s.out = os.Stdout
+	}
+
+	return s
+}
+
+// file opens the rotated log file and, when LOG_FILE_ROTATE_INTERVAL is set,
+// rotates it on that interval on top of the size limit.
+func (s *sinks) file(opts options) (*lumberjack.Logger, error) {
+	if err := os.MkdirAll(filepath.Dir(opts.filePath), 0o755); err != nil {
+		return nil, err
+	}
+
+	file := &lumberjack.Logger{
+		Filename:   opts.filePath,
+		MaxSize:    opts.fileMaxSize,
+		MaxAge:     opts.fileMaxAge,
+		MaxBackups: opts.fileMaxBackups,
+		Compress:   opts.fileCompress,
+		LocalTime:  true,
+	}
+	s.closers = append(s.closers, file)
+
+	if opts.fileRotateInterval > 0 {
+		go func() {
+			ticker := time.NewTicker(opts.fileRotateInterval)
+			defer ticker.Stop()
+
+			for {
+				select {
+				case <-s.stop:
+					return
+				case <-ticker.C:
+					if err := file.Rotate(); err != nil {
+						logrus.Warnf("failed to rotate log file: %v", err)
+					}
+				}
+			}
+		}()
+	}
+
+	return file, nil
+}
+
+func (s *sinks) Close() error {
+	close(s.stop)
+
+	var errs []error
+	for _, c := range s.closers {
+		errs = append(errs, c.Close())
+	}
+
+	return errors.Join(errs...)
+}
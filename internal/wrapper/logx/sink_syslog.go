@@ -0,0 +1,68 @@
+//go:build !windows && !plan9
+
+package logx
+
+import (
+	"log/syslog"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// syslogHook sends every kept entry to the local syslog daemon with the
+// priority matching its level.
+type syslogHook struct {
+	w         *syslog.Writer
+	formatter logrus.Formatter
+	filter    *filter
+}
+
+func newSyslogHook(opts options, f *filter) (*syslogHook, error) {
+	w, err := syslog.New(syslog.LOG_INFO|syslog.LOG_DAEMON, opts.syslogTag)
+	if err != nil {
+		return nil, err
+	}
+
+	// syslog stamps every message itself
+	var formatter logrus.Formatter = &logrus.TextFormatter{DisableTimestamp: true}
+	if opts.format == FormatJSON {
+		formatter = &logrus.JSONFormatter{}
+	}
+
+	return &syslogHook{w: w, formatter: formatter, filter: f}, nil
+}
+
+func (h *syslogHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (h *syslogHook) Fire(entry *logrus.Entry) error {
+	if !h.filter.keep(entry) {
+		return nil
+	}
+
+	line, err := h.formatter.Format(entry)
+	if err != nil {
+		return err
+	}
+	msg := strings.TrimSuffix(string(line), "\n")
+
+	switch entry.Level {
+	case logrus.PanicLevel:
+		return h.w.Emerg(msg)
+	case logrus.FatalLevel:
+		return h.w.Crit(msg)
+	case logrus.ErrorLevel:
+		return h.w.Err(msg)
+	case logrus.WarnLevel:
+		return h.w.Warning(msg)
+	case logrus.InfoLevel:
+		return h.w.Info(msg)
+	default:
+		return h.w.Debug(msg)
+	}
+}
+
+func (h *syslogHook) Close() error {
+	return h.w.Close()
+}
@@ -0,0 +1,21 @@
+//go:build windows || plan9
+
+package logx
+
+import (
+	"errors"
+
+	"github.com/sirupsen/logrus"
+)
+
+type syslogHook struct {
+	logrus.Hook
+}
+
+func newSyslogHook(opts options, f *filter) (*syslogHook, error) {
+	return nil, errors.New("syslog is not supported on this platform")
+}
+
+func (h *syslogHook) Close() error {
+	return nil
+}
@@ -0,0 +1,80 @@
+package logx
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSinks(t *testing.T) {
+	tests := []struct {
+		name           string
+		outputs        []string
+		rotateInterval time.Duration
+		assert         func(t *testing.T, s *sinks, path string)
+	}{
+		{
+			name:    "when_no_output_is_set_should_default_to_stdout",
+			outputs: nil,
+			assert: func(t *testing.T, s *sinks, path string) {
+				assert.Equal(t, os.Stdout, s.out)
+				assert.NoFileExists(t, path)
+			},
+		},
+		{
+			name:    "when_output_is_unknown_should_fall_back_to_stdout",
+			outputs: []string{"kafka"},
+			assert: func(t *testing.T, s *sinks, path string) {
+				assert.Equal(t, os.Stdout, s.out)
+			},
+		},
+		{
+			name:    "when_output_is_file_should_write_to_file",
+			outputs: []string{OutputFile},
+			assert: func(t *testing.T, s *sinks, path string) {
+				_, err := s.out.Write([]byte("hello\n"))
+				assert.NoError(t, err)
+
+				data, err := os.ReadFile(path)
+				assert.NoError(t, err)
+				assert.Equal(t, "hello\n", string(data))
+			},
+		},
+		{
+			name:           "when_rotate_interval_is_set_should_rotate_file",
+			outputs:        []string{OutputStdout, OutputFile},
+			rotateInterval: 50 * time.Millisecond,
+			assert: func(t *testing.T, s *sinks, path string) {
+				_, err := s.out.Write([]byte("hello\n"))
+				assert.NoError(t, err)
+
+				assert.Eventually(t, func() bool {
+					matches, _ := filepath.Glob(filepath.Join(filepath.Dir(path), "app-*.log*"))
+					return len(matches) > 0
+				}, 2*time.Second, 20*time.Millisecond)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "logs", "app.log")
+			opts := options{
+				outputs:            tt.outputs,
+				filePath:           path,
+				fileMaxSize:        1,
+				fileMaxBackups:     1,
+				fileRotateInterval: tt.rotateInterval,
+			}
+
+			s := newSinks(opts, newLevels(logrus.InfoLevel))
+			defer s.Close()
+
+			tt.assert(t, s, path)
+		})
+	}
+}
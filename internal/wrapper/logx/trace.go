@@ -0,0 +1,30 @@
+package logx
+
+import (
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TraceHook adds the trace_id and span_id of the span in the entry context,
+// so log lines can be joined with their trace.
+type TraceHook struct{}
+
+func (h TraceHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (h TraceHook) Fire(entry *logrus.Entry) error {
+	if entry.Context == nil {
+		return nil
+	}
+
+	sc := trace.SpanContextFromContext(entry.Context)
+	if !sc.IsValid() {
+		return nil
+	}
+
+	entry.Data["trace_id"] = sc.TraceID().String()
+	entry.Data["span_id"] = sc.SpanID().String()
+
+	return nil
+}
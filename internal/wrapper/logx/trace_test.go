@@ -0,0 +1,56 @@
+package logx_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"go-fiber-api/internal/wrapper/logx"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestTraceHook(t *testing.T) {
+	sc := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{0x4b, 0xf9, 0x2f, 0x35, 0x77, 0xb3, 0x4d, 0xa6, 0xa3, 0xce, 0x92, 0x9d, 0x0e, 0x0e, 0x47, 0x36},
+		SpanID:     trace.SpanID{0x00, 0xf0, 0x67, 0xaa, 0x0b, 0xa9, 0x02, 0xb7},
+		TraceFlags: trace.FlagsSampled,
+	})
+
+	tests := []struct {
+		name            string
+		ctx             context.Context
+		expectedTraceID any
+		expectedSpanID  any
+	}{
+		{
+			name:            "when_context_has_span_should_add_ids",
+			ctx:             trace.ContextWithSpanContext(context.Background(), sc),
+			expectedTraceID: "4bf92f3577b34da6a3ce929d0e0e4736",
+			expectedSpanID:  "00f067aa0ba902b7",
+		},
+		{
+			name: "when_context_has_no_span_should_not_add_ids",
+			ctx:  context.Background(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			l := logrus.New()
+			l.SetOutput(&buf)
+			l.SetFormatter(&logrus.JSONFormatter{})
+			l.AddHook(logx.TraceHook{})
+
+			l.WithContext(tt.ctx).Info("traced")
+
+			var actual map[string]any
+			assert.NoError(t, json.Unmarshal(buf.Bytes(), &actual))
+			assert.Equal(t, tt.expectedTraceID, actual["trace_id"])
+			assert.Equal(t, tt.expectedSpanID, actual["span_id"])
+		})
+	}
+}
@@ -5,6 +5,8 @@
 package logx
 
 import (
+	"go-fiber-api/internal/core/lifecycle"
+
 	"github.com/google/wire"
 )
 
@@ -12,7 +14,7 @@ var ProviderSet = wire.NewSet(
 	Provide,
 )
 
-func Wire() (*LogX, error) {
+func Wire(lc *lifecycle.Lifecycle) (*LogX, error) {
 	wire.Build(ProviderSet)
 	return &LogX{}, nil
 }
@@ -8,12 +8,13 @@ package logx
 
 import (
 	"github.com/google/wire"
+	"go-fiber-api/internal/core/lifecycle"
 )
 
 // Injectors from wire.go:
 
-func Wire() (*LogX, error) {
-	logX := Provide()
+func Wire(lc *lifecycle.Lifecycle) (*LogX, error) {
+	logX := Provide(lc)
 	return logX, nil
 }
 
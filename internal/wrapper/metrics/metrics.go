@@ -0,0 +1,177 @@
+package metrics
+
+import (
+	"database/sql"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/adaptor"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/sirupsen/logrus"
+)
+
+const namespace = "go_fiber_api"
+
+// Cache results recorded by CacheResult.
+const (
+	CacheHit    = "hit"
+	CacheMiss   = "miss"
+	CacheBypass = "bypass"
+)
+
+// Metrics owns the Prometheus registry of the application and the collectors
+// shared by the HTTP server, the database, Redis and the middlewares. Every
+// method is safe to call on a nil *Metrics, which records nothing, so
+// packages can be used without metrics in tests.
+type Metrics struct {
+	registry *prometheus.Registry
+
+	httpRequests    *prometheus.CounterVec
+	httpDuration    *prometheus.HistogramVec
+	dbQueryDuration *prometheus.HistogramVec
+	redisDuration   *prometheus.HistogramVec
+	redisErrors     *prometheus.CounterVec
+	cacheRequests   *prometheus.CounterVec
+	authFailures    *prometheus.CounterVec
+}
+
+// New creates Metrics backed by its own registry, with the Go runtime and
+// process collectors registered.
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		httpRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "http",
+			Name:      "requests_total",
+			Help:      "HTTP requests by method, route template and status.",
+		}, []string{"method", "route", "status"}),
+		httpDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "http",
+			Name:      "request_duration_seconds",
+			Help:      "HTTP request latency by method, route template and status.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route", "status"}),
+		dbQueryDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "db",
+			Name:      "query_duration_seconds",
+			Help:      "GORM query latency by operation and table.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"operation", "table"}),
+		redisDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "redis",
+			Name:      "command_duration_seconds",
+			Help:      "Redis command latency by command.",
+			Buckets:   []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1},
+		}, []string{"command"}),
+		redisErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "redis",
+			Name:      "command_errors_total",
+			Help:      "Failed Redis commands by command, missing keys excluded.",
+		}, []string{"command"}),
+		cacheRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "cache",
+			Name:      "requests_total",
+			Help:      "Requests seen by the cache middleware by result (hit, miss, bypass).",
+		}, []string{"result"}),
+		authFailures: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "auth",
+			Name:      "failures_total",
+			Help:      "Rejected authentication attempts by method and reason.",
+		}, []string{"method", "reason"}),
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.httpRequests,
+		m.httpDuration,
+		m.dbQueryDuration,
+		m.redisDuration,
+		m.redisErrors,
+		m.cacheRequests,
+		m.authFailures,
+	)
+
+	return m
+}
+
+// Registry returns the registry the collectors are registered with, or nil.
+func (m *Metrics) Registry() *prometheus.Registry {
+	if m == nil {
+		return nil
+	}
+
+	return m.registry
+}
+
+// Handler serves the registry in the Prometheus exposition format.
+func (m *Metrics) Handler() fiber.Handler {
+	return adaptor.HTTPHandler(promhttp.HandlerFor(m.Registry(), promhttp.HandlerOpts{}))
+}
+
+func (m *Metrics) ObserveHTTP(method, route string, status int, d time.Duration) {
+	if m == nil {
+		return
+	}
+
+	code := strconv.Itoa(status)
+	m.httpRequests.WithLabelValues(method, route, code).Inc()
+	m.httpDuration.WithLabelValues(method, route, code).Observe(d.Seconds())
+}
+
+func (m *Metrics) ObserveQuery(operation, table string, d time.Duration) {
+	if m == nil {
+		return
+	}
+
+	m.dbQueryDuration.WithLabelValues(operation, table).Observe(d.Seconds())
+}
+
+// ObserveRedis records a command; err must already exclude redis.Nil.
+func (m *Metrics) ObserveRedis(command string, d time.Duration, err error) {
+	if m == nil {
+		return
+	}
+
+	m.redisDuration.WithLabelValues(command).Observe(d.Seconds())
+	if err != nil {
+		m.redisErrors.WithLabelValues(command).Inc()
+	}
+}
+
+func (m *Metrics) CacheResult(result string) {
+	if m == nil {
+		return
+	}
+
+	m.cacheRequests.WithLabelValues(result).Inc()
+}
+
+func (m *Metrics) AuthFailure(method, reason string) {
+	if m == nil {
+		return
+	}
+
+	m.authFailures.WithLabelValues(method, reason).Inc()
+}
+
+// RegisterDBStats exposes the connection pool stats of db under name.
+func (m *Metrics) RegisterDBStats(db *sql.DB, name string) {
+	if m == nil {
+		return
+	}
+
+	if err := m.registry.Register(collectors.NewDBStatsCollector(db, name)); err != nil {
+		logrus.Warnf("failed to register db stats collector: %v", err)
+	}
+}
@@ -0,0 +1,99 @@
+package metrics_test
+
+import (
+	"errors"
+	"go-fiber-api/internal/wrapper/metrics"
+	"io"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetrics_Handler(t *testing.T) {
+	tests := []struct {
+		name     string
+		record   func(m *metrics.Metrics)
+		expected []string
+	}{
+		{
+			name: "when_http_request_is_observed_should_expose_route_template",
+			record: func(m *metrics.Metrics) {
+				m.ObserveHTTP(fiber.MethodGet, "/users/:id", fiber.StatusOK, 10*time.Millisecond)
+			},
+			expected: []string{
+				`go_fiber_api_http_requests_total{method="GET",route="/users/:id",status="200"} 1`,
+				`go_fiber_api_http_request_duration_seconds_count{method="GET",route="/users/:id",status="200"} 1`,
+			},
+		},
+		{
+			name: "when_query_is_observed_should_expose_operation_and_table",
+			record: func(m *metrics.Metrics) {
+				m.ObserveQuery("query", "users", time.Millisecond)
+			},
+			expected: []string{
+				`go_fiber_api_db_query_duration_seconds_count{operation="query",table="users"} 1`,
+			},
+		},
+		{
+			name: "when_redis_command_fails_should_count_error",
+			record: func(m *metrics.Metrics) {
+				m.ObserveRedis("get", time.Millisecond, nil)
+				m.ObserveRedis("get", time.Millisecond, errors.New("mock error"))
+			},
+			expected: []string{
+				`go_fiber_api_redis_command_duration_seconds_count{command="get"} 2`,
+				`go_fiber_api_redis_command_errors_total{command="get"} 1`,
+			},
+		},
+		{
+			name: "when_cache_and_auth_are_recorded_should_expose_them",
+			record: func(m *metrics.Metrics) {
+				m.CacheResult(metrics.CacheHit)
+				m.CacheResult(metrics.CacheMiss)
+				m.AuthFailure("apikey", "missing")
+			},
+			expected: []string{
+				`go_fiber_api_cache_requests_total{result="hit"} 1`,
+				`go_fiber_api_cache_requests_total{result="miss"} 1`,
+				`go_fiber_api_auth_failures_total{method="apikey",reason="missing"} 1`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := metrics.New()
+			tt.record(m)
+
+			app := fiber.New()
+			app.Get("/metrics", m.Handler())
+
+			resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/metrics", nil))
+			assert.NoError(t, err)
+			assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+			body, err := io.ReadAll(resp.Body)
+			assert.NoError(t, err)
+			for _, expected := range tt.expected {
+				assert.Contains(t, string(body), expected)
+			}
+		})
+	}
+}
+
+func TestMetrics_Nil(t *testing.T) {
+	var m *metrics.Metrics
+
+	assert.NotPanics(t, func() {
+		m.ObserveHTTP(fiber.MethodGet, "/", fiber.StatusOK, time.Millisecond)
+		m.ObserveQuery("query", "users", time.Millisecond)
+		m.ObserveRedis("get", time.Millisecond, nil)
+		m.CacheResult(metrics.CacheHit)
+		m.AuthFailure("apikey", "missing")
+		m.RegisterDBStats(nil, "db")
+	})
+	assert.Nil(t, m.Registry())
+}
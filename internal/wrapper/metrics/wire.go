@@ -0,0 +1,7 @@
+package metrics
+
+import "github.com/google/wire"
+
+var ProviderSet = wire.NewSet(
+	New,
+)
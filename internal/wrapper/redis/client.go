@@ -7,68 +7,164 @@ import (
 	"encoding/json"
 	"fmt"
 	"go-fiber-api/internal/core/config"
-	"sync"
+	"go-fiber-api/internal/core/lifecycle"
+	"go-fiber-api/internal/wrapper/metrics"
+	"go-fiber-api/internal/wrapper/tracing"
+	"sync/atomic"
 	"time"
 
 	"github.com/redis/go-redis/v9"
 	"github.com/sirupsen/logrus"
 )
 
-var (
-	rc     *clientImpl
-	rcOnce sync.Once
+// Nil is returned by reads when the key does not exist.
+const Nil = redis.Nil
+
+type (
+	Script    = redis.Script
+	Pipeliner = redis.Pipeliner
+	Cmder     = redis.Cmder
 )
 
+// NewScript wraps a Lua script, run with EVALSHA and falling back to EVAL.
+func NewScript(src string) *Script {
+	return redis.NewScript(src)
+}
+
 // RedisClientInterface defines the methods that a Redis client should implement
 type Client interface {
+	// Set, Get and Del use context.Background(), prefer the Context variants
+	// inside a request.
 	Set(key string, value any, ttl ...int) error
 	Get(key string, out any) error
 	Del(key string) error
+
+	SetContext(ctx context.Context, key string, value any, ttl ...int) error
+	GetContext(ctx context.Context, key string, out any) error
+	DelContext(ctx context.Context, keys ...string) error
+
+	// MGet returns the raw value of every key, nil for missing ones. In
+	// cluster mode, keys of different slots are read one by one in a
+	// pipeline rather than failing with CROSSSLOT.
+	MGet(ctx context.Context, keys ...string) ([][]byte, error)
+	// MSet stores every value without expiration. In cluster mode, keys are
+	// set one by one in a pipeline, so they are not set at once there.
+	MSet(ctx context.Context, values map[string]any) error
+	SetNX(ctx context.Context, key string, value any, ttl time.Duration) (bool, error)
+	// GetDel reads and deletes key at once, so only one caller gets it.
+	GetDel(ctx context.Context, key string, out any) error
+
+	Incr(ctx context.Context, key string) (int64, error)
+	IncrBy(ctx context.Context, key string, value int64) (int64, error)
+	Expire(ctx context.Context, key string, ttl time.Duration) (bool, error)
+	TTL(ctx context.Context, key string) (time.Duration, error)
+
+	HSet(ctx context.Context, key string, values map[string]any) error
+	HGet(ctx context.Context, key, field string, out any) error
+	HGetAll(ctx context.Context, key string) (map[string]string, error)
+	HDel(ctx context.Context, key string, fields ...string) error
+
+	SAdd(ctx context.Context, key string, members ...any) error
+	SRem(ctx context.Context, key string, members ...any) error
+	SMembers(ctx context.Context, key string) ([]string, error)
+	SIsMember(ctx context.Context, key string, member any) (bool, error)
+
+	// Pipelined runs fn in a pipeline. Its writes bypass the local tier, so
+	// it must not write keys read with Get.
+	Pipelined(ctx context.Context, fn func(Pipeliner) error) ([]Cmder, error)
+	Eval(ctx context.Context, script *Script, keys []string, args ...any) (any, error)
+
+	Publish(ctx context.Context, channel string, message any) error
+	Subscribe(ctx context.Context, channels ...string) *redis.PubSub
+	Stats() Stats
+	Ping(ctx context.Context) error
 	Close() error
 }
 
+// Stats holds the hit/miss counters of every cache tier served by a Client.
+// Local counters stay at zero unless the in-process tier is enabled.
+type Stats struct {
+	LocalHits    uint64 `json:"localHits"`
+	LocalMisses  uint64 `json:"localMisses"`
+	RemoteHits   uint64 `json:"remoteHits"`
+	RemoteMisses uint64 `json:"remoteMisses"`
+}
+
 type clientImpl struct {
 	cfg    *config.Configuration
-	client *redis.Client
+	client redis.UniversalClient
+
+	hits   atomic.Uint64
+	misses atomic.Uint64
 }
 
-func ProvideClient(cfg *config.Configuration) (Client, error) {
-	var err error
-	rcOnce.Do(func() {
-		addr := fmt.Sprintf("%v:%v", cfg.RedisHost, cfg.RedisPort)
-		options := &redis.Options{
-			Addr: addr,
-			DB:   cfg.RedisDB,
-		}
-		if len(cfg.RedisPassword) > 0 {
-			options.Password = cfg.RedisPassword
-		}
-		client := redis.NewClient(options)
+// ProvideClient connects a new client, closed once lc stops.
+func ProvideClient(cfg *config.Configuration, m *metrics.Metrics, t *tracing.Tracing, lc *lifecycle.Lifecycle) (Client, error) {
+	client, err := newUniversalClient(cfg)
+	if err != nil {
+		logrus.Errorf("error configuring redis: %v", err)
+		return nil, err
+	}
 
-		var pong string
-		pong, err = client.Ping(context.Background()).Result()
-		if err != nil {
-			logrus.Errorf("error connecting to redis: %v", err)
-			return
-		} else {
-			logrus.Infof("redis connected: %s", pong)
-		}
+	if m != nil {
+		client.AddHook(metricsHook{m: m})
+	}
+	if t != nil {
+		client.AddHook(tracingHook{t: t})
+	}
+
+	// An unreachable Redis must not stop the application from starting;
+	// go-redis reconnects lazily and callers degrade until it is back.
+	pong, pingErr := client.Ping(context.Background()).Result()
+	if pingErr != nil {
+		logrus.Warnf("error connecting to redis: %v", pingErr)
+	} else {
+		logrus.Infof("redis connected (%s): %s", mode(cfg), pong)
+	}
+
+	var rc Client = &clientImpl{
+		cfg:    cfg,
+		client: client,
+	}
 
-		rc = &clientImpl{
-			cfg:    cfg,
-			client: client,
+	if cfg.CacheLocalEnabled {
+		tiered, err := provideTieredClient(cfg, rc.(*clientImpl))
+		switch {
+		case err == nil:
+			rc = tiered
+		case pingErr != nil:
+			logrus.Warnf("local cache tier disabled, redis unavailable: %v", err)
+		default:
+			_ = client.Close()
+			return nil, err
 		}
+	}
+
+	lc.Append(lifecycle.Hook{
+		Name: "redis",
+		OnStop: func(context.Context) error {
+			return rc.Close()
+		},
 	})
-	return rc, err
-}
 
-func ResetProvideClient() {
-	rcOnce = sync.Once{}
+	return rc, nil
 }
 
 const defaultTTL = 300 // Adjust this value to set a default TTL
 
 func (r *clientImpl) Set(key string, value any, ttl ...int) error {
+	return r.SetContext(context.Background(), key, value, ttl...)
+}
+
+func (r *clientImpl) Get(key string, out any) error {
+	return r.GetContext(context.Background(), key, out)
+}
+
+func (r *clientImpl) Del(key string) error {
+	return r.DelContext(context.Background(), key)
+}
+
+func (r *clientImpl) SetContext(ctx context.Context, key string, value any, ttl ...int) error {
 	if key == "" {
 		return fmt.Errorf("key cannot be empty")
 	}
@@ -84,34 +180,85 @@ func (r *clientImpl) Set(key string, value any, ttl ...int) error {
 
 	timeS := time.Duration(expiration) * time.Second
 
-	var dataByteArray []byte
-	var err error
-
-	switch v := value.(type) {
-	case []byte:
-		dataByteArray = v
-	default:
-		dataByteArray, err = json.Marshal(value)
-		if err != nil {
-			return fmt.Errorf("failed to marshal value: %v", err)
-		}
+	dataByteArray, err := Encode(value)
+	if err != nil {
+		return err
 	}
 
-	return r.client.Set(context.Background(), key, dataByteArray, timeS).Err()
+	return r.client.Set(ctx, key, dataByteArray, timeS).Err()
 }
 
-func (r *clientImpl) Get(key string, out any) error {
-	val, err := r.client.Get(context.Background(), key).Result()
+func (r *clientImpl) GetContext(ctx context.Context, key string, out any) error {
+	val, err := r.getRaw(ctx, key)
 	if err != nil {
 		return err
 	}
 
-	switch v := out.(type) {
-	case *[]byte:
-		out = []byte(val)
+	return Decode(val, out)
+}
+
+func (r *clientImpl) DelContext(ctx context.Context, keys ...string) error {
+	return r.client.Del(ctx, keys...).Err()
+}
+
+// getRaw reads the stored bytes of key and records a remote hit or miss.
+func (r *clientImpl) getRaw(ctx context.Context, key string) ([]byte, error) {
+	val, err := r.client.Get(ctx, key).Bytes()
+	if err != nil {
+		if err == redis.Nil {
+			r.misses.Add(1)
+		}
+		return nil, err
+	}
+
+	r.hits.Add(1)
+	return val, nil
+}
+
+// getWithTTL reads the stored bytes of key along with its remaining TTL, -1
+// for a key without expiration, and records a remote hit or miss.
+func (r *clientImpl) getWithTTL(ctx context.Context, key string) ([]byte, time.Duration, error) {
+	var (
+		get  *redis.StringCmd
+		pttl *redis.DurationCmd
+	)
+	_, err := r.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		get = pipe.Get(ctx, key)
+		pttl = pipe.PTTL(ctx, key)
 		return nil
-	default:
-		return json.Unmarshal([]byte(val), v)
+	})
+	if err != nil && err != redis.Nil {
+		return nil, 0, err
+	}
+
+	val, err := get.Bytes()
+	if err != nil {
+		if err == redis.Nil {
+			r.misses.Add(1)
+		}
+		return nil, 0, err
+	}
+
+	r.hits.Add(1)
+	return val, pttl.Val(), nil
+}
+
+func (r *clientImpl) Publish(ctx context.Context, channel string, message any) error {
+	return r.client.Publish(ctx, channel, message).Err()
+}
+
+func (r *clientImpl) Subscribe(ctx context.Context, channels ...string) *redis.PubSub {
+	return r.client.Subscribe(ctx, channels...)
+}
+
+func (r *clientImpl) Ping(ctx context.Context) error {
+	return r.client.Ping(ctx).Err()
+}
+
+func (r *clientImpl) Stats() Stats {
+	return Stats{
+		RemoteHits:   r.hits.Load(),
+		RemoteMisses: r.misses.Load(),
 	}
 }
 
@@ -119,6 +266,28 @@ func (r *clientImpl) Close() error {
 	return r.client.Close()
 }
 
-func (r *clientImpl) Del(key string) error {
-	return r.client.Del(context.Background(), key).Err()
+// Encode serializes value the way the client stores it: as is for []byte,
+// as JSON otherwise.
+func Encode(value any) ([]byte, error) {
+	switch v := value.(type) {
+	case []byte:
+		return v, nil
+	default:
+		data, err := json.Marshal(value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal value: %v", err)
+		}
+		return data, nil
+	}
+}
+
+// Decode reads into out a value serialized by Encode.
+func Decode(val []byte, out any) error {
+	switch v := out.(type) {
+	case *[]byte:
+		*v = val
+		return nil
+	default:
+		return json.Unmarshal(val, out)
+	}
 }
@@ -5,16 +5,19 @@ import (
 	"testing"
 
 	"go-fiber-api/internal/core/config"
+	"go-fiber-api/internal/core/lifecycle"
 
 	"github.com/stretchr/testify/assert"
 )
 
 func TestRedisClient_Set_Byte(t *testing.T) {
 	cfg := &config.Configuration{
-		RedisHost:     "localhost",
-		RedisPort:     "6379",
-		RedisPassword: "",
-		RedisDB:       10,
+		Redis: config.RedisConfig{
+			Host:     "localhost",
+			Port:     "6379",
+			Password: "",
+			DB:       10,
+		},
 	}
 
 	tests := []struct {
@@ -69,9 +72,9 @@ func TestRedisClient_Set_Byte(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			client, _ := ProvideClient(cfg)
+			client, _ := ProvideClient(cfg, nil, nil, lifecycle.New())
 			c := client.(*clientImpl)
-			defer ResetProvideClient()
+			defer client.Close()
 			err := c.Set(tt.key, tt.value, tt.ttl...)
 
 			if tt.wantErr {
@@ -86,10 +89,12 @@ func TestRedisClient_Set_Byte(t *testing.T) {
 
 func TestRedisClient_Set_String(t *testing.T) {
 	cfg := &config.Configuration{
-		RedisHost:     "localhost",
-		RedisPort:     "6379",
-		RedisPassword: "",
-		RedisDB:       10,
+		Redis: config.RedisConfig{
+			Host:     "localhost",
+			Port:     "6379",
+			Password: "",
+			DB:       10,
+		},
 	}
 
 	tests := []struct {
@@ -152,9 +157,9 @@ func TestRedisClient_Set_String(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			client, _ := ProvideClient(cfg)
+			client, _ := ProvideClient(cfg, nil, nil, lifecycle.New())
 			c := client.(*clientImpl)
-			defer ResetProvideClient()
+			defer client.Close()
 
 			err := c.Set(tt.key, tt.value, tt.ttl...)
 
@@ -164,7 +169,7 @@ func TestRedisClient_Set_String(t *testing.T) {
 			} else {
 				assert.NoError(t, err)
 
-				var val []byte
+				var val string
 				err := c.Get(tt.key, &val)
 				assert.NoError(t, err)
 				assert.Equal(t, tt.value, val)
@@ -175,10 +180,12 @@ func TestRedisClient_Set_String(t *testing.T) {
 
 func TestRedisClient_Set_Model(t *testing.T) {
 	cfg := &config.Configuration{
-		RedisHost:     "localhost",
-		RedisPort:     "6379",
-		RedisPassword: "",
-		RedisDB:       10,
+		Redis: config.RedisConfig{
+			Host:     "localhost",
+			Port:     "6379",
+			Password: "",
+			DB:       10,
+		},
 	}
 
 	tests := []struct {
@@ -203,9 +210,9 @@ func TestRedisClient_Set_Model(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			client, _ := ProvideClient(cfg)
+			client, _ := ProvideClient(cfg, nil, nil, lifecycle.New())
 			c := client.(*clientImpl)
-			defer ResetProvideClient()
+			defer client.Close()
 
 			err := c.Set(tt.key, tt.value, tt.ttl...)
 
@@ -226,10 +233,12 @@ func TestRedisClient_Set_Model(t *testing.T) {
 
 func TestRedisClient_Del(t *testing.T) {
 	cfg := &config.Configuration{
-		RedisHost:     "localhost",
-		RedisPort:     "6379",
-		RedisPassword: "",
-		RedisDB:       10,
+		Redis: config.RedisConfig{
+			Host:     "localhost",
+			Port:     "6379",
+			Password: "",
+			DB:       10,
+		},
 	}
 
 	tests := []struct {
@@ -247,9 +256,9 @@ func TestRedisClient_Del(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			client, _ := ProvideClient(cfg)
+			client, _ := ProvideClient(cfg, nil, nil, lifecycle.New())
 			c := client.(*clientImpl)
-			defer ResetProvideClient()
+			defer client.Close()
 
 			err := c.Del(tt.key)
 
@@ -266,10 +275,12 @@ func TestRedisClient_Del(t *testing.T) {
 
 func TestRedisClient_Close(t *testing.T) {
 	cfg := &config.Configuration{
-		RedisHost:     "localhost",
-		RedisPort:     "6379",
-		RedisPassword: "",
-		RedisDB:       10,
+		Redis: config.RedisConfig{
+			Host:     "localhost",
+			Port:     "6379",
+			Password: "",
+			DB:       10,
+		},
 	}
 
 	tests := []struct {
@@ -286,9 +297,9 @@ func TestRedisClient_Close(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			client, _ := ProvideClient(cfg)
+			client, _ := ProvideClient(cfg, nil, nil, lifecycle.New())
 			c := client.(*clientImpl)
-			defer ResetProvideClient()
+			defer client.Close()
 
 			err := c.Close()
 
@@ -0,0 +1,187 @@
+package redis
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// inCluster reports whether the keys of a command may live on several
+// nodes: MGET and MSET then fail with CROSSSLOT, unless every key hashes to
+// the same slot.
+func (r *clientImpl) inCluster() bool {
+	_, ok := r.client.(*redis.ClusterClient)
+	return ok
+}
+
+func (r *clientImpl) MGet(ctx context.Context, keys ...string) ([][]byte, error) {
+	if r.inCluster() {
+		return r.mgetPipelined(ctx, keys...)
+	}
+
+	vals, err := r.client.MGet(ctx, keys...).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([][]byte, len(vals))
+	for i, v := range vals {
+		if s, ok := v.(string); ok {
+			result[i] = []byte(s)
+		}
+	}
+
+	return result, nil
+}
+
+// mgetPipelined is MGet running a GET per key, which a cluster client
+// sends to the node of each key.
+func (r *clientImpl) mgetPipelined(ctx context.Context, keys ...string) ([][]byte, error) {
+	cmds := make([]*redis.StringCmd, len(keys))
+	_, err := r.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for i, key := range keys {
+			cmds[i] = pipe.Get(ctx, key)
+		}
+		return nil
+	})
+	if err != nil && err != redis.Nil {
+		return nil, err
+	}
+
+	result := make([][]byte, len(cmds))
+	for i, cmd := range cmds {
+		val, err := cmd.Bytes()
+		switch {
+		case err == redis.Nil:
+		case err != nil:
+			return nil, err
+		default:
+			result[i] = val
+		}
+	}
+
+	return result, nil
+}
+
+func (r *clientImpl) MSet(ctx context.Context, values map[string]any) error {
+	if r.inCluster() {
+		return r.msetPipelined(ctx, values)
+	}
+
+	pairs := make([]any, 0, len(values)*2)
+	for key, value := range values {
+		data, err := Encode(value)
+		if err != nil {
+			return err
+		}
+		pairs = append(pairs, key, data)
+	}
+
+	return r.client.MSet(ctx, pairs...).Err()
+}
+
+// msetPipelined is MSet running a SET per key, which a cluster client sends
+// to the node of each key. The keys are no longer set at once.
+func (r *clientImpl) msetPipelined(ctx context.Context, values map[string]any) error {
+	_, err := r.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for key, value := range values {
+			data, err := Encode(value)
+			if err != nil {
+				return err
+			}
+			pipe.Set(ctx, key, data, 0)
+		}
+		return nil
+	})
+
+	return err
+}
+
+func (r *clientImpl) SetNX(ctx context.Context, key string, value any, ttl time.Duration) (bool, error) {
+	data, err := Encode(value)
+	if err != nil {
+		return false, err
+	}
+
+	return r.client.SetNX(ctx, key, data, ttl).Result()
+}
+
+func (r *clientImpl) GetDel(ctx context.Context, key string, out any) error {
+	val, err := r.client.GetDel(ctx, key).Bytes()
+	if err != nil {
+		return err
+	}
+
+	return Decode(val, out)
+}
+
+func (r *clientImpl) Incr(ctx context.Context, key string) (int64, error) {
+	return r.client.Incr(ctx, key).Result()
+}
+
+func (r *clientImpl) IncrBy(ctx context.Context, key string, value int64) (int64, error) {
+	return r.client.IncrBy(ctx, key, value).Result()
+}
+
+func (r *clientImpl) Expire(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	return r.client.Expire(ctx, key, ttl).Result()
+}
+
+func (r *clientImpl) TTL(ctx context.Context, key string) (time.Duration, error) {
+	return r.client.TTL(ctx, key).Result()
+}
+
+func (r *clientImpl) HSet(ctx context.Context, key string, values map[string]any) error {
+	fields := make(map[string]any, len(values))
+	for field, value := range values {
+		data, err := Encode(value)
+		if err != nil {
+			return err
+		}
+		fields[field] = data
+	}
+
+	return r.client.HSet(ctx, key, fields).Err()
+}
+
+func (r *clientImpl) HGet(ctx context.Context, key, field string, out any) error {
+	val, err := r.client.HGet(ctx, key, field).Bytes()
+	if err != nil {
+		return err
+	}
+
+	return Decode(val, out)
+}
+
+func (r *clientImpl) HGetAll(ctx context.Context, key string) (map[string]string, error) {
+	return r.client.HGetAll(ctx, key).Result()
+}
+
+func (r *clientImpl) HDel(ctx context.Context, key string, fields ...string) error {
+	return r.client.HDel(ctx, key, fields...).Err()
+}
+
+func (r *clientImpl) SAdd(ctx context.Context, key string, members ...any) error {
+	return r.client.SAdd(ctx, key, members...).Err()
+}
+
+func (r *clientImpl) SRem(ctx context.Context, key string, members ...any) error {
+	return r.client.SRem(ctx, key, members...).Err()
+}
+
+func (r *clientImpl) SMembers(ctx context.Context, key string) ([]string, error) {
+	return r.client.SMembers(ctx, key).Result()
+}
+
+func (r *clientImpl) SIsMember(ctx context.Context, key string, member any) (bool, error) {
+	return r.client.SIsMember(ctx, key, member).Result()
+}
+
+func (r *clientImpl) Pipelined(ctx context.Context, fn func(Pipeliner) error) ([]Cmder, error) {
+	return r.client.Pipelined(ctx, fn)
+}
+
+func (r *clientImpl) Eval(ctx context.Context, script *Script, keys []string, args ...any) (any, error) {
+	return script.Run(ctx, r.client, keys, args...).Result()
+}
@@ -0,0 +1,201 @@
+package redis
+
+import (
+	"context"
+	"go-fiber-api/internal/core/config"
+	"go-fiber-api/internal/core/lifecycle"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedisClient_GetContext_Byte(t *testing.T) {
+	cfg := &config.Configuration{
+		Redis: config.RedisConfig{
+			Host: "localhost",
+			Port: "6379",
+			DB:   10,
+		},
+	}
+
+	client, _ := ProvideClient(cfg, nil, nil, lifecycle.New())
+	c := client.(*clientImpl)
+	defer client.Close()
+
+	ctx := context.Background()
+	assert.NoError(t, c.SetContext(ctx, "test-key-get-byte", []byte("test-value"), 5))
+
+	var val []byte
+	assert.NoError(t, c.GetContext(ctx, "test-key-get-byte", &val))
+	assert.Equal(t, []byte("test-value"), val)
+
+	ctx, cancel := context.WithCancel(ctx)
+	cancel()
+	assert.ErrorIs(t, c.GetContext(ctx, "test-key-get-byte", &val), context.Canceled)
+}
+
+func TestRedisClient_Commands(t *testing.T) {
+	cfg := &config.Configuration{
+		Redis: config.RedisConfig{
+			Host: "localhost",
+			Port: "6379",
+			DB:   10,
+		},
+	}
+
+	tests := []struct {
+		name string
+		run  func(t *testing.T, ctx context.Context, c *clientImpl)
+	}{
+		{
+			name: "mget_mset",
+			run: func(t *testing.T, ctx context.Context, c *clientImpl) {
+				assert.NoError(t, c.DelContext(ctx, "test-key-mset-1", "test-key-mset-2", "test-key-mset-missing"))
+				assert.NoError(t, c.MSet(ctx, map[string]any{
+					"test-key-mset-1": []byte("one"),
+					"test-key-mset-2": 2,
+				}))
+
+				vals, err := c.MGet(ctx, "test-key-mset-1", "test-key-mset-2", "test-key-mset-missing")
+				assert.NoError(t, err)
+				assert.Equal(t, [][]byte{[]byte("one"), []byte("2"), nil}, vals)
+			},
+		},
+		{
+			// Run by MGet and MSet in cluster mode, where their keys may
+			// hash to different slots.
+			name: "mget_mset_pipelined",
+			run: func(t *testing.T, ctx context.Context, c *clientImpl) {
+				assert.NoError(t, c.DelContext(ctx, "{test-a}:mset", "{test-b}:mset", "{test-c}:mset"))
+				assert.NoError(t, c.msetPipelined(ctx, map[string]any{
+					"{test-a}:mset": []byte("one"),
+					"{test-b}:mset": 2,
+				}))
+
+				vals, err := c.mgetPipelined(ctx, "{test-a}:mset", "{test-b}:mset", "{test-c}:mset")
+				assert.NoError(t, err)
+				assert.Equal(t, [][]byte{[]byte("one"), []byte("2"), nil}, vals)
+
+				ttl, err := c.TTL(ctx, "{test-a}:mset")
+				assert.NoError(t, err)
+				assert.Equal(t, time.Duration(-1), ttl, "set without expiration")
+			},
+		},
+		{
+			name: "setnx",
+			run: func(t *testing.T, ctx context.Context, c *clientImpl) {
+				assert.NoError(t, c.DelContext(ctx, "test-key-setnx"))
+
+				ok, err := c.SetNX(ctx, "test-key-setnx", "first", time.Second)
+				assert.NoError(t, err)
+				assert.True(t, ok)
+
+				ok, err = c.SetNX(ctx, "test-key-setnx", "second", time.Second)
+				assert.NoError(t, err)
+				assert.False(t, ok)
+			},
+		},
+		{
+			name: "getdel",
+			run: func(t *testing.T, ctx context.Context, c *clientImpl) {
+				assert.NoError(t, c.SetContext(ctx, "test-key-getdel", "once", 10))
+
+				var val string
+				assert.NoError(t, c.GetDel(ctx, "test-key-getdel", &val))
+				assert.Equal(t, "once", val)
+
+				assert.ErrorIs(t, c.GetDel(ctx, "test-key-getdel", &val), Nil)
+			},
+		},
+		{
+			name: "incr_expire",
+			run: func(t *testing.T, ctx context.Context, c *clientImpl) {
+				assert.NoError(t, c.DelContext(ctx, "test-key-incr"))
+
+				n, err := c.Incr(ctx, "test-key-incr")
+				assert.NoError(t, err)
+				assert.Equal(t, int64(1), n)
+
+				n, err = c.IncrBy(ctx, "test-key-incr", 4)
+				assert.NoError(t, err)
+				assert.Equal(t, int64(5), n)
+
+				ok, err := c.Expire(ctx, "test-key-incr", 10*time.Second)
+				assert.NoError(t, err)
+				assert.True(t, ok)
+
+				ttl, err := c.TTL(ctx, "test-key-incr")
+				assert.NoError(t, err)
+				assert.Equal(t, 10*time.Second, ttl)
+			},
+		},
+		{
+			name: "hash",
+			run: func(t *testing.T, ctx context.Context, c *clientImpl) {
+				assert.NoError(t, c.DelContext(ctx, "test-key-hash"))
+				assert.NoError(t, c.HSet(ctx, "test-key-hash", map[string]any{
+					"name":  "apikey",
+					"count": 3,
+				}))
+
+				var name string
+				assert.NoError(t, c.HGet(ctx, "test-key-hash", "name", &name))
+				assert.Equal(t, "apikey", name)
+
+				assert.NoError(t, c.HDel(ctx, "test-key-hash", "name"))
+				all, err := c.HGetAll(ctx, "test-key-hash")
+				assert.NoError(t, err)
+				assert.Equal(t, map[string]string{"count": "3"}, all)
+			},
+		},
+		{
+			name: "set",
+			run: func(t *testing.T, ctx context.Context, c *clientImpl) {
+				assert.NoError(t, c.DelContext(ctx, "test-key-set"))
+				assert.NoError(t, c.SAdd(ctx, "test-key-set", "a", "b"))
+				assert.NoError(t, c.SRem(ctx, "test-key-set", "a"))
+
+				members, err := c.SMembers(ctx, "test-key-set")
+				assert.NoError(t, err)
+				assert.Equal(t, []string{"b"}, members)
+
+				ok, err := c.SIsMember(ctx, "test-key-set", "b")
+				assert.NoError(t, err)
+				assert.True(t, ok)
+			},
+		},
+		{
+			name: "pipelined",
+			run: func(t *testing.T, ctx context.Context, c *clientImpl) {
+				cmds, err := c.Pipelined(ctx, func(p Pipeliner) error {
+					p.Set(ctx, "test-key-pipelined", "value", time.Second)
+					p.Get(ctx, "test-key-pipelined")
+					return nil
+				})
+				assert.NoError(t, err)
+				assert.Len(t, cmds, 2)
+			},
+		},
+		{
+			name: "eval",
+			run: func(t *testing.T, ctx context.Context, c *clientImpl) {
+				script := NewScript(`return redis.call("SET", KEYS[1], ARGV[1])`)
+
+				res, err := c.Eval(ctx, script, []string{"test-key-eval"}, "value")
+				assert.NoError(t, err)
+				assert.Equal(t, "OK", res)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, _ := ProvideClient(cfg, nil, nil, lifecycle.New())
+			c := client.(*clientImpl)
+			defer client.Close()
+
+			tt.run(t, context.Background(), c)
+		})
+	}
+}
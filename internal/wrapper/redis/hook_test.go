@@ -0,0 +1,90 @@
+package redis
+
+import (
+	"context"
+	"go-fiber-api/internal/core/config"
+	"go-fiber-api/internal/core/lifecycle"
+	"go-fiber-api/internal/wrapper/metrics"
+	"go-fiber-api/internal/wrapper/tracing"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestRedisClient_Metrics(t *testing.T) {
+	cfg := &config.Configuration{
+		Redis: config.RedisConfig{
+			Host: "localhost",
+			Port: "6379",
+			DB:   10,
+		},
+	}
+
+	m := metrics.New()
+	client, _ := ProvideClient(cfg, m, nil, lifecycle.New())
+	defer client.Close()
+
+	ctx := context.Background()
+	assert.NoError(t, client.DelContext(ctx, "test-key-metrics"))
+
+	var val string
+	assert.ErrorIs(t, client.GetContext(ctx, "test-key-metrics", &val), Nil)
+
+	families, err := m.Registry().Gather()
+	assert.NoError(t, err)
+
+	observed := map[string]uint64{}
+	errs := map[string]float64{}
+	for _, family := range families {
+		for _, metric := range family.GetMetric() {
+			switch family.GetName() {
+			case "go_fiber_api_redis_command_duration_seconds":
+				observed[metric.GetLabel()[0].GetValue()] = metric.GetHistogram().GetSampleCount()
+			case "go_fiber_api_redis_command_errors_total":
+				errs[metric.GetLabel()[0].GetValue()] = metric.GetCounter().GetValue()
+			}
+		}
+	}
+
+	assert.Equal(t, uint64(1), observed["del"])
+	assert.Equal(t, uint64(1), observed["get"])
+	// A missing key is not an error.
+	assert.Zero(t, errs["get"])
+}
+
+func TestRedisClient_Tracing(t *testing.T) {
+	cfg := &config.Configuration{
+		Redis: config.RedisConfig{
+			Host: "localhost",
+			Port: "6379",
+			DB:   10,
+		},
+		TracingSampleRatio: 1,
+	}
+
+	recorder := tracetest.NewSpanRecorder()
+	tr, err := tracing.New(cfg, sdktrace.WithSpanProcessor(recorder))
+	assert.NoError(t, err)
+
+	client, _ := ProvideClient(cfg, nil, tr, lifecycle.New())
+	defer client.Close()
+
+	ctx, parent := tr.Start(context.Background(), "parent")
+	var val string
+	assert.ErrorIs(t, client.GetContext(ctx, "test-key-tracing", &val), Nil)
+	parent.End()
+
+	var spans []string
+	for _, span := range recorder.Ended() {
+		if span.Name() == "redis.get" {
+			assert.Equal(t, parent.SpanContext().SpanID(), span.Parent().SpanID())
+			// A missing key is not an error.
+			assert.Equal(t, codes.Unset, span.Status().Code)
+		}
+		spans = append(spans, span.Name())
+	}
+	assert.Contains(t, spans, "redis.get")
+}
@@ -0,0 +1,118 @@
+package redis
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"go-fiber-api/internal/core/config"
+	"os"
+	"strings"
+
+	"github.com/redis/go-redis/v9"
+)
+
+type Mode string
+
+const (
+	ModeSingle   Mode = "single"
+	ModeSentinel Mode = "sentinel"
+	ModeCluster  Mode = "cluster"
+)
+
+// mode picks the deployment from config: a Sentinel master name wins over
+// Cluster seed nodes, otherwise RedisHost/RedisPort is a single node.
+func mode(cfg *config.Configuration) Mode {
+	switch {
+	case len(cfg.Redis.SentinelMaster) > 0:
+		return ModeSentinel
+	case len(splitAddrs(cfg.Redis.ClusterAddrs)) > 0:
+		return ModeCluster
+	default:
+		return ModeSingle
+	}
+}
+
+func universalOptions(cfg *config.Configuration) (*redis.UniversalOptions, error) {
+	options := &redis.UniversalOptions{
+		DB:       cfg.Redis.DB,
+		Username: cfg.Redis.Username,
+		Password: cfg.Redis.Password,
+	}
+
+	switch mode(cfg) {
+	case ModeSentinel:
+		options.MasterName = cfg.Redis.SentinelMaster
+		options.Addrs = splitAddrs(cfg.Redis.SentinelAddrs)
+		options.SentinelUsername = cfg.Redis.SentinelUsername
+		options.SentinelPassword = cfg.Redis.SentinelPassword
+		if len(options.Addrs) == 0 {
+			return nil, fmt.Errorf("redis sentinel master %s needs at least one sentinel address", options.MasterName)
+		}
+	case ModeCluster:
+		options.Addrs = splitAddrs(cfg.Redis.ClusterAddrs)
+	default:
+		options.Addrs = []string{fmt.Sprintf("%v:%v", cfg.Redis.Host, cfg.Redis.Port)}
+	}
+
+	if cfg.Redis.TLSEnabled {
+		tlsConfig, err := tlsConfig(cfg)
+		if err != nil {
+			return nil, err
+		}
+		options.TLSConfig = tlsConfig
+	}
+
+	return options, nil
+}
+
+func newUniversalClient(cfg *config.Configuration) (redis.UniversalClient, error) {
+	options, err := universalOptions(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	// NewUniversalClient would fall back to a single node client when only one
+	// cluster seed is configured, so the mode is chosen explicitly.
+	switch mode(cfg) {
+	case ModeSentinel:
+		return redis.NewFailoverClient(options.Failover()), nil
+	case ModeCluster:
+		return redis.NewClusterClient(options.Cluster()), nil
+	default:
+		return redis.NewClient(options.Simple()), nil
+	}
+}
+
+func tlsConfig(cfg *config.Configuration) (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		ServerName:         cfg.Redis.TLSServerName,
+		InsecureSkipVerify: cfg.Redis.TLSInsecureSkipVerify,
+	}
+
+	if len(cfg.Redis.TLSCAFile) > 0 {
+		ca, err := os.ReadFile(cfg.Redis.TLSCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("read redis ca file failed: %v", err)
+		}
+
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(ca) {
+			return nil, fmt.Errorf("no certificate found in redis ca file %s", cfg.Redis.TLSCAFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	return tlsConfig, nil
+}
+
+func splitAddrs(addrs string) []string {
+	result := make([]string, 0)
+	for _, addr := range strings.Split(addrs, ",") {
+		if addr = strings.TrimSpace(addr); len(addr) > 0 {
+			result = append(result, addr)
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,95 @@
+package redis
+
+import (
+	"go-fiber-api/internal/core/config"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUniversalOptions(t *testing.T) {
+	tests := []struct {
+		name          string
+		cfg           *config.Configuration
+		expectedMode  Mode
+		expectedAddrs []string
+		wantErr       bool
+		expectedError string
+	}{
+		{
+			name: "when_only_host_is_set_should_use_single_node",
+			cfg: &config.Configuration{
+				Redis: config.RedisConfig{
+					Host: "localhost",
+					Port: "6379",
+				},
+			},
+			expectedMode:  ModeSingle,
+			expectedAddrs: []string{"localhost:6379"},
+		},
+		{
+			name: "when_sentinel_master_is_set_should_use_sentinel",
+			cfg: &config.Configuration{
+				Redis: config.RedisConfig{
+					Host:           "localhost",
+					Port:           "6379",
+					SentinelMaster: "mymaster",
+					SentinelAddrs:  "sentinel-1:26379, sentinel-2:26379",
+					ClusterAddrs:   "node-1:6379",
+				},
+			},
+			expectedMode:  ModeSentinel,
+			expectedAddrs: []string{"sentinel-1:26379", "sentinel-2:26379"},
+		},
+		{
+			name: "when_sentinel_has_no_address_should_get_error",
+			cfg: &config.Configuration{
+				Redis: config.RedisConfig{
+					SentinelMaster: "mymaster",
+				},
+			},
+			expectedMode:  ModeSentinel,
+			wantErr:       true,
+			expectedError: "redis sentinel master mymaster needs at least one sentinel address",
+		},
+		{
+			name: "when_cluster_addrs_is_set_should_use_cluster",
+			cfg: &config.Configuration{
+				Redis: config.RedisConfig{
+					ClusterAddrs: "node-1:6379,,node-2:6379",
+				},
+			},
+			expectedMode:  ModeCluster,
+			expectedAddrs: []string{"node-1:6379", "node-2:6379"},
+		},
+		{
+			name: "when_tls_ca_file_is_missing_should_get_error",
+			cfg: &config.Configuration{
+				Redis: config.RedisConfig{
+					Host:       "localhost",
+					Port:       "6379",
+					TLSEnabled: true,
+					TLSCAFile:  "/not/exist/ca.pem",
+				},
+			},
+			expectedMode:  ModeSingle,
+			wantErr:       true,
+			expectedError: "read redis ca file failed: open /not/exist/ca.pem: no such file or directory",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expectedMode, mode(tt.cfg))
+
+			options, err := universalOptions(tt.cfg)
+			if tt.wantErr {
+				assert.EqualError(t, err, tt.expectedError)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedAddrs, options.Addrs)
+		})
+	}
+}
@@ -0,0 +1,325 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"go-fiber-api/internal/core/config"
+	"hash/fnv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+	lru "github.com/hashicorp/golang-lru/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// invalidationChannel carries "<instance id>|<key>" messages whenever a
+	// key is written or deleted, so other instances drop their local copy.
+	invalidationChannel = "cache:invalidate"
+	invalidationSep     = "|"
+
+	// generationStripes bounds the generation counters, shared by the keys
+	// hashing to the same stripe.
+	generationStripes = 256
+)
+
+type localEntry struct {
+	data      []byte
+	expiresAt time.Time
+}
+
+// tieredClient keeps a size and TTL bounded in-process LRU in front of Redis.
+// Writes go through to Redis and are broadcast on invalidationChannel so the
+// local tier of every instance stays coherent, including the keys changed by
+// Incr, IncrBy, Expire and the keys of Eval; Pipelined is the only writer
+// that is not tracked. Only plain string values are kept locally; counters,
+// hashes and sets are always served by Redis.
+type tieredClient struct {
+	*clientImpl
+
+	local      *lru.Cache[string, localEntry]
+	ttl        time.Duration
+	instanceID string
+
+	// generations are bumped whenever keys are invalidated, so a value read
+	// from Redis before an invalidation is never kept locally after it.
+	generations [generationStripes]atomic.Uint64
+
+	pubsub *redis.PubSub
+	cancel context.CancelFunc
+	done   chan struct{}
+
+	hits   atomic.Uint64
+	misses atomic.Uint64
+}
+
+func provideTieredClient(cfg *config.Configuration, remote *clientImpl) (Client, error) {
+	local, err := lru.New[string, localEntry](cfg.CacheLocalSize)
+	if err != nil {
+		return nil, fmt.Errorf("create local cache failed: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t := &tieredClient{
+		clientImpl: remote,
+		local:      local,
+		ttl:        time.Duration(cfg.CacheLocalTTL) * time.Second,
+		instanceID: uuid.New().String(),
+		pubsub:     remote.Subscribe(ctx, invalidationChannel),
+		cancel:     cancel,
+		done:       make(chan struct{}),
+	}
+
+	// Wait for the subscription to be confirmed so no invalidation sent after
+	// startup is missed.
+	if _, err := t.pubsub.Receive(ctx); err != nil {
+		cancel()
+		_ = t.pubsub.Close()
+		return nil, fmt.Errorf("subscribe %s failed: %v", invalidationChannel, err)
+	}
+
+	go t.listen()
+
+	return t, nil
+}
+
+func (t *tieredClient) listen() {
+	defer close(t.done)
+
+	for msg := range t.pubsub.ChannelWithSubscriptions() {
+		t.handle(msg)
+	}
+}
+
+// handle applies a message of the invalidation channel to the local tier.
+func (t *tieredClient) handle(msg any) {
+	switch msg := msg.(type) {
+	case *redis.Subscription:
+		// Resubscribed after a reconnection: invalidations sent meanwhile are
+		// lost, so is every local copy.
+		if msg.Kind == "subscribe" {
+			t.purge()
+		}
+	case *redis.Message:
+		instanceID, key, ok := strings.Cut(msg.Payload, invalidationSep)
+		if !ok {
+			logrus.Warnf("invalid cache invalidation message: %s", msg.Payload)
+			return
+		}
+
+		if instanceID == t.instanceID {
+			return
+		}
+
+		t.generation(key).Add(1)
+		t.local.Remove(key)
+	}
+}
+
+// generation returns the generation counter of key.
+func (t *tieredClient) generation(key string) *atomic.Uint64 {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(key))
+
+	return &t.generations[h.Sum32()%generationStripes]
+}
+
+// purge drops every local copy, and the values being read from Redis.
+func (t *tieredClient) purge() {
+	for i := range t.generations {
+		t.generations[i].Add(1)
+	}
+	t.local.Purge()
+}
+
+func (t *tieredClient) invalidate(ctx context.Context, keys ...string) {
+	for _, key := range keys {
+		t.generation(key).Add(1)
+		t.local.Remove(key)
+
+		msg := t.instanceID + invalidationSep + key
+		if err := t.clientImpl.Publish(ctx, invalidationChannel, msg); err != nil {
+			logrus.Warnf("failed to publish cache invalidation for %s: %v", key, err)
+		}
+	}
+}
+
+func (t *tieredClient) Set(key string, value any, ttl ...int) error {
+	return t.SetContext(context.Background(), key, value, ttl...)
+}
+
+func (t *tieredClient) Get(key string, out any) error {
+	return t.GetContext(context.Background(), key, out)
+}
+
+func (t *tieredClient) Del(key string) error {
+	return t.DelContext(context.Background(), key)
+}
+
+func (t *tieredClient) SetContext(ctx context.Context, key string, value any, ttl ...int) error {
+	if err := t.clientImpl.SetContext(ctx, key, value, ttl...); err != nil {
+		return err
+	}
+
+	data, err := Encode(value)
+	if err != nil {
+		return err
+	}
+
+	// Never keep a local copy longer than Redis would.
+	remoteTTL := defaultTTL
+	if len(ttl) > 0 {
+		remoteTTL = ttl[0]
+	}
+	localTTL := min(t.ttl, time.Duration(remoteTTL)*time.Second)
+
+	t.invalidate(ctx, key)
+	// Read after the invalidation of this write: a later one racing with the
+	// add still drops the local copy, as in GetContext.
+	gen := t.generation(key).Load()
+	t.local.Add(key, localEntry{data: data, expiresAt: time.Now().Add(localTTL)})
+	if t.generation(key).Load() != gen {
+		t.local.Remove(key)
+	}
+
+	return nil
+}
+
+func (t *tieredClient) GetContext(ctx context.Context, key string, out any) error {
+	if entry, ok := t.local.Get(key); ok {
+		if time.Now().Before(entry.expiresAt) {
+			t.hits.Add(1)
+			return Decode(entry.data, out)
+		}
+		t.local.Remove(key)
+	}
+	t.misses.Add(1)
+
+	gen := t.generation(key).Load()
+	data, remoteTTL, err := t.getWithTTL(ctx, key)
+	if err != nil {
+		return err
+	}
+
+	// Never keep a local copy longer than Redis would, nor one read before
+	// an invalidation of key.
+	localTTL := t.ttl
+	if remoteTTL != -1 && remoteTTL < localTTL { // -1 is without expiration
+		localTTL = remoteTTL
+	}
+	if localTTL > 0 && t.generation(key).Load() == gen {
+		t.local.Add(key, localEntry{data: data, expiresAt: time.Now().Add(localTTL)})
+		// Invalidated while being added, its removal may have come first.
+		if t.generation(key).Load() != gen {
+			t.local.Remove(key)
+		}
+	}
+
+	return Decode(data, out)
+}
+
+func (t *tieredClient) DelContext(ctx context.Context, keys ...string) error {
+	for _, key := range keys {
+		t.local.Remove(key)
+	}
+
+	if err := t.clientImpl.DelContext(ctx, keys...); err != nil {
+		return err
+	}
+
+	t.invalidate(ctx, keys...)
+
+	return nil
+}
+
+func (t *tieredClient) MSet(ctx context.Context, values map[string]any) error {
+	if err := t.clientImpl.MSet(ctx, values); err != nil {
+		return err
+	}
+
+	for key := range values {
+		t.invalidate(ctx, key)
+	}
+
+	return nil
+}
+
+func (t *tieredClient) SetNX(ctx context.Context, key string, value any, ttl time.Duration) (bool, error) {
+	ok, err := t.clientImpl.SetNX(ctx, key, value, ttl)
+	if ok {
+		t.invalidate(ctx, key)
+	}
+
+	return ok, err
+}
+
+func (t *tieredClient) GetDel(ctx context.Context, key string, out any) error {
+	t.local.Remove(key)
+
+	if err := t.clientImpl.GetDel(ctx, key, out); err != nil {
+		return err
+	}
+
+	t.invalidate(ctx, key)
+
+	return nil
+}
+
+func (t *tieredClient) Incr(ctx context.Context, key string) (int64, error) {
+	val, err := t.clientImpl.Incr(ctx, key)
+	if err == nil {
+		t.invalidate(ctx, key)
+	}
+
+	return val, err
+}
+
+func (t *tieredClient) IncrBy(ctx context.Context, key string, value int64) (int64, error) {
+	val, err := t.clientImpl.IncrBy(ctx, key, value)
+	if err == nil {
+		t.invalidate(ctx, key)
+	}
+
+	return val, err
+}
+
+func (t *tieredClient) Expire(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	ok, err := t.clientImpl.Expire(ctx, key, ttl)
+	if ok {
+		t.invalidate(ctx, key)
+	}
+
+	return ok, err
+}
+
+// Eval invalidates every key of the script, which may have written any.
+func (t *tieredClient) Eval(ctx context.Context, script *Script, keys []string, args ...any) (any, error) {
+	res, err := t.clientImpl.Eval(ctx, script, keys, args...)
+	// Even a failing script may have written some of them already.
+	t.invalidate(ctx, keys...)
+
+	return res, err
+}
+
+func (t *tieredClient) Stats() Stats {
+	stats := t.clientImpl.Stats()
+	stats.LocalHits = t.hits.Load()
+	stats.LocalMisses = t.misses.Load()
+
+	return stats
+}
+
+func (t *tieredClient) Close() error {
+	t.cancel()
+	err := t.pubsub.Close()
+	<-t.done
+
+	if cerr := t.clientImpl.Close(); cerr != nil {
+		return cerr
+	}
+
+	return err
+}
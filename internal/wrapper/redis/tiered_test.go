@@ -0,0 +1,312 @@
+package redis
+
+import (
+	"context"
+	"go-fiber-api/internal/core/config"
+	"go-fiber-api/internal/core/response"
+	"testing"
+	"time"
+
+	goredis "github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTieredClient(t *testing.T, cfg *config.Configuration) *tieredClient {
+	remote := &clientImpl{
+		cfg:    cfg,
+		client: goredis.NewClient(&goredis.Options{Addr: cfg.Redis.Host + ":" + cfg.Redis.Port, DB: cfg.Redis.DB}),
+	}
+
+	client, err := provideTieredClient(cfg, remote)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	return client.(*tieredClient)
+}
+
+func TestTieredClient_Get(t *testing.T) {
+	cfg := &config.Configuration{
+		Redis: config.RedisConfig{
+			Host: "localhost",
+			Port: "6379",
+			DB:   10,
+		},
+		CacheLocalSize: 10,
+		CacheLocalTTL:  30,
+	}
+
+	tests := []struct {
+		name     string
+		key      string
+		reads    int
+		expected Stats
+	}{
+		{
+			name:     "when_key_is_missing_should_miss_both_tiers",
+			key:      "test-tiered-key-missing",
+			reads:    1,
+			expected: Stats{LocalMisses: 1, RemoteMisses: 1},
+		},
+		{
+			name:     "when_key_is_set_should_hit_local_tier",
+			key:      "test-tiered-key-set",
+			reads:    2,
+			expected: Stats{LocalHits: 2},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newTieredClient(t, cfg)
+			defer c.Close()
+
+			value := response.ResponseDTO{Message: "test-message"}
+			if tt.expected.LocalHits > 0 {
+				assert.NoError(t, c.Set(tt.key, value, 10))
+			} else {
+				assert.NoError(t, c.Del(tt.key))
+			}
+
+			for i := 0; i < tt.reads; i++ {
+				var val response.ResponseDTO
+				err := c.Get(tt.key, &val)
+				if tt.expected.LocalHits > 0 {
+					assert.NoError(t, err)
+					assert.Equal(t, value, val)
+				} else {
+					assert.Equal(t, goredis.Nil, err)
+				}
+			}
+
+			assert.Equal(t, tt.expected, c.Stats())
+		})
+	}
+}
+
+func TestTieredClient_Invalidation(t *testing.T) {
+	cfg := &config.Configuration{
+		Redis: config.RedisConfig{
+			Host: "localhost",
+			Port: "6379",
+			DB:   10,
+		},
+		CacheLocalSize: 10,
+		CacheLocalTTL:  30,
+	}
+
+	key := "test-tiered-key-invalidation"
+
+	first := newTieredClient(t, cfg)
+	defer first.Close()
+	second := newTieredClient(t, cfg)
+	defer second.Close()
+
+	assert.NoError(t, first.Set(key, "first", 10))
+
+	// A read racing with the invalidation of the write above isn't kept.
+	var val string
+	assert.Eventually(t, func() bool {
+		return second.Get(key, &val) == nil && second.local.Contains(key)
+	}, time.Second, 10*time.Millisecond)
+	assert.Equal(t, "first", val)
+
+	// A write on one instance must evict the stale copy held by the other.
+	assert.NoError(t, first.Set(key, "second", 10))
+	assert.Eventually(t, func() bool {
+		return !second.local.Contains(key)
+	}, time.Second, 10*time.Millisecond)
+
+	assert.NoError(t, second.Get(key, &val))
+	assert.Equal(t, "second", val)
+}
+
+func TestTieredClient_Get_RemoteTTL(t *testing.T) {
+	cfg := &config.Configuration{
+		Redis: config.RedisConfig{
+			Host: "localhost",
+			Port: "6379",
+			DB:   10,
+		},
+		CacheLocalSize: 10,
+		CacheLocalTTL:  30,
+	}
+
+	c := newTieredClient(t, cfg)
+	defer c.Close()
+
+	key := "test-tiered-key-remote-ttl"
+	// Written by another instance, only 1s of it is left.
+	assert.NoError(t, c.clientImpl.SetContext(context.Background(), key, "value", 1))
+
+	// test logic
+	var val string
+	assert.NoError(t, c.Get(key, &val))
+	assert.Equal(t, "value", val)
+
+	entry, ok := c.local.Peek(key)
+	assert.True(t, ok)
+	assert.WithinDuration(t, time.Now().Add(time.Second), entry.expiresAt, 100*time.Millisecond)
+}
+
+func TestTieredClient_Set_LocalTTL(t *testing.T) {
+	cfg := &config.Configuration{
+		Redis: config.RedisConfig{
+			Host: "localhost",
+			Port: "6379",
+			DB:   10,
+		},
+		CacheLocalSize: 10,
+		CacheLocalTTL:  3600,
+	}
+
+	tests := []struct {
+		name     string
+		ttl      []int
+		expected time.Duration
+	}{
+		{
+			name:     "when_ttl_is_missing_should_expire_with_default_ttl",
+			expected: defaultTTL * time.Second,
+		},
+		{
+			name:     "when_ttl_is_shorter_should_expire_with_it",
+			ttl:      []int{5},
+			expected: 5 * time.Second,
+		},
+		{
+			name:     "when_ttl_is_longer_should_expire_with_local_ttl",
+			ttl:      []int{7200},
+			expected: time.Hour,
+		},
+	}
+
+	c := newTieredClient(t, cfg)
+	defer c.Close()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key := "test-tiered-key-" + tt.name
+
+			// test logic
+			assert.NoError(t, c.Set(key, "value", tt.ttl...))
+
+			entry, ok := c.local.Peek(key)
+			assert.True(t, ok)
+			assert.WithinDuration(t, time.Now().Add(tt.expected), entry.expiresAt, 100*time.Millisecond)
+		})
+	}
+}
+
+func TestTieredClient_Resubscribe(t *testing.T) {
+	cfg := &config.Configuration{
+		Redis: config.RedisConfig{
+			Host: "localhost",
+			Port: "6379",
+			DB:   10,
+		},
+		CacheLocalSize: 10,
+		CacheLocalTTL:  30,
+	}
+
+	c := newTieredClient(t, cfg)
+	defer c.Close()
+
+	key := "test-tiered-key-resubscribe"
+	assert.NoError(t, c.Set(key, "value", 10))
+	assert.True(t, c.local.Contains(key))
+	gen := c.generation(key).Load()
+
+	// test logic
+	// Invalidations sent while the subscription was down are lost, so every
+	// local copy is dropped once it is back.
+	c.handle(&goredis.Subscription{Kind: "subscribe", Channel: invalidationChannel, Count: 1})
+
+	assert.False(t, c.local.Contains(key))
+	assert.NotEqual(t, gen, c.generation(key).Load(), "values being read are not kept either")
+}
+
+func TestTieredClient_Writes(t *testing.T) {
+	cfg := &config.Configuration{
+		Redis: config.RedisConfig{
+			Host: "localhost",
+			Port: "6379",
+			DB:   10,
+		},
+		CacheLocalSize: 10,
+		CacheLocalTTL:  30,
+	}
+
+	script := goredis.NewScript(`return redis.call("SET", KEYS[1], ARGV[1])`)
+
+	tests := []struct {
+		name     string
+		write    func(c *tieredClient, key string) error
+		expected string
+	}{
+		{
+			name: "when_incremented_should_drop_local_copy",
+			write: func(c *tieredClient, key string) error {
+				_, err := c.Incr(context.Background(), key)
+				return err
+			},
+			expected: "2",
+		},
+		{
+			name: "when_incremented_by_should_drop_local_copy",
+			write: func(c *tieredClient, key string) error {
+				_, err := c.IncrBy(context.Background(), key, 2)
+				return err
+			},
+			expected: "3",
+		},
+		{
+			name: "when_expired_should_drop_local_copy",
+			write: func(c *tieredClient, key string) error {
+				_, err := c.Expire(context.Background(), key, -time.Second)
+				return err
+			},
+		},
+		{
+			name: "when_written_by_script_should_drop_local_copy",
+			write: func(c *tieredClient, key string) error {
+				_, err := c.Eval(context.Background(), script, []string{key}, 5)
+				return err
+			},
+			expected: "5",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			first := newTieredClient(t, cfg)
+			defer first.Close()
+			second := newTieredClient(t, cfg)
+			defer second.Close()
+
+			key := "test-tiered-key-" + tt.name
+			assert.NoError(t, first.Set(key, 1, 10))
+
+			var val int
+			assert.Eventually(t, func() bool {
+				return second.Get(key, &val) == nil && second.local.Contains(key)
+			}, time.Second, 10*time.Millisecond)
+
+			// test logic
+			assert.NoError(t, tt.write(first, key))
+			assert.False(t, first.local.Contains(key))
+			assert.Eventually(t, func() bool {
+				return !second.local.Contains(key)
+			}, time.Second, 10*time.Millisecond)
+
+			var raw []byte
+			err := second.Get(key, &raw)
+			if tt.expected == "" {
+				assert.Equal(t, goredis.Nil, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected, string(raw))
+			}
+		})
+	}
+}
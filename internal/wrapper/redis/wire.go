@@ -6,6 +6,9 @@ package redis
 
 import (
 	"go-fiber-api/internal/core/config"
+	"go-fiber-api/internal/core/lifecycle"
+	"go-fiber-api/internal/wrapper/metrics"
+	"go-fiber-api/internal/wrapper/tracing"
 
 	"github.com/google/wire"
 )
@@ -13,7 +16,7 @@ import (
 var ProviderSet = wire.NewSet(ProvideClient)
 
 // Wire is the wire provider for the redis package
-func Wire(cfg *config.Configuration) (Client, error) {
+func Wire(cfg *config.Configuration, m *metrics.Metrics, t *tracing.Tracing, lc *lifecycle.Lifecycle) (Client, error) {
 	wire.Build(ProviderSet)
 
 	return &clientImpl{}, nil
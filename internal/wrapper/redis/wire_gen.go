@@ -9,13 +9,16 @@ package redis
 import (
 	"github.com/google/wire"
 	"go-fiber-api/internal/core/config"
+	"go-fiber-api/internal/core/lifecycle"
+	"go-fiber-api/internal/wrapper/metrics"
+	"go-fiber-api/internal/wrapper/tracing"
 )
 
 // Injectors from wire.go:
 
 // Wire is the wire provider for the redis package
-func Wire(cfg *config.Configuration) (Client, error) {
-	client, err := ProvideClient(cfg)
+func Wire(cfg *config.Configuration, m *metrics.Metrics, t *tracing.Tracing, lc *lifecycle.Lifecycle) (Client, error) {
+	client, err := ProvideClient(cfg, m, t, lc)
 	if err != nil {
 		return nil, err
 	}
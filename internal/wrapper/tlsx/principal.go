@@ -0,0 +1,56 @@
+package tlsx
+
+import (
+	"crypto/tls"
+	"strings"
+)
+
+// Principals maps client certificate identities to principal names. An
+// identity is a URI SAN (e.g. a SPIFFE ID), a DNS SAN or the subject common
+// name, tried in that order.
+type Principals map[string]string
+
+// ParsePrincipals reads "identity=principal" pairs separated by commas. An
+// empty string gives an empty mapping, under which the common name is the
+// principal.
+func ParsePrincipals(s string) Principals {
+	p := Principals{}
+	for _, pair := range strings.Split(s, ",") {
+		identity, principal, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if !ok || identity == "" || principal == "" {
+			continue
+		}
+		p[strings.TrimSpace(identity)] = strings.TrimSpace(principal)
+	}
+
+	return p
+}
+
+// Lookup returns the principal of the verified client certificate of state.
+// Unverified connections, and certificates absent from a non-empty mapping,
+// have none.
+func (p Principals) Lookup(state *tls.ConnectionState) (string, bool) {
+	if state == nil || len(state.VerifiedChains) == 0 || len(state.VerifiedChains[0]) == 0 {
+		return "", false
+	}
+	leaf := state.VerifiedChains[0][0]
+
+	if len(p) == 0 {
+		return leaf.Subject.CommonName, leaf.Subject.CommonName != ""
+	}
+
+	var identities []string
+	for _, uri := range leaf.URIs {
+		identities = append(identities, uri.String())
+	}
+	identities = append(identities, leaf.DNSNames...)
+	identities = append(identities, leaf.Subject.CommonName)
+
+	for _, identity := range identities {
+		if principal, ok := p[identity]; ok {
+			return principal, true
+		}
+	}
+
+	return "", false
+}
@@ -0,0 +1,81 @@
+package tlsx_test
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"go-fiber-api/internal/wrapper/tlsx"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParsePrincipals(t *testing.T) {
+	assert.Equal(t, tlsx.Principals{}, tlsx.ParsePrincipals(""))
+	assert.Equal(t, tlsx.Principals{
+		"billing":                       "billing-service",
+		"spiffe://example.org/reporter": "reporter",
+	}, tlsx.ParsePrincipals(" billing = billing-service ,spiffe://example.org/reporter=reporter,broken,=x"))
+}
+
+func TestPrincipals_Lookup(t *testing.T) {
+	ca := newCert(t, "ca", nil)
+	billing := newCert(t, "billing", ca)
+	reporter := newCert(t, "reporter", ca, "spiffe://example.org/reporter")
+
+	verified := func(c *testCert) *tls.ConnectionState {
+		return &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{c.cert, ca.cert}}}
+	}
+
+	tests := []struct {
+		name              string
+		principals        tlsx.Principals
+		state             *tls.ConnectionState
+		expectedPrincipal string
+		expectedOK        bool
+	}{
+		{
+			name:       "when_no_tls_should_have_no_principal",
+			principals: tlsx.Principals{},
+			state:      nil,
+		},
+		{
+			name:       "when_certificate_is_unverified_should_have_no_principal",
+			principals: tlsx.Principals{},
+			state:      &tls.ConnectionState{PeerCertificates: []*x509.Certificate{billing.cert}},
+		},
+		{
+			name:              "when_mapping_is_empty_should_use_common_name",
+			principals:        tlsx.Principals{},
+			state:             verified(billing),
+			expectedPrincipal: "billing",
+			expectedOK:        true,
+		},
+		{
+			name:              "when_common_name_is_mapped_should_use_mapping",
+			principals:        tlsx.Principals{"billing": "billing-service"},
+			state:             verified(billing),
+			expectedPrincipal: "billing-service",
+			expectedOK:        true,
+		},
+		{
+			name:              "when_uri_san_is_mapped_should_use_it_first",
+			principals:        tlsx.Principals{"spiffe://example.org/reporter": "reporter-spiffe", "reporter": "reporter-cn"},
+			state:             verified(reporter),
+			expectedPrincipal: "reporter-spiffe",
+			expectedOK:        true,
+		},
+		{
+			name:       "when_certificate_is_not_mapped_should_have_no_principal",
+			principals: tlsx.Principals{"billing": "billing-service"},
+			state:      verified(reporter),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			principal, ok := tt.principals.Lookup(tt.state)
+			assert.Equal(t, tt.expectedOK, ok)
+			assert.Equal(t, tt.expectedPrincipal, principal)
+		})
+	}
+}
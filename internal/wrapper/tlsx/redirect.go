@@ -0,0 +1,31 @@
+package tlsx
+
+import (
+	"net"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// NewRedirectServer answers every plain HTTP request with a permanent
+// redirect to the same URL over HTTPS on httpsPort.
+func NewRedirectServer(httpsPort string) *fiber.App {
+	server := fiber.New(fiber.Config{
+		DisableStartupMessage: true,
+	})
+
+	server.Use(func(c *fiber.Ctx) error {
+		host := c.Hostname()
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+		if httpsPort != "443" {
+			host = net.JoinHostPort(host, httpsPort)
+		}
+
+		// RequestURI rather than OriginalURL, which keeps the scheme and host
+		// of absolute-form request targets.
+		return c.Redirect("https://"+host+string(c.Request().URI().RequestURI()), fiber.StatusPermanentRedirect)
+	})
+
+	return server
+}
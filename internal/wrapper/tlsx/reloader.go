@@ -0,0 +1,115 @@
+package tlsx
+
+import (
+	"crypto/tls"
+	"fmt"
+	"path/filepath"
+	"sync"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/sirupsen/logrus"
+)
+
+// CertReloader serves a certificate/key pair and loads it again whenever the
+// files change on disk, so renewed certificates are picked up without a
+// restart. A pair that fails to load is logged and the previous one is kept.
+type CertReloader struct {
+	certFile string
+	keyFile  string
+
+	mu   sync.RWMutex
+	cert *tls.Certificate
+
+	watcher *fsnotify.Watcher
+	done    chan struct{}
+}
+
+func NewCertReloader(certFile, keyFile string) (*CertReloader, error) {
+	r := &CertReloader{
+		certFile: certFile,
+		keyFile:  keyFile,
+		done:     make(chan struct{}),
+	}
+
+	if err := r.reload(); err != nil {
+		return nil, err
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, fmt.Errorf("watch certificate failed: %v", err)
+	}
+
+	// Directories are watched rather than the files: editors and Kubernetes
+	// secret mounts replace files, which drops a watch on the file itself.
+	dirs := map[string]struct{}{
+		filepath.Dir(certFile): {},
+		filepath.Dir(keyFile):  {},
+	}
+	for dir := range dirs {
+		if err := watcher.Add(dir); err != nil {
+			_ = watcher.Close()
+			return nil, fmt.Errorf("watch %s failed: %v", dir, err)
+		}
+	}
+
+	r.watcher = watcher
+	go r.watch()
+
+	return r, nil
+}
+
+func (r *CertReloader) reload() error {
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return fmt.Errorf("load certificate failed: %v", err)
+	}
+
+	r.mu.Lock()
+	r.cert = &cert
+	r.mu.Unlock()
+
+	return nil
+}
+
+func (r *CertReloader) watch() {
+	defer close(r.done)
+
+	for {
+		select {
+		case _, ok := <-r.watcher.Events:
+			if !ok {
+				return
+			}
+
+			// The cert and key are usually written one after the other, a
+			// mismatch in between is expected and fixed by the next event.
+			if err := r.reload(); err != nil {
+				logrus.Debugf("certificate not reloaded: %v", err)
+				continue
+			}
+			logrus.Infof("certificate reloaded from %s", r.certFile)
+		case err, ok := <-r.watcher.Errors:
+			if !ok {
+				return
+			}
+			logrus.Warnf("certificate watch failed: %v", err)
+		}
+	}
+}
+
+// GetCertificate is meant for tls.Config.GetCertificate.
+func (r *CertReloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.cert, nil
+}
+
+// Close stops watching the files.
+func (r *CertReloader) Close() error {
+	err := r.watcher.Close()
+	<-r.done
+
+	return err
+}
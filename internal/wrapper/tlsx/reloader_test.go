@@ -0,0 +1,36 @@
+package tlsx_test
+
+import (
+	"go-fiber-api/internal/wrapper/tlsx"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCertReloader(t *testing.T) {
+	dir := t.TempDir()
+	first := newCert(t, "first", nil)
+	certFile, keyFile := first.write(t, dir, "server")
+
+	r, err := tlsx.NewCertReloader(certFile, keyFile)
+	assert.NoError(t, err)
+	defer r.Close()
+
+	cert, err := r.GetCertificate(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, first.cert.Raw, cert.Certificate[0])
+
+	second := newCert(t, "second", nil)
+	second.write(t, dir, "server")
+
+	assert.Eventually(t, func() bool {
+		cert, err := r.GetCertificate(nil)
+		return err == nil && string(cert.Certificate[0]) == string(second.cert.Raw)
+	}, 2*time.Second, 10*time.Millisecond)
+}
+
+func TestCertReloader_Invalid(t *testing.T) {
+	_, err := tlsx.NewCertReloader("missing.pem", "missing-key.pem")
+	assert.ErrorContains(t, err, "load certificate failed")
+}
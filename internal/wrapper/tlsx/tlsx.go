@@ -0,0 +1,51 @@
+package tlsx
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"go-fiber-api/internal/core/config"
+	"os"
+)
+
+// Client certificate modes of TLS_CLIENT_AUTH.
+const (
+	ClientAuthNone     = "none"
+	ClientAuthOptional = "optional"
+	ClientAuthRequire  = "require"
+)
+
+// ServerConfig builds the TLS configuration of the server, serving the
+// certificate of r and verifying client certificates against the configured
+// CA when mutual TLS is on.
+func ServerConfig(cfg *config.Configuration, r *CertReloader) (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		MinVersion:     tls.VersionTLS12,
+		GetCertificate: r.GetCertificate,
+	}
+
+	switch cfg.TLSClientAuth {
+	case "", ClientAuthNone:
+		return tlsConfig, nil
+	case ClientAuthOptional:
+		// Callers without a certificate fall back to X-API-Key.
+		tlsConfig.ClientAuth = tls.VerifyClientCertIfGiven
+	case ClientAuthRequire:
+		tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+	default:
+		return nil, fmt.Errorf("unknown tls client auth %q", cfg.TLSClientAuth)
+	}
+
+	pem, err := os.ReadFile(cfg.TLSClientCAFile)
+	if err != nil {
+		return nil, fmt.Errorf("read client ca failed: %v", err)
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("no certificate found in %s", cfg.TLSClientCAFile)
+	}
+	tlsConfig.ClientCAs = pool
+
+	return tlsConfig, nil
+}
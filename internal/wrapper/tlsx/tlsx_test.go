@@ -0,0 +1,182 @@
+package tlsx_test
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"go-fiber-api/internal/core/config"
+	"go-fiber-api/internal/wrapper/tlsx"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServerConfig(t *testing.T) {
+	dir := t.TempDir()
+	ca := newCert(t, "ca", nil)
+	caFile, _ := ca.write(t, dir, "ca")
+	serverCert, serverKey := newCert(t, "localhost", ca).write(t, dir, "server")
+
+	r, err := tlsx.NewCertReloader(serverCert, serverKey)
+	assert.NoError(t, err)
+	defer r.Close()
+
+	tests := []struct {
+		name               string
+		cfg                *config.Configuration
+		expectedError      string
+		expectedClientAuth tls.ClientAuthType
+	}{
+		{
+			name:               "when_client_auth_is_none_should_not_ask_for_certificates",
+			cfg:                &config.Configuration{TLSClientAuth: tlsx.ClientAuthNone},
+			expectedClientAuth: tls.NoClientCert,
+		},
+		{
+			name:               "when_client_auth_is_optional_should_verify_given_certificates",
+			cfg:                &config.Configuration{TLSClientAuth: tlsx.ClientAuthOptional, TLSClientCAFile: caFile},
+			expectedClientAuth: tls.VerifyClientCertIfGiven,
+		},
+		{
+			name:               "when_client_auth_is_required_should_require_certificates",
+			cfg:                &config.Configuration{TLSClientAuth: tlsx.ClientAuthRequire, TLSClientCAFile: caFile},
+			expectedClientAuth: tls.RequireAndVerifyClientCert,
+		},
+		{
+			name:          "when_client_ca_is_missing_should_return_error",
+			cfg:           &config.Configuration{TLSClientAuth: tlsx.ClientAuthRequire, TLSClientCAFile: filepath.Join(dir, "missing.pem")},
+			expectedError: "read client ca failed",
+		},
+		{
+			name:          "when_client_auth_is_unknown_should_return_error",
+			cfg:           &config.Configuration{TLSClientAuth: "maybe"},
+			expectedError: `unknown tls client auth "maybe"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tlsConfig, err := tlsx.ServerConfig(tt.cfg, r)
+			if tt.expectedError != "" {
+				assert.ErrorContains(t, err, tt.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedClientAuth, tlsConfig.ClientAuth)
+			assert.Equal(t, uint16(tls.VersionTLS12), tlsConfig.MinVersion)
+		})
+	}
+}
+
+func TestServerConfig_MutualTLS(t *testing.T) {
+	dir := t.TempDir()
+	ca := newCert(t, "ca", nil)
+	caFile, _ := ca.write(t, dir, "ca")
+	serverCert, serverKey := newCert(t, "localhost", ca).write(t, dir, "server")
+	client := newCert(t, "billing", ca)
+
+	r, err := tlsx.NewCertReloader(serverCert, serverKey)
+	assert.NoError(t, err)
+	defer r.Close()
+
+	tlsConfig, err := tlsx.ServerConfig(&config.Configuration{
+		TLSClientAuth:   tlsx.ClientAuthOptional,
+		TLSClientCAFile: caFile,
+	}, r)
+	assert.NoError(t, err)
+
+	principals := tlsx.ParsePrincipals("billing=billing-service")
+
+	app := fiber.New(fiber.Config{DisableStartupMessage: true})
+	app.Get("/", func(c *fiber.Ctx) error {
+		principal, ok := principals.Lookup(c.Context().TLSConnectionState())
+		if !ok {
+			return c.SendStatus(fiber.StatusUnauthorized)
+		}
+		return c.SendString(principal)
+	})
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	go func() { _ = app.Listener(tls.NewListener(ln, tlsConfig)) }()
+	defer app.Shutdown()
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.cert)
+
+	tests := []struct {
+		name           string
+		certificates   []tls.Certificate
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "when_client_certificate_is_mapped_should_authenticate",
+			certificates:   []tls.Certificate{client.tlsCertificate(t)},
+			expectedStatus: fiber.StatusOK,
+			expectedBody:   "billing-service",
+		},
+		{
+			name:           "when_no_client_certificate_should_not_authenticate",
+			expectedStatus: fiber.StatusUnauthorized,
+			expectedBody:   "Unauthorized",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			httpClient := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{
+				RootCAs:      roots,
+				ServerName:   "localhost",
+				Certificates: tt.certificates,
+			}}}
+
+			resp, err := httpClient.Get("https://" + ln.Addr().String())
+			assert.NoError(t, err)
+			defer resp.Body.Close()
+
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+			body, err := io.ReadAll(resp.Body)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedBody, string(body))
+		})
+	}
+}
+
+func TestNewRedirectServer(t *testing.T) {
+	tests := []struct {
+		name             string
+		httpsPort        string
+		url              string
+		expectedLocation string
+	}{
+		{
+			name:             "when_https_port_is_default_should_omit_it",
+			httpsPort:        "443",
+			url:              "http://example.com:8080/service?q=1",
+			expectedLocation: "https://example.com/service?q=1",
+		},
+		{
+			name:             "when_https_port_is_custom_should_keep_it",
+			httpsPort:        "8443",
+			url:              "http://example.com/service",
+			expectedLocation: "https://example.com:8443/service",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := tlsx.NewRedirectServer(tt.httpsPort)
+
+			resp, err := server.Test(httptest.NewRequest(fiber.MethodGet, tt.url, nil))
+			assert.NoError(t, err)
+			assert.Equal(t, fiber.StatusPermanentRedirect, resp.StatusCode)
+			assert.Equal(t, tt.expectedLocation, resp.Header.Get(fiber.HeaderLocation))
+		})
+	}
+}
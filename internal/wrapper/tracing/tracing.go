@@ -0,0 +1,199 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"go-fiber-api/internal/core/config"
+	"go-fiber-api/internal/core/lifecycle"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/go-resty/resty/v2"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+const (
+	ExporterNone   = "none"
+	ExporterOTLP   = "otlp"
+	ExporterStdout = "stdout"
+	ExporterFile   = "file"
+
+	instrumentationName = "go-fiber-api"
+)
+
+// Tracing owns the tracer provider and the W3C trace context propagator of
+// the application. Nothing is registered globally, so every component gets
+// them from here. Like metrics, a nil *Tracing is valid and traces nothing.
+type Tracing struct {
+	provider   trace.TracerProvider
+	tracer     trace.Tracer
+	propagator propagation.TextMapPropagator
+	shutdown   func(ctx context.Context) error
+}
+
+// Provide builds the tracing of the application, flushing pending spans
+// once lc stops.
+func Provide(cfg *config.Configuration, lc *lifecycle.Lifecycle) (*Tracing, error) {
+	t, err := New(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	lc.Append(lifecycle.Hook{
+		Name:   "tracing",
+		OnStop: t.Shutdown,
+	})
+
+	return t, nil
+}
+
+// New builds the tracer provider for the configured exporter. With the "none"
+// exporter and no opts, spans are not recorded at all; opts are mostly there
+// for tests to attach their own span processor.
+func New(cfg *config.Configuration, opts ...sdktrace.TracerProviderOption) (*Tracing, error) {
+	tr := &Tracing{
+		propagator: propagation.NewCompositeTextMapPropagator(
+			propagation.TraceContext{},
+			propagation.Baggage{},
+		),
+		shutdown: func(context.Context) error { return nil },
+	}
+
+	exporter, closeExporter, err := newExporter(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	if exporter == nil && len(opts) == 0 {
+		tr.provider = noop.NewTracerProvider()
+		tr.tracer = tr.provider.Tracer(instrumentationName)
+		return tr, nil
+	}
+
+	opts = append([]sdktrace.TracerProviderOption{
+		sdktrace.WithResource(resource.NewSchemaless(semconv.ServiceName(cfg.TracingServiceName))),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.TracingSampleRatio))),
+	}, opts...)
+	if exporter != nil {
+		opts = append(opts, sdktrace.WithBatcher(exporter))
+	}
+
+	provider := sdktrace.NewTracerProvider(opts...)
+	tr.provider = provider
+	tr.tracer = provider.Tracer(instrumentationName)
+	tr.shutdown = func(ctx context.Context) error {
+		err := provider.Shutdown(ctx)
+		if cerr := closeExporter(); err == nil {
+			err = cerr
+		}
+
+		return err
+	}
+
+	return tr, nil
+}
+
+func newExporter(cfg *config.Configuration) (sdktrace.SpanExporter, func() error, error) {
+	noClose := func() error { return nil }
+
+	switch cfg.TracingExporter {
+	case "", ExporterNone:
+		return nil, noClose, nil
+	case ExporterOTLP:
+		opts := []otlptracehttp.Option{otlptracehttp.WithEndpoint(cfg.TracingOTLPEndpoint)}
+		if cfg.TracingOTLPInsecure {
+			opts = append(opts, otlptracehttp.WithInsecure())
+		}
+
+		exporter, err := otlptracehttp.New(context.Background(), opts...)
+		if err != nil {
+			return nil, nil, fmt.Errorf("create otlp exporter failed: %v", err)
+		}
+
+		return exporter, noClose, nil
+	case ExporterStdout:
+		exporter, err := stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+		if err != nil {
+			return nil, nil, fmt.Errorf("create stdout exporter failed: %v", err)
+		}
+
+		return exporter, noClose, nil
+	case ExporterFile:
+		if err := os.MkdirAll(filepath.Dir(cfg.TracingFilePath), 0o755); err != nil {
+			return nil, nil, fmt.Errorf("create trace file dir failed: %v", err)
+		}
+
+		f, err := os.OpenFile(cfg.TracingFilePath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		if err != nil {
+			return nil, nil, fmt.Errorf("open trace file failed: %v", err)
+		}
+
+		exporter, err := stdouttrace.New(stdouttrace.WithWriter(f))
+		if err != nil {
+			_ = f.Close()
+			return nil, nil, fmt.Errorf("create file exporter failed: %v", err)
+		}
+
+		return exporter, f.Close, nil
+	default:
+		return nil, nil, fmt.Errorf("unknown tracing exporter %q", cfg.TracingExporter)
+	}
+}
+
+func (t *Tracing) Tracer() trace.Tracer {
+	if t == nil {
+		return noop.NewTracerProvider().Tracer(instrumentationName)
+	}
+
+	return t.tracer
+}
+
+func (t *Tracing) Propagator() propagation.TextMapPropagator {
+	if t == nil {
+		return propagation.NewCompositeTextMapPropagator()
+	}
+
+	return t.propagator
+}
+
+// Start starts a span as a child of the span in ctx, if any.
+func (t *Tracing) Start(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	return t.Tracer().Start(ctx, name, opts...)
+}
+
+// InstrumentResty records a client span for every request of client and
+// propagates the trace context to the callee. Requests must be given the
+// request context with SetContext to join its trace.
+func (t *Tracing) InstrumentResty(client *resty.Client) {
+	if t == nil || client == nil {
+		return
+	}
+
+	base := client.GetClient().Transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	client.SetTransport(otelhttp.NewTransport(base,
+		otelhttp.WithTracerProvider(t.provider),
+		otelhttp.WithPropagators(t.propagator),
+	))
+}
+
+// Shutdown flushes the pending spans and closes the exporter.
+func (t *Tracing) Shutdown(ctx context.Context) error {
+	if t == nil {
+		return nil
+	}
+
+	return t.shutdown(ctx)
+}
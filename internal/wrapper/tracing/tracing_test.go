@@ -0,0 +1,127 @@
+package tracing_test
+
+import (
+	"context"
+	"go-fiber-api/internal/core/config"
+	"go-fiber-api/internal/wrapper/tracing"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/stretchr/testify/assert"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name          string
+		cfg           *config.Configuration
+		expectedError string
+		recording     bool
+	}{
+		{
+			name:      "when_exporter_is_none_should_not_record",
+			cfg:       &config.Configuration{TracingExporter: tracing.ExporterNone},
+			recording: false,
+		},
+		{
+			name:          "when_exporter_is_unknown_should_return_error",
+			cfg:           &config.Configuration{TracingExporter: "zipkin"},
+			expectedError: `unknown tracing exporter "zipkin"`,
+		},
+		{
+			name: "when_exporter_is_stdout_should_record",
+			cfg: &config.Configuration{
+				TracingExporter:    tracing.ExporterStdout,
+				TracingSampleRatio: 1,
+			},
+			recording: true,
+		},
+		{
+			name: "when_sample_ratio_is_zero_should_not_record",
+			cfg: &config.Configuration{
+				TracingExporter:    tracing.ExporterStdout,
+				TracingSampleRatio: 0,
+			},
+			recording: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tr, err := tracing.New(tt.cfg)
+			if tt.expectedError != "" {
+				assert.EqualError(t, err, tt.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			defer tr.Shutdown(context.Background())
+
+			_, span := tr.Start(context.Background(), "test")
+			defer span.End()
+
+			assert.Equal(t, tt.recording, span.IsRecording())
+		})
+	}
+}
+
+func TestNew_File(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "traces", "traces.json")
+	tr, err := tracing.New(&config.Configuration{
+		TracingExporter:    tracing.ExporterFile,
+		TracingSampleRatio: 1,
+		TracingFilePath:    path,
+	})
+	assert.NoError(t, err)
+
+	_, span := tr.Start(context.Background(), "file-span")
+	span.End()
+	assert.NoError(t, tr.Shutdown(context.Background()))
+
+	data, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), `"Name":"file-span"`)
+}
+
+func TestTracing_InstrumentResty(t *testing.T) {
+	var traceparent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparent = r.Header.Get("traceparent")
+	}))
+	defer server.Close()
+
+	recorder := tracetest.NewSpanRecorder()
+	tr, err := tracing.New(&config.Configuration{TracingSampleRatio: 1}, sdktrace.WithSpanProcessor(recorder))
+	assert.NoError(t, err)
+
+	client := resty.New()
+	tr.InstrumentResty(client)
+
+	ctx, parent := tr.Start(context.Background(), "parent")
+	_, err = client.R().SetContext(ctx).Get(server.URL)
+	parent.End()
+	assert.NoError(t, err)
+
+	spans := recorder.Ended()
+	assert.Len(t, spans, 2)
+
+	outbound := spans[0]
+	assert.Equal(t, parent.SpanContext().TraceID(), outbound.SpanContext().TraceID())
+	assert.Equal(t, parent.SpanContext().SpanID(), outbound.Parent().SpanID())
+	assert.Contains(t, traceparent, outbound.SpanContext().TraceID().String())
+}
+
+func TestTracing_Nil(t *testing.T) {
+	var tr *tracing.Tracing
+
+	assert.NotPanics(t, func() {
+		_, span := tr.Start(context.Background(), "test")
+		span.End()
+		tr.InstrumentResty(resty.New())
+		assert.NoError(t, tr.Shutdown(context.Background()))
+	})
+}
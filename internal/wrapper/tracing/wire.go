@@ -0,0 +1,7 @@
+package tracing
+
+import "github.com/google/wire"
+
+var ProviderSet = wire.NewSet(
+	Provide,
+)
@@ -0,0 +1,131 @@
+// Package apperror holds the errors told to the clients of the API. Each one
+// has a stable code clients can rely on, unlike its message, and may wrap a
+// cause that is logged but never shown to them.
+package apperror
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+	"gorm.io/gorm"
+)
+
+// Codes of the errors without a more specific one.
+const (
+	CodeNotFound     = "NOT_FOUND"
+	CodeConflict     = "CONFLICT"
+	CodeValidation   = "VALIDATION_FAILED"
+	CodeUnauthorized = "UNAUTHORIZED"
+	CodeForbidden    = "FORBIDDEN"
+	CodeRateLimited  = "RATE_LIMITED"
+	CodeInternal     = "INTERNAL"
+)
+
+// FieldError is an invalid field of a request.
+type FieldError struct {
+	Field string `json:"failedFields"`
+	Tag   string `json:"tag"`
+	Value string `json:"value"`
+}
+
+// Error is an error of the application rendered with Status, Code and
+// Message. Two errors are the same to errors.Is when their codes are.
+type Error struct {
+	Status  int
+	Code    string
+	Message string
+
+	Fields     []FieldError  // of a validation error
+	RetryAfter time.Duration // of a rate limited error, 0 when unknown
+
+	cause error
+}
+
+func (e *Error) Error() string {
+	if e.cause != nil {
+		return e.Message + ": " + e.cause.Error()
+	}
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.cause
+}
+
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Code == e.Code
+}
+
+// RetryAfterSeconds is the Retry-After header value of e, in whole seconds.
+func (e *Error) RetryAfterSeconds() int {
+	return int(math.Ceil(e.RetryAfter.Seconds()))
+}
+
+// Wrap returns a copy of e caused by err.
+func (e *Error) Wrap(err error) *Error {
+	c := *e
+	c.cause = err
+	return &c
+}
+
+// NotFound is an error of a missing resource.
+func NotFound(code, message string) *Error {
+	return &Error{Status: fiber.StatusNotFound, Code: code, Message: message}
+}
+
+// Conflict is an error of a resource clashing with another one.
+func Conflict(code, message string) *Error {
+	return &Error{Status: fiber.StatusConflict, Code: code, Message: message}
+}
+
+// Validation is an error of an invalid request, fields naming its invalid
+// fields when known.
+func Validation(code, message string, fields ...FieldError) *Error {
+	return &Error{Status: fiber.StatusBadRequest, Code: code, Message: message, Fields: fields}
+}
+
+// Unauthorized is an error of a request without valid credentials.
+func Unauthorized(code, message string) *Error {
+	return &Error{Status: fiber.StatusUnauthorized, Code: code, Message: message}
+}
+
+// Forbidden is an error of a request its caller isn't allowed.
+func Forbidden(code, message string) *Error {
+	return &Error{Status: fiber.StatusForbidden, Code: code, Message: message}
+}
+
+// RateLimited is an error of a request refused until retryAfter has elapsed.
+func RateLimited(code, message string, retryAfter time.Duration) *Error {
+	return &Error{Status: fiber.StatusTooManyRequests, Code: code, Message: message, RetryAfter: retryAfter}
+}
+
+// Internal is an unexpected error, its cause kept from clients.
+func Internal(err error) *Error {
+	return (&Error{Status: fiber.StatusInternalServerError, Code: CodeInternal, Message: utils.StatusMessage(fiber.StatusInternalServerError)}).Wrap(err)
+}
+
+// From returns the non-nil err as an *Error: itself when it is one, a not
+// found or conflict error for the errors of a missing record or of a unique
+// violation, and an internal error otherwise. Unique violations are reported
+// by gorm with TranslateError. A *fiber.Error keeps its status as code.
+func From(err error) *Error {
+	var appErr *Error
+	var fiberErr *fiber.Error
+	switch {
+	case errors.As(err, &appErr):
+		return appErr
+	case errors.As(err, &fiberErr):
+		return &Error{Status: fiberErr.Code, Code: strconv.Itoa(fiberErr.Code), Message: fiberErr.Message}
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return NotFound(CodeNotFound, "not found").Wrap(err)
+	case errors.Is(err, gorm.ErrDuplicatedKey):
+		return Conflict(CodeConflict, "already exists").Wrap(err)
+	default:
+		return Internal(err)
+	}
+}
@@ -0,0 +1,95 @@
+package apperror_test
+
+import (
+	"errors"
+	"fmt"
+	"go-fiber-api/internal/core/storage/db"
+	"go-fiber-api/toolkit/apperror"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+var errRoleExists = apperror.Conflict("ROLE_EXISTS", "role already exists")
+
+type item struct {
+	gorm.Model
+	Name string `gorm:"uniqueIndex"`
+}
+
+func TestFrom(t *testing.T) {
+	client, err := db.GetDbTestMode()
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	assert.NoError(t, client.AutoMigrate(&item{}))
+	assert.NoError(t, client.Create(&item{Name: "a"}).Error)
+
+	tests := []struct {
+		name           string
+		err            error
+		expectedStatus int
+		expectedCode   string
+		expectedMsg    string
+	}{
+		{
+			name:           "when_error_is_app_error_should_keep_it",
+			err:            fmt.Errorf("create role: %w", errRoleExists),
+			expectedStatus: fiber.StatusConflict,
+			expectedCode:   "ROLE_EXISTS",
+			expectedMsg:    "role already exists",
+		},
+		{
+			name:           "when_record_is_not_found_should_get_not_found",
+			err:            client.Where("name = ?", "b").First(&item{}).Error,
+			expectedStatus: fiber.StatusNotFound,
+			expectedCode:   apperror.CodeNotFound,
+			expectedMsg:    "not found",
+		},
+		{
+			name:           "when_unique_index_is_violated_should_get_conflict",
+			err:            client.Create(&item{Name: "a"}).Error,
+			expectedStatus: fiber.StatusConflict,
+			expectedCode:   apperror.CodeConflict,
+			expectedMsg:    "already exists",
+		},
+		{
+			name:           "when_error_is_fiber_error_should_keep_its_status_as_code",
+			err:            fiber.ErrMethodNotAllowed,
+			expectedStatus: fiber.StatusMethodNotAllowed,
+			expectedCode:   "405",
+			expectedMsg:    "Method Not Allowed",
+		},
+		{
+			name:           "when_error_is_unknown_should_get_internal",
+			err:            errors.New("dial tcp: connection refused"),
+			expectedStatus: fiber.StatusInternalServerError,
+			expectedCode:   apperror.CodeInternal,
+			expectedMsg:    "Internal Server Error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// test logic
+			e := apperror.From(tt.err)
+			assert.Equal(t, tt.expectedStatus, e.Status)
+			assert.Equal(t, tt.expectedCode, e.Code)
+			assert.Equal(t, tt.expectedMsg, e.Message)
+		})
+	}
+}
+
+func TestError_Is(t *testing.T) {
+	cause := errors.New("UNIQUE constraint failed: roles.name")
+
+	// test logic
+	err := fmt.Errorf("save role: %w", errRoleExists.Wrap(cause))
+
+	assert.ErrorIs(t, err, errRoleExists, "copies are the same error")
+	assert.ErrorIs(t, err, cause)
+	assert.NotErrorIs(t, err, apperror.Conflict(apperror.CodeConflict, "role already exists"))
+	assert.EqualError(t, err, "save role: role already exists: UNIQUE constraint failed: roles.name")
+}
@@ -1,31 +1,60 @@
 package errorhandler
 
 import (
-	"fmt"
+	"go-fiber-api/toolkit/apperror"
+	"strconv"
 
 	"github.com/gofiber/fiber/v2"
-	"github.com/gofiber/fiber/v2/utils"
 )
 
-func Handler() fiber.ErrorHandler {
+type Config struct {
+	// DevMode shows the causes of errors to clients, hidden in production.
+	DevMode bool
+	// ProblemTypeBaseURL is the base of the type URIs of problems, see
+	// Problem.
+	ProblemTypeBaseURL string
+}
+
+// Handler renders errors as their *apperror.Error, see apperror.From. Clients
+// accepting application/problem+json over application/json get a Problem,
+// the others the legacy {message, code, ok} body.
+func Handler(config ...Config) fiber.ErrorHandler {
+	var cfg Config
+	if len(config) > 0 {
+		cfg = config[0]
+	}
+
 	return func(ctx *fiber.Ctx, err error) error {
-		// Default to 500
-		code := "0000"
-		status := fiber.StatusInternalServerError
-		msg := utils.StatusMessage(status)
-
-		if e, ok := err.(*fiber.Error); ok {
-			status = e.Code
-			code = fmt.Sprint(e.Code)
-			msg = e.Message
+		e := apperror.From(err)
+
+		msg := e.Message
+		if cfg.DevMode && e.Unwrap() != nil {
+			msg = e.Error()
+		}
+
+		if e.RetryAfter > 0 {
+			ctx.Set(fiber.HeaderRetryAfter, strconv.Itoa(e.RetryAfterSeconds()))
+		}
+
+		// The body depends on the Accept header of the request.
+		ctx.Vary(fiber.HeaderAccept)
+		if ctx.Accepts(fiber.MIMEApplicationJSON, MIMEApplicationProblemJSON) == MIMEApplicationProblemJSON {
+			return ctx.
+				Status(e.Status).
+				JSON(newProblem(ctx, cfg, e, msg), MIMEApplicationProblemJSON)
+		}
+
+		body := fiber.Map{
+			"message": msg,
+			"code":    e.Code,
+			"ok":      false,
+		}
+		if len(e.Fields) > 0 {
+			body["data"] = e.Fields
 		}
 
 		return ctx.
-			Status(status).
-			JSON(fiber.Map{
-				"message": msg,
-				"code":    code,
-				"ok":      false,
-			})
+			Status(e.Status).
+			JSON(body)
 	}
 }
@@ -0,0 +1,174 @@
+package errorhandler_test
+
+import (
+	"errors"
+	"go-fiber-api/toolkit/apperror"
+	"go-fiber-api/toolkit/errorhandler"
+	"io"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/requestid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler(t *testing.T) {
+	tests := []struct {
+		name           string
+		cfg            errorhandler.Config
+		err            error
+		expectedStatus int
+		expectedBody   string
+		expectedRetry  string
+	}{
+		{
+			name:           "when_error_is_app_error_should_render_its_code",
+			err:            apperror.Forbidden("USER_LOCKED", "user is locked"),
+			expectedStatus: fiber.StatusForbidden,
+			expectedBody:   `{"code":"USER_LOCKED","message":"user is locked","ok":false}`,
+		},
+		{
+			name:           "when_error_is_validation_should_render_fields",
+			err:            apperror.Validation(apperror.CodeValidation, "invalid request", apperror.FieldError{Field: "LoginDTO.Username", Tag: "required"}),
+			expectedStatus: fiber.StatusBadRequest,
+			expectedBody:   `{"code":"VALIDATION_FAILED","data":[{"failedFields":"LoginDTO.Username","tag":"required","value":""}],"message":"invalid request","ok":false}`,
+		},
+		{
+			name:           "when_error_is_rate_limited_should_set_retry_after",
+			err:            apperror.RateLimited(apperror.CodeRateLimited, "slow down", 2500*time.Millisecond),
+			expectedStatus: fiber.StatusTooManyRequests,
+			expectedBody:   `{"code":"RATE_LIMITED","message":"slow down","ok":false}`,
+			expectedRetry:  "3",
+		},
+		{
+			name:           "when_error_is_fiber_error_should_render_its_status_as_code",
+			err:            fiber.ErrNotFound,
+			expectedStatus: fiber.StatusNotFound,
+			expectedBody:   `{"code":"404","message":"Not Found","ok":false}`,
+		},
+		{
+			name:           "when_error_is_unknown_should_hide_it",
+			err:            errors.New(`pq: relation "users" does not exist`),
+			expectedStatus: fiber.StatusInternalServerError,
+			expectedBody:   `{"code":"INTERNAL","message":"Internal Server Error","ok":false}`,
+		},
+		{
+			name:           "when_cause_is_wrapped_should_hide_it",
+			err:            apperror.Conflict("ROLE_EXISTS", "role already exists").Wrap(errors.New("UNIQUE constraint failed: roles.name")),
+			expectedStatus: fiber.StatusConflict,
+			expectedBody:   `{"code":"ROLE_EXISTS","message":"role already exists","ok":false}`,
+		},
+		{
+			name:           "when_dev_mode_should_show_cause",
+			cfg:            errorhandler.Config{DevMode: true},
+			err:            errors.New(`pq: relation "users" does not exist`),
+			expectedStatus: fiber.StatusInternalServerError,
+			expectedBody:   `{"code":"INTERNAL","message":"Internal Server Error: pq: relation \"users\" does not exist","ok":false}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := fiber.New(fiber.Config{ErrorHandler: errorhandler.Handler(tt.cfg)})
+			app.Get("/", func(c *fiber.Ctx) error {
+				return tt.err
+			})
+
+			// test logic
+			resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/", nil))
+			assert.NoError(t, err)
+			body, _ := io.ReadAll(resp.Body)
+
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+			assert.Equal(t, tt.expectedBody, string(body))
+			assert.Equal(t, tt.expectedRetry, resp.Header.Get(fiber.HeaderRetryAfter))
+		})
+	}
+}
+
+func TestHandler_Problem(t *testing.T) {
+	tests := []struct {
+		name                string
+		cfg                 errorhandler.Config
+		accept              string
+		err                 error
+		expectedStatus      int
+		expectedContentType string
+		expectedBody        string
+	}{
+		{
+			name:                "when_problem_is_accepted_should_render_it",
+			accept:              "application/problem+json",
+			err:                 apperror.Forbidden("USER_LOCKED", "user is locked"),
+			expectedStatus:      fiber.StatusForbidden,
+			expectedContentType: errorhandler.MIMEApplicationProblemJSON,
+			expectedBody:        `{"type":"about:blank","title":"Forbidden","status":403,"detail":"user is locked","instance":"test-request-id","code":"USER_LOCKED"}`,
+		},
+		{
+			name:                "when_type_base_is_set_should_type_problem_by_code",
+			cfg:                 errorhandler.Config{ProblemTypeBaseURL: "https://docs.example.com/errors/"},
+			accept:              "application/problem+json, application/json;q=0.5",
+			err:                 apperror.Forbidden("USER_LOCKED", "user is locked"),
+			expectedStatus:      fiber.StatusForbidden,
+			expectedContentType: errorhandler.MIMEApplicationProblemJSON,
+			expectedBody:        `{"type":"https://docs.example.com/errors/user-locked","title":"Forbidden","status":403,"detail":"user is locked","instance":"test-request-id","code":"USER_LOCKED"}`,
+		},
+		{
+			name:                "when_validation_fails_should_list_field_errors",
+			accept:              "application/problem+json",
+			err:                 apperror.Validation(apperror.CodeValidation, "invalid request", apperror.FieldError{Field: "RegisterDTO.Password", Tag: "min", Value: "8"}),
+			expectedStatus:      fiber.StatusBadRequest,
+			expectedContentType: errorhandler.MIMEApplicationProblemJSON,
+			expectedBody:        `{"type":"about:blank","title":"Bad Request","status":400,"detail":"invalid request","instance":"test-request-id","code":"VALIDATION_FAILED","errors":[{"field":"RegisterDTO.Password","tag":"min","param":"8"}]}`,
+		},
+		{
+			name:                "when_error_is_internal_should_hide_it",
+			accept:              "application/problem+json",
+			err:                 errors.New(`pq: relation "users" does not exist`),
+			expectedStatus:      fiber.StatusInternalServerError,
+			expectedContentType: errorhandler.MIMEApplicationProblemJSON,
+			expectedBody:        `{"type":"about:blank","title":"Internal Server Error","status":500,"instance":"test-request-id","code":"INTERNAL"}`,
+		},
+		{
+			name:                "when_json_is_preferred_should_render_legacy_body",
+			accept:              "application/json, application/problem+json;q=0.5",
+			err:                 apperror.Forbidden("USER_LOCKED", "user is locked"),
+			expectedStatus:      fiber.StatusForbidden,
+			expectedContentType: fiber.MIMEApplicationJSON,
+			expectedBody:        `{"code":"USER_LOCKED","message":"user is locked","ok":false}`,
+		},
+		{
+			name:                "when_anything_is_accepted_should_render_legacy_body",
+			accept:              "*/*",
+			err:                 apperror.Forbidden("USER_LOCKED", "user is locked"),
+			expectedStatus:      fiber.StatusForbidden,
+			expectedContentType: fiber.MIMEApplicationJSON,
+			expectedBody:        `{"code":"USER_LOCKED","message":"user is locked","ok":false}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := fiber.New(fiber.Config{ErrorHandler: errorhandler.Handler(tt.cfg)})
+			app.Use(requestid.New(requestid.Config{Generator: func() string { return "test-request-id" }}))
+			app.Get("/", func(c *fiber.Ctx) error {
+				return tt.err
+			})
+
+			req := httptest.NewRequest(fiber.MethodGet, "/", nil)
+			req.Header.Set(fiber.HeaderAccept, tt.accept)
+
+			// test logic
+			resp, err := app.Test(req)
+			assert.NoError(t, err)
+			body, _ := io.ReadAll(resp.Body)
+
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+			assert.Equal(t, tt.expectedContentType, resp.Header.Get(fiber.HeaderContentType))
+			assert.Equal(t, fiber.HeaderAccept, resp.Header.Get(fiber.HeaderVary))
+			assert.Equal(t, tt.expectedBody, string(body))
+		})
+	}
+}
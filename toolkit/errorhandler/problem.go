@@ -0,0 +1,56 @@
+package errorhandler
+
+import (
+	"go-fiber-api/toolkit/apperror"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+)
+
+const MIMEApplicationProblemJSON = "application/problem+json"
+
+// Problem is an error as RFC 7807 problem details.
+type Problem struct {
+	// Type is ProblemTypeBaseURL followed by the code in kebab case, e.g.
+	// https://docs.example.com/errors/user-locked, or about:blank without a
+	// base.
+	Type   string `json:"type"`
+	Title  string `json:"title"` // of the status
+	Status int    `json:"status"`
+	Detail string `json:"detail,omitempty"`
+	// Instance is the request ID, to find the logs of the request.
+	Instance string `json:"instance,omitempty"`
+
+	Code   string         `json:"code"`
+	Errors []ProblemField `json:"errors,omitempty"` // of a validation error
+}
+
+// ProblemField is an invalid field of a request.
+type ProblemField struct {
+	Field string `json:"field"`
+	Tag   string `json:"tag"`             // of the failed rule, e.g. required or min
+	Param string `json:"param,omitempty"` // of the rule, e.g. 8 for min
+}
+
+func newProblem(ctx *fiber.Ctx, cfg Config, e *apperror.Error, detail string) Problem {
+	p := Problem{
+		Type:     "about:blank",
+		Title:    utils.StatusMessage(e.Status),
+		Status:   e.Status,
+		Instance: ctx.GetRespHeader(fiber.HeaderXRequestID),
+		Code:     e.Code,
+	}
+	if detail != p.Title {
+		p.Detail = detail
+	}
+	if cfg.ProblemTypeBaseURL != "" {
+		p.Type = strings.TrimSuffix(cfg.ProblemTypeBaseURL, "/") + "/" + strings.ToLower(strings.ReplaceAll(e.Code, "_", "-"))
+	}
+
+	for _, f := range e.Fields {
+		p.Errors = append(p.Errors, ProblemField{Field: f.Field, Tag: f.Tag, Param: f.Value})
+	}
+
+	return p
+}
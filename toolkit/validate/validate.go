@@ -2,6 +2,8 @@ package validate
 
 import (
 	"fmt"
+	"go-fiber-api/toolkit/apperror"
+
 	"github.com/go-playground/validator/v10"
 )
 
@@ -30,3 +32,18 @@ func Validate(v any) []*ErrorResponse {
 	}
 	return errors
 }
+
+// Struct returns an apperror.Validation error naming the invalid fields of v,
+// nil when it is valid.
+func Struct(v any) error {
+	errs := Validate(v)
+	if len(errs) == 0 {
+		return nil
+	}
+
+	fields := make([]apperror.FieldError, len(errs))
+	for i, e := range errs {
+		fields[i] = apperror.FieldError{Field: e.FailedField, Tag: e.Tag, Value: e.Value}
+	}
+	return apperror.Validation(apperror.CodeValidation, "invalid request", fields...)
+}